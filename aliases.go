@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gookit/color"
+
+	"shell-analyzer/analyzer"
+)
+
+func renderAliasExpansions(expansions map[string]map[string]analyzer.AliasExpansion) string {
+	var content strings.Builder
+	content.WriteString("🔁 Alias Expansion:\n")
+	if len(expansions) == 0 {
+		content.WriteString("No aliases were expanded during analysis\n")
+		return content.String()
+	}
+	content.WriteString("Commands starting with an alias are counted as their expansion,\n")
+	content.WriteString("so totals may differ from `history | sort | uniq -c`.\n\n")
+
+	var shells []string
+	for shell := range expansions {
+		shells = append(shells, shell)
+	}
+	sort.Strings(shells)
+
+	for _, shell := range shells {
+		var sorted []analyzer.AliasExpansion
+		for _, expansion := range expansions[shell] {
+			sorted = append(sorted, expansion)
+		}
+		sort.Slice(sorted, func(i, j int) bool {
+			if sorted[i].Hits != sorted[j].Hits {
+				return sorted[i].Hits > sorted[j].Hits
+			}
+			return sorted[i].Alias < sorted[j].Alias
+		})
+
+		content.WriteString(fmt.Sprintf("Shell: %s\n", color.Cyan.Sprint(shell)))
+		for _, expansion := range sorted {
+			note := ""
+			if expansion.Truncated {
+				note = " (stopped at depth limit or cycle)"
+			}
+			content.WriteString(fmt.Sprintf("• %s → %s (%d hits)%s\n",
+				color.Yellow.Sprint(expansion.Alias),
+				expansion.Expansion,
+				expansion.Hits,
+				note))
+		}
+		content.WriteString("\n")
+	}
+
+	return content.String()
+}
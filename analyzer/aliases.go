@@ -0,0 +1,290 @@
+package analyzer
+
+import "strings"
+
+// AliasExpansion records how often an alias was expanded during analysis
+type AliasExpansion struct {
+	Alias     string
+	Expansion string
+	Hits      int
+	Truncated bool
+}
+
+// AliasDefinition is one place an alias is defined
+type AliasDefinition struct {
+	File  string
+	Line  int
+	Value string
+}
+
+// Expand a leading alias in cmd, following alias chains up to depth.
+// Returns the expanded command, the aliases applied in order, and whether
+// expansion stopped early because of a cycle or the depth limit.
+func expandAlias(cmd string, aliases map[string]string, depth int) (string, []string, bool) {
+	var applied []string
+	seen := make(map[string]bool)
+
+	for i := 0; i < depth; i++ {
+		fields := strings.Fields(cmd)
+		if len(fields) == 0 {
+			return cmd, applied, false
+		}
+
+		name := fields[0]
+		value, ok := aliases[name]
+		if !ok {
+			return cmd, applied, false
+		}
+		if seen[name] {
+			return cmd, applied, true
+		}
+		seen[name] = true
+		applied = append(applied, name)
+
+		rest := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(cmd), name))
+		cmd = strings.TrimSpace(value + " " + rest)
+
+		// Like the shell, don't re-expand an alias that starts with its own name (ls='ls -G')
+		if valueFields := strings.Fields(value); len(valueFields) > 0 && valueFields[0] == name {
+			return cmd, applied, false
+		}
+	}
+
+	// Out of depth: only truncated if there is still an alias left to expand
+	if fields := strings.Fields(cmd); len(fields) > 0 && depth > 0 {
+		if _, ok := aliases[fields[0]]; ok {
+			return cmd, applied, true
+		}
+	}
+	return cmd, applied, false
+}
+
+// The aliases expanded in one simple command, in order, and whether
+// expansion stopped early
+type aliasChain struct {
+	applied   []string
+	truncated bool
+}
+
+// Expand the alias of every simple command in a command line, not just the
+// first: each command after ;, &&, ||, |, & or a newline, and inside
+// groups, is expanded like expandAlias does. Quotes, substitutions and
+// heredoc bodies are left as they are.
+func expandLineAliases(line string, aliases map[string]string, depth int) (string, []aliasChain) {
+	var expanded strings.Builder
+	var chains []aliasChain
+	for i := 0; i < len(line); {
+		gap := commandGap(line, i)
+		expanded.WriteString(line[i : i+gap])
+		i += gap
+		if i == len(line) {
+			break
+		}
+
+		end, heredocs := simpleCommandEnd(line, i)
+		command := line[i:end]
+		trimmed := strings.TrimRight(command, " \t")
+		if cmd, applied, truncated := expandAlias(trimmed, aliases, depth); len(applied) > 0 {
+			chains = append(chains, aliasChain{applied, truncated})
+			command = cmd + command[len(trimmed):]
+		}
+		expanded.WriteString(command)
+		i = end
+
+		if len(heredocs) > 0 && i < len(line) && line[i] == '\n' {
+			bodies := heredocBodiesEnd(line, i, heredocs)
+			expanded.WriteString(line[i:bodies])
+			i = bodies
+		}
+	}
+	return expanded.String(), chains
+}
+
+// Expand aliases across a history, recording each expansion in data
+func expandAliases(shell string, entries []CommandEntry, aliases map[string]string, depth int, categories Categorizer, data *ShellData) []CommandEntry {
+	if depth <= 0 || len(aliases) == 0 {
+		return entries
+	}
+
+	expansions := data.AliasExpansions[shell]
+	if expansions == nil {
+		expansions = make(map[string]AliasExpansion)
+	}
+
+	expanded := make([]CommandEntry, len(entries))
+	for i, entry := range entries {
+		entry, chains := expandEntryAliases(entry, aliases, depth, categories)
+		for _, chain := range chains {
+			for j, alias := range chain.applied {
+				expansion := expansions[alias]
+				expansion.Alias = alias
+				expansion.Expansion = aliases[alias]
+				expansion.Hits++
+				if chain.truncated && j == 0 {
+					expansion.Truncated = true
+				}
+				expansions[alias] = expansion
+			}
+		}
+		expanded[i] = entry
+	}
+
+	if len(expansions) > 0 {
+		data.AliasExpansions[shell] = expansions
+	}
+	return expanded
+}
+
+// Expand the aliases of one entry, categorizing it by what it runs
+func expandEntryAliases(entry CommandEntry, aliases map[string]string, depth int, categories Categorizer) (CommandEntry, []aliasChain) {
+	cmd, chains := expandLineAliases(entry.Command, aliases, depth)
+	if len(chains) > 0 {
+		entry.Command = cmd
+		entry.Categories = categories.commandCategories(cmd)
+	}
+	return entry, chains
+}
+
+// A name given a value in a shell config, like an alias or a variable
+type configAssignment struct {
+	name  string
+	value string
+}
+
+// Options of fish's abbr that take a value as the next word
+var abbrOptionArgs = map[string]bool{"-p": true, "--position": true, "-r": true, "--regex": true}
+
+// Options of fish's abbr that manage abbreviations rather than define one
+var abbrQueries = map[string]bool{
+	"-e": true, "--erase": true, "-l": true, "--list": true, "-s": true, "--show": true,
+	"-q": true, "--query": true, "--rename": true, "-h": true, "--help": true,
+	"-f": true, "--function": true,
+}
+
+// Split shell code into words, undoing quotes and backslash escapes the
+// way the shell does. Only the first simple command is read, up to an
+// unquoted ;, & or | or a comment. complete is false when a quote is
+// still open or the code ends with a backslash, meaning the statement
+// goes on on the next line.
+func ShellWords(code string) (words []string, complete bool) {
+	var word strings.Builder
+	inWord := false
+	for i := 0; i < len(code); i++ {
+		c := code[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		case c == '#' && !inWord:
+			return words, true
+		case c == ';' || c == '&' || c == '|':
+			if inWord {
+				words = append(words, word.String())
+			}
+			return words, true
+		case c == '\\':
+			if i+1 == len(code) {
+				return words, false
+			}
+			i++
+			// A backslash before a newline only joins the lines
+			if code[i] != '\n' {
+				word.WriteByte(code[i])
+				inWord = true
+			}
+		case c == '\'':
+			end := strings.IndexByte(code[i+1:], '\'')
+			if end < 0 {
+				return words, false
+			}
+			word.WriteString(code[i+1 : i+1+end])
+			inWord = true
+			i += end + 1
+		case c == '"':
+			closed := false
+			for i++; i < len(code); i++ {
+				if code[i] == '"' {
+					closed = true
+					break
+				}
+				if code[i] == '\\' && i+1 < len(code) && strings.IndexByte("\"\\$`\n", code[i+1]) >= 0 {
+					i++
+					if code[i] == '\n' {
+						continue
+					}
+				}
+				word.WriteByte(code[i])
+			}
+			if !closed {
+				return words, false
+			}
+			inWord = true
+		default:
+			word.WriteByte(c)
+			inWord = true
+		}
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, true
+}
+
+// Whether a config line starts an alias definition in shell
+func isAliasDefinition(shell, line string) bool {
+	fields := strings.Fields(line)
+	return len(fields) > 0 && (fields[0] == "alias" || (shell == "fish" && fields[0] == "abbr"))
+}
+
+// The aliases an alias or abbr statement defines, from its words. bash and
+// zsh take name=value pairs after any options like zsh's -g; fish also
+// takes "alias name body..." and "abbr -a name expansion...". Statements
+// that only print aliases define nothing.
+func parseAliasDefinition(shell string, words []string) []configAssignment {
+	if len(words) < 2 {
+		return nil
+	}
+	command, args := words[0], words[1:]
+
+	var positional []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "-") || len(positional) > 0 {
+			positional = append(positional, arg)
+			continue
+		}
+		if command == "abbr" {
+			if abbrQueries[arg] {
+				return nil
+			}
+			if abbrOptionArgs[arg] {
+				i++
+			}
+		}
+	}
+
+	if shell == "fish" {
+		if len(positional) == 0 {
+			return nil
+		}
+		name, value, found := strings.Cut(positional[0], "=")
+		if !found {
+			value = strings.Join(positional[1:], " ")
+		}
+		if name == "" || value == "" {
+			return nil
+		}
+		return []configAssignment{{name, value}}
+	}
+
+	var assignments []configAssignment
+	for _, arg := range positional {
+		if name, value, found := strings.Cut(arg, "="); found && name != "" {
+			assignments = append(assignments, configAssignment{name, value})
+		}
+	}
+	return assignments
+}
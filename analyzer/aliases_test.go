@@ -0,0 +1,181 @@
+package analyzer
+
+import (
+	"maps"
+	"slices"
+	"testing"
+)
+
+var testAliases = map[string]string{
+	"gco": "git checkout",
+	"k":   "kubectl",
+	"kgp": "k get pods",
+	"ll":  "ls -la",
+	"ls":  "ls --color",
+	"a":   "b",
+	"b":   "a",
+}
+
+func TestExpandAlias(t *testing.T) {
+	tests := []struct {
+		cmd       string
+		depth     int
+		want      string
+		applied   []string
+		truncated bool
+	}{
+		{"gco main", 5, "git checkout main", []string{"gco"}, false},
+		{"kgp -A", 5, "kubectl get pods -A", []string{"kgp", "k"}, false},
+		{"ll /tmp", 5, "ls --color -la /tmp", []string{"ll", "ls"}, false},
+		{"ls", 5, "ls --color", []string{"ls"}, false},
+		{"git status", 5, "git status", nil, false},
+		{"kgp", 1, "k get pods", []string{"kgp"}, true},
+		{"a", 5, "a", []string{"a", "b"}, true},
+	}
+	for _, test := range tests {
+		got, applied, truncated := expandAlias(test.cmd, testAliases, test.depth)
+		if got != test.want || !slices.Equal(applied, test.applied) || truncated != test.truncated {
+			t.Errorf("expandAlias(%q, %d) = %q, %v, %v; want %q, %v, %v",
+				test.cmd, test.depth, got, applied, truncated, test.want, test.applied, test.truncated)
+		}
+	}
+}
+
+func TestExpandLineAliases(t *testing.T) {
+	tests := []struct {
+		line    string
+		want    string
+		applied []string
+	}{
+		{"gco main && k get pods", "git checkout main && kubectl get pods", []string{"gco", "k"}},
+		{"gco main; kgp", "git checkout main; kubectl get pods", []string{"gco", "kgp", "k"}},
+		{"k logs app | gco", "kubectl logs app | git checkout", []string{"k", "gco"}},
+		{"make || k describe pod x", "make || kubectl describe pod x", []string{"k"}},
+		{"sleep 5 & k top pods", "sleep 5 & kubectl top pods", []string{"k"}},
+		{"(gco dev && k apply -f .)", "(git checkout dev && kubectl apply -f .)", []string{"gco", "k"}},
+		{"{ gco dev; k get ns; }", "{ git checkout dev; kubectl get ns; }", []string{"gco", "k"}},
+		{"if k get ns; then gco main; fi", "if kubectl get ns; then git checkout main; fi", []string{"k", "gco"}},
+		{"gco main\nk get pods", "git checkout main\nkubectl get pods", []string{"gco", "k"}},
+
+		// Only command positions are expanded
+		{"echo k && git log", "echo k && git log", nil},
+		{`echo "a; k get" && k get`, `echo "a; k get" && kubectl get`, []string{"k"}},
+		{"echo $(k get pods) | k apply", "echo $(k get pods) | kubectl apply", []string{"k"}},
+		{"k get pods 2>&1 | grep x", "kubectl get pods 2>&1 | grep x", []string{"k"}},
+		{"cat <<EOF | k apply -f -\nk: v\nEOF\ngco x", "cat <<EOF | kubectl apply -f -\nk: v\nEOF\ngit checkout x", []string{"k", "gco"}},
+		{"", "", nil},
+	}
+	for _, test := range tests {
+		got, chains := expandLineAliases(test.line, testAliases, 5)
+		var applied []string
+		for _, chain := range chains {
+			applied = append(applied, chain.applied...)
+		}
+		if got != test.want || !slices.Equal(applied, test.applied) {
+			t.Errorf("expandLineAliases(%q) = %q, %v; want %q, %v", test.line, got, applied, test.want, test.applied)
+		}
+	}
+}
+
+func TestExpandAliasesCountsEachCommand(t *testing.T) {
+	data := NewShellData()
+	entries := []CommandEntry{
+		{Command: "gco main && k get pods"},
+		{Command: "k get svc | grep api"},
+		{Command: "git status"},
+	}
+	expanded := expandAliases("zsh", entries, testAliases, 5, DefaultCategories(), &data)
+
+	want := []string{"git checkout main && kubectl get pods", "kubectl get svc | grep api", "git status"}
+	for i, entry := range expanded {
+		if entry.Command != want[i] {
+			t.Errorf("entry %d = %q, want %q", i, entry.Command, want[i])
+		}
+	}
+	expansions := data.AliasExpansions["zsh"]
+	if expansions["k"].Hits != 2 || expansions["gco"].Hits != 1 {
+		t.Errorf("expansions = %+v, want k twice and gco once", expansions)
+	}
+	if categories := categoryNames(expanded[0].Categories); !slices.Equal(categories, []string{"version-control", "containers"}) {
+		t.Errorf("categories = %v, want version-control from git and containers from kubectl", categories)
+	}
+
+	// kubectl is counted for the commands after && and |
+	cfg := DefaultConfig()
+	cfg.TrackedTools = []string{"kubectl"}
+	analyzeCommands(expanded, &data, cfg, nil)
+	if tracked := data.Insights.ToolUsage.Tracked; len(tracked) != 1 || tracked[0].Count != 2 {
+		t.Errorf("Tracked = %+v, want kubectl twice", tracked)
+	}
+}
+
+func TestAliasedCommandsCountedExpanded(t *testing.T) {
+	home := writeTestHome(t, map[string]string{
+		".zshrc":       "alias k=kubectl\n",
+		".zsh_history": ": 1712912400:0;k get pods\n: 1712912460:0;k logs api\n: 1712912520:0;ls\n",
+	})
+	for _, keep := range []bool{true, false} {
+		data, err := Analyze(Options{HomeDir: home, Shells: []string{"zsh"}, NoProbe: true, KeepEntries: keep})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := map[string]int{"kubectl": 2, "ls": 1}; !maps.Equal(data.CommonCmds, want) {
+			t.Errorf("KeepEntries %v: CommonCmds = %v, want %v", keep, data.CommonCmds, want)
+		}
+		if data.CategoryTotals["containers"] != 2 {
+			t.Errorf("KeepEntries %v: CategoryTotals = %v, want containers for both k commands", keep, data.CategoryTotals)
+		}
+		if !keep {
+			continue
+		}
+
+		// Kept as typed, but categorized by what ran
+		entry := data.Histories["zsh"][0]
+		if categories := categoryNames(entry.Categories); entry.Command != "k get pods" || !slices.Equal(categories, []string{"containers"}) {
+			t.Errorf("Histories[zsh][0] = %q in %v, want k get pods in containers", entry.Command, categories)
+		}
+	}
+}
+
+// An empty ShellConfig ready for parseShellConfig
+func newTestShellConfig() ShellConfig {
+	return ShellConfig{
+		Aliases:          make(map[string]string),
+		Environment:      make(map[string]string),
+		Secrets:          NewSecretHygiene(),
+		AliasDefinitions: make(map[string][]AliasDefinition),
+		HistoryOptions:   make(map[string]bool),
+	}
+}
+
+func TestParseShellConfigAliases(t *testing.T) {
+	tests := []struct {
+		shell   string
+		content string
+		want    map[string]string
+	}{
+		{"bash", `alias gs='git status'`, map[string]string{"gs": "git status"}},
+		{"bash", `alias deploy='make build && ENV=prod make push'`, map[string]string{"deploy": "make build && ENV=prod make push"}},
+		{"bash", `alias say="echo \"hi there\""`, map[string]string{"say": `echo "hi there"`}},
+		{"bash", `alias q='echo '\''quoted'\'''`, map[string]string{"q": "echo 'quoted'"}},
+		{"bash", "alias ll=\"ls \\\n-la\"", map[string]string{"ll": "ls -la"}},
+		{"bash", `alias a=b c='d e'`, map[string]string{"a": "b", "c": "d e"}},
+		{"zsh", `alias -g G='| grep'`, map[string]string{"G": "| grep"}},
+		{"zsh", "alias\nalias -L", map[string]string{}},
+		{"fish", `alias gs 'git status'`, map[string]string{"gs": "git status"}},
+		{"fish", `alias gp='git push'`, map[string]string{"gp": "git push"}},
+		{"fish", `alias ll ls -la`, map[string]string{"ll": "ls -la"}},
+		{"fish", `abbr -a gco git checkout`, map[string]string{"gco": "git checkout"}},
+		{"fish", `abbr --add --position anywhere k kubectl`, map[string]string{"k": "kubectl"}},
+		{"fish", "abbr -e gco\nabbr --list", map[string]string{}},
+		// abbr is fish's only
+		{"bash", `abbr -a gco git checkout`, map[string]string{}},
+	}
+	for _, test := range tests {
+		config := newTestShellConfig()
+		parseShellConfig(test.shell, "~/.config", test.content, &config)
+		if !maps.Equal(config.Aliases, test.want) {
+			t.Errorf("%s: %q defined %q, want %q", test.shell, test.content, config.Aliases, test.want)
+		}
+	}
+}
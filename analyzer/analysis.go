@@ -0,0 +1,1067 @@
+package analyzer
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// How days are written in keys and dates, like "2024-04-12"
+const DayLayout = "2006-01-02"
+
+// Basic data structures
+type ShellData struct {
+	Histories    map[string][]CommandEntry
+	CommonCmds   map[string]int
+	TimePatterns map[string]int
+	Insights     DetailedInsights
+	ShellConfigs map[string]ShellConfig
+
+	// Category weights summed over every command read, like CommonCmds
+	CategoryTotals map[string]float64
+
+	// Per-shell alias expansions applied during analysis
+	AliasExpansions map[string]map[string]AliasExpansion
+
+	// Parts of the analysis turned off in config, per shell
+	Skipped map[string][]string
+
+	// The shell the user mainly works in, empty when nothing was analyzed
+	PrimaryShell string
+
+	// Older commands per shell past max_entries, counted in the totals
+	// and per-command insights but not kept
+	DroppedCommands map[string]int
+
+	// History lines skipped per shell as prompt output, not commands
+	PromptNoise map[string]int
+
+	// How the shell configs changed across snapshots
+	ConfigDrift ConfigDrift
+
+	// Long stretches per shell with no history, longest first
+	HistoryGaps map[string][]HistoryGap
+
+	// Non-fatal errors hit while reading histories and configs
+	Errors []error `json:"-"`
+}
+
+// CommandEntry is a command from a history. Each run is read as its own
+// entry, since sessions, workflows, gaps and time sinks need each run's
+// time; once those are analyzed, repeats of a command are collapsed into
+// one entry, whose Timestamp and Duration are its latest run's.
+type CommandEntry struct {
+	Command    string
+	Timestamp  time.Time
+	Duration   time.Duration
+	Categories []CategoryMatch
+
+	// How many runs were collapsed into the entry, 0 for a single run,
+	// and the earliest and latest of their timestamps
+	Count     int
+	FirstSeen time.Time
+	LastSeen  time.Time
+}
+
+// Runs is how many times the entry's command ran
+func (e CommandEntry) Runs() int {
+	return max(e.Count, 1)
+}
+
+// Seen is when the entry's command first and last ran, zero when unknown
+func (e CommandEntry) Seen() (first, last time.Time) {
+	if e.Count == 0 {
+		return e.Timestamp, e.Timestamp
+	}
+	return e.FirstSeen, e.LastSeen
+}
+
+// CategoryMatch is a category a command belongs to, weighted by confidence
+type CategoryMatch struct {
+	Name   string
+	Weight float64
+}
+
+type DetailedInsights struct {
+	TechnicalProfile TechProfile
+	WorkPatterns     WorkPatterns
+	ToolUsage        ToolUsage
+	Security         SecurityInsights
+	Hints            []Hint
+	Projects         []Project
+	PrivateProjects  PrivateProjects
+
+	// How sophisticated commands are, from pipes, redirects,
+	// substitutions and subshells
+	Complexity    float64
+	Substitutions SubstitutionStats
+}
+
+type TechProfile struct {
+	PrimaryRole     string
+	SecondarySkills []string
+	TechStack       []string
+	Proficiency     map[string]float64
+	LanguageUsage   map[string]int
+	RoleScores      map[string]float64
+	StackUsage      map[string]int
+}
+
+type WorkPatterns struct {
+	PeakHours       []int
+	CommonWorkflows []string
+	Productivity    map[string]float64
+	Interactivity   InteractivityStats
+	Navigation      NavigationStats
+	Sessions        SessionStats
+	WorkHours       WorkHoursStats
+	Workplaces      WorkplaceStats
+	DailyActivity   map[string]int
+	TimeSinks       []TimeSink
+}
+
+type ToolUsage struct {
+	Editors    map[string]int
+	Languages  map[string]int
+	BuildTools map[string]int
+	Other      map[string]int
+	Tracked    []TrackedTool
+	Lifecycle  []ToolLifecycle
+	Pipelines  PipelineStats
+
+	// File types opened in each editor, keyed by editor then extension
+	EditorFileTypes map[string]map[string]int
+}
+
+// TrackedTool is a tool the user pinned in config to always be reported
+type TrackedTool struct {
+	Name      string
+	Count     int
+	Installed bool
+}
+
+type ShellConfig struct {
+	ConfigFiles map[string]ConfigInfo
+	Plugins     []PluginInfo
+	Aliases     map[string]string
+	Environment map[string]string
+	Secrets     SecretHygiene
+
+	// Every definition of each alias in the order the files were read
+	AliasDefinitions map[string][]AliasDefinition
+
+	// History options like SHARE_HISTORY, true when on and false when
+	// explicitly turned off
+	HistoryOptions map[string]bool
+
+	// Config files that exist but couldn't be read, left out of the
+	// analysis. The errors are in ShellData.Errors.
+	Unreadable []string `json:",omitempty"`
+
+	// The home directory ~ stands for, or empty for the user's
+	home string
+}
+
+type ConfigInfo struct {
+	Path     string
+	Modified time.Time
+
+	// Left out of JSON exports with -omit-config-contents
+	Content string `json:",omitempty"`
+}
+
+type PluginInfo struct {
+	Name        string
+	Source      string
+	LastUpdated time.Time
+}
+
+func NewShellData() ShellData {
+	return ShellData{
+		Histories:    make(map[string][]CommandEntry),
+		Skipped:      make(map[string][]string),
+		CommonCmds:   make(map[string]int),
+		TimePatterns: make(map[string]int),
+
+		CategoryTotals: make(map[string]float64),
+		Insights: DetailedInsights{
+			TechnicalProfile: TechProfile{
+				Proficiency:   make(map[string]float64),
+				LanguageUsage: make(map[string]int),
+				StackUsage:    make(map[string]int),
+			},
+			WorkPatterns: WorkPatterns{
+				Productivity:  make(map[string]float64),
+				DailyActivity: make(map[string]int),
+			},
+			ToolUsage: ToolUsage{
+				Editors:    make(map[string]int),
+				Languages:  make(map[string]int),
+				BuildTools: make(map[string]int),
+				Other:      make(map[string]int),
+
+				EditorFileTypes: make(map[string]map[string]int),
+			},
+			Security: SecurityInsights{
+				Secrets: NewSecretHygiene(),
+			},
+		},
+		ShellConfigs:    make(map[string]ShellConfig),
+		AliasExpansions: make(map[string]map[string]AliasExpansion),
+	}
+}
+
+func runAnalysis(cfg Config, progress *AnalysisProgress) ShellData {
+	data := NewShellData()
+	data.Insights.WorkPatterns.WorkHours.Definition = cfg.WorkHours.String()
+
+	// Read shell histories
+	expandedHistories := make(map[string][]CommandEntry)
+	totals := newCommandTotals()
+	totals.categories = cfg.Categories
+	stats := newCommandStats(&data, cfg, nil)
+	totals.observe = stats.add
+
+	sources := historySources(cfg, totals)
+	for i, source := range sources {
+		progress.set(i, len(sources))
+		shell := source.Name()
+		if len(cfg.shells) > 0 && !slices.Contains(cfg.shells, shell) {
+			continue
+		}
+
+		// Histories the user opted out of are never opened
+		if cfg.HistoryEnabled(shell) && !source.Detect() {
+			data.Errors = append(data.Errors, &AnalysisError{Shell: shell, Path: source.Location(), Kind: ErrHistoryNotFound})
+			continue
+		}
+
+		// The config comes first so its aliases are known while the
+		// history streams past
+		var config ShellConfig
+		var configErrs []error
+		if cfg.analyzeConfig(shell) {
+			config, configErrs = analyzeShellConfigs(shell, cfg.home)
+		}
+
+		// Totals count what each command ran, its aliases expanded
+		totals.expand = nil
+		if cfg.AliasExpansionDepth > 0 && len(config.Aliases) > 0 {
+			totals.expand = func(entry CommandEntry) CommandEntry {
+				entry, _ = expandEntryAliases(entry, config.Aliases, cfg.AliasExpansionDepth, cfg.Categories)
+				return entry
+			}
+		}
+
+		var history []CommandEntry
+		if cfg.HistoryEnabled(shell) {
+			// Passwords typed at the prompt are taken out as they're read,
+			// before anything else sees them, totals and exports included
+			screen := newPasswordScreen(shell, config.Aliases)
+			totals.screen = screen.keep
+			var err error
+			history, err = source.Read()
+			totals.screen = nil
+			if err != nil {
+				data.Errors = append(data.Errors, withShell(err, shell))
+
+				// Keep whatever was read before a parse error, and everything
+				// else when only some lines were too long
+				if !errors.Is(err, ErrParse) && !errors.Is(err, ErrLineTooLong) {
+					continue
+				}
+			}
+			data.Histories[shell] = history
+			data.Insights.Security.OrphanPasswords = append(data.Insights.Security.OrphanPasswords, screen.orphans...)
+		} else {
+			data.Skipped[shell] = append(data.Skipped[shell], SkippedHistory)
+		}
+
+		if cfg.analyzeConfig(shell) {
+			data.ShellConfigs[shell] = config
+			for _, err := range configErrs {
+				data.Errors = append(data.Errors, withShell(err, shell))
+			}
+		} else {
+			data.Skipped[shell] = append(data.Skipped[shell], SkippedConfig)
+		}
+
+		// Sources that don't count into totals weren't screened as they
+		// were read
+		history, orphans := redactOrphanPasswords(shell, history, config.Aliases, totals)
+		if len(orphans) > 0 {
+			data.Histories[shell] = history
+			data.Insights.Security.OrphanPasswords = append(data.Insights.Security.OrphanPasswords, orphans...)
+		}
+
+		// The rest needs the commands kept, one run after another
+		if len(history) == 0 {
+			continue
+		}
+
+		// Analyze commands with their aliases expanded. The kept history
+		// stays as typed, but is categorized by what it ran.
+		expanded := expandAliases(shell, history, config.Aliases, cfg.AliasExpansionDepth, cfg.Categories, &data)
+		for i := range history {
+			history[i].Categories = expanded[i].Categories
+		}
+
+		// Estimate typed vs pasted/scripted commands from timing
+		interactivity := analyzeInteractivity(expanded)
+		data.Insights.WorkPatterns.Interactivity.Interactive += interactivity.Interactive
+		data.Insights.WorkPatterns.Interactivity.Scripted += interactivity.Scripted
+		expandedHistories[shell] = expanded
+	}
+
+	progress.set(len(sources), len(sources))
+	stats.finish()
+
+	data.CommonCmds = totals.Tools
+	data.TimePatterns = totals.timePatterns()
+	data.CategoryTotals = totals.Categories
+	data.DroppedCommands = totals.Dropped
+	data.PromptNoise = totals.PromptNoise
+
+	data.HistoryGaps = make(map[string][]HistoryGap)
+	for shell, history := range data.Histories {
+		if gaps := findHistoryGaps(history); len(gaps) > 0 {
+			data.HistoryGaps[shell] = gaps
+		}
+	}
+
+	sequences := shellSequences(expandedHistories)
+	allEntries := combineSequences(sequences)
+	if cfg.Timeline {
+		analyzeTimeline(expandedHistories, &data)
+	} else {
+		data.Insights.WorkPatterns.CommonWorkflows = findWorkflows(sequences)
+	}
+
+	inferPrimaryRole(allEntries, &data, cfg.RoleWeights, time.Now())
+	data.Insights.ToolUsage.Lifecycle = analyzeToolLifecycle(allEntries)
+	data.Insights.WorkPatterns.TimeSinks = analyzeTimeSinks(allEntries)
+	data.Insights.ToolUsage.Pipelines = analyzePipelines(allEntries)
+	data.Insights.Complexity = analyzeCommandComplexity(allEntries)
+	data.Insights.WorkPatterns.Workplaces = analyzeWorkplaces(allEntries)
+	data.Insights.Substitutions = analyzeSubstitutions(allEntries)
+	data.Insights.Hints = analyzeHints(allEntries, cfg.SecretEntropyThreshold)
+	data.Insights.Projects, data.Insights.PrivateProjects = analyzeProjects(sequences, cfg.PrivatePaths, cfg.home)
+	data.Insights.Security.DangerousAliases = findDangerousAliases(data.ShellConfigs, cfg.AliasExpansionDepth)
+	data.PrimaryShell = inferPrimaryShell(data, cfg.loginShell(), time.Now())
+
+	snapshots, err := loadSnapshots(cfg.ExpandPath(SnapshotDir))
+	if err != nil {
+		data.Errors = append(data.Errors, fmt.Errorf("reading snapshots: %w", err))
+	}
+	data.ConfigDrift = analyzeConfigDrift(snapshots, summarizeConfigs(data.ShellConfigs), time.Now())
+	data.Insights.TechnicalProfile.StackUsage = analyzeStacks(allEntries, cfg.Stacks)
+
+	// Only the analysis above needs each run; what's kept is one entry
+	// per distinct command
+	for shell, history := range data.Histories {
+		data.Histories[shell] = collapseRepeats(history)
+	}
+
+	return data
+}
+
+// Collapse repeats of a command into its first entry, counting the runs
+// and keeping the earliest and latest of their timestamps. Entries that
+// were already collapsed, as when merging, add up the same way.
+func collapseRepeats(entries []CommandEntry) []CommandEntry {
+	index := make(map[string]int)
+	var collapsed []CommandEntry
+	for _, entry := range entries {
+		first, last := entry.Seen()
+		i, ok := index[entry.Command]
+		if !ok {
+			index[entry.Command] = len(collapsed)
+			entry.Count, entry.FirstSeen, entry.LastSeen = entry.Runs(), first, last
+			collapsed = append(collapsed, entry)
+			continue
+		}
+
+		merged := &collapsed[i]
+		merged.Count += entry.Runs()
+		if !first.IsZero() && (merged.FirstSeen.IsZero() || first.Before(merged.FirstSeen)) {
+			merged.FirstSeen = first
+		}
+		// Undated runs read later in the history are the more recent
+		if !last.Before(merged.LastSeen) {
+			merged.LastSeen, merged.Timestamp, merged.Duration = last, entry.Timestamp, entry.Duration
+		}
+	}
+	return collapsed
+}
+
+// All commands in one slice. A single history, the usual case in
+// containers, is used as is rather than copied.
+func combineSequences(sequences [][]CommandEntry) []CommandEntry {
+	if len(sequences) == 1 {
+		return sequences[0]
+	}
+
+	total := 0
+	for _, sequence := range sequences {
+		total += len(sequence)
+	}
+	entries := make([]CommandEntry, 0, total)
+	for _, sequence := range sequences {
+		entries = append(entries, sequence...)
+	}
+	return entries
+}
+
+// historyReadStats counts the lines of a history file that were read but
+// not kept
+type historyReadStats struct {
+	// Older commands past max_entries
+	Dropped int
+
+	// Lines that were prompt output rather than commands
+	PromptNoise int
+}
+
+// Read a history file in one pass, keeping at most cfg.MaxEntries of the
+// newest commands, or none with cfg.countOnly. Every command, kept or not,
+// is counted in totals, which may be nil.
+func readHistory(path string, cfg Config, totals *CommandTotals) ([]CommandEntry, historyReadStats, error) {
+	var stats historyReadStats
+	file, err := openHistory(path, cfg)
+	if err != nil {
+		return nil, stats, err
+	}
+	defer file.Close()
+
+	window := entryWindow{limit: cfg.MaxEntries, countOnly: cfg.countOnly}
+	var pendingTimestamp time.Time
+	skipped := 0
+	scanner := newLineScanner(file, cfg.MaxLineBytes, &skipped)
+
+	lastSkipped := 0
+	for scanner.Scan() {
+		line := scanner.Text()
+		// A timestamp belongs to the line skipped for its length after it
+		if skipped != lastSkipped {
+			pendingTimestamp = time.Time{}
+			lastSkipped = skipped
+		}
+
+		// Bash writes the timestamp on its own line before the command
+		if ts, ok := parseBashTimestamp(line); ok {
+			pendingTimestamp = ts
+			continue
+		}
+		// Other comment lines, such as timestamps in a format we don't
+		// know, don't run anything and aren't commands
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		timestamp, duration := pendingTimestamp, time.Duration(0)
+		pendingTimestamp = time.Time{}
+		if ts, elapsed, cmd, ok := parseZshExtended(line); ok {
+			timestamp, duration, line = ts, elapsed, cmd
+		}
+
+		cmd := cleanHistoryLine(line, cfg.historyPrefixRegex)
+		if isPromptNoise(cmd, cfg.promptNoise) {
+			stats.PromptNoise++
+			continue
+		}
+		if cmd != "" {
+			entry := CommandEntry{
+				Command:    cmd,
+				Timestamp:  timestamp,
+				Duration:   duration,
+				Categories: cfg.Categories.commandCategories(cmd),
+			}
+			if totals.Add(entry) {
+				window.add(entry)
+			}
+		}
+	}
+
+	entries := window.ordered()
+	stats.Dropped = window.dropped
+	if err := scanner.Err(); err != nil {
+		return entries, stats, parseError(path, err)
+	}
+	if skipped > 0 {
+		return entries, stats, &AnalysisError{Path: path, Kind: ErrLineTooLong,
+			Cause: fmt.Errorf("skipped %d line(s) of %d bytes or more, raise max_line_bytes to read them", skipped, cfg.MaxLineBytes)}
+	}
+	return entries, stats, nil
+}
+
+// The whole command on a history line, without the metadata a configured
+// prefix matches
+func cleanHistoryLine(line string, prefix *regexp.Regexp) string {
+	// Files saved on Windows end lines with \r\n, which would otherwise
+	// leave a \r on every command
+	line = strings.TrimSuffix(line, "\r")
+
+	// Strip a user-configured prefix such as line numbers or timestamps
+	if prefix != nil {
+		if loc := prefix.FindStringIndex(line); loc != nil && loc[0] == 0 {
+			line = line[loc[1]:]
+		}
+	}
+
+	return strings.TrimSpace(line)
+}
+
+// commandStats works out the insights drawn from one command at a time as
+// the histories stream past, so they cover every command read, whether or
+// not it's kept
+type commandStats struct {
+	data *ShellData
+	cfg  Config
+
+	// The result of getInstalledLanguages, probed on the first command
+	// since each probe starts a process; nil until then
+	installedLangs map[string]string
+
+	langUsage       map[string]int
+	toolUsage       map[string]int
+	timeOfDay       map[int]int
+	commandPatterns map[string]int
+	commands        map[string]int
+	distinct        map[string]bool
+	runs            int
+	subcommands     int
+}
+
+func newCommandStats(data *ShellData, cfg Config, installedLangs map[string]string) *commandStats {
+	usage := &data.Insights.ToolUsage
+	if len(usage.Tracked) == 0 {
+		for _, tool := range cfg.TrackedTools {
+			usage.Tracked = append(usage.Tracked, TrackedTool{
+				Name:      tool,
+				Installed: CheckToolInstalled(tool),
+			})
+		}
+	}
+
+	return &commandStats{
+		data:            data,
+		cfg:             cfg,
+		installedLangs:  installedLangs,
+		langUsage:       make(map[string]int),
+		toolUsage:       make(map[string]int),
+		timeOfDay:       make(map[int]int),
+		commandPatterns: make(map[string]int),
+		commands:        make(map[string]int),
+		distinct:        make(map[string]bool),
+	}
+}
+
+// Analyze a whole history at once. installedLangs is the result of
+// getInstalledLanguages.
+func analyzeCommands(entries []CommandEntry, data *ShellData, cfg Config, installedLangs map[string]string) {
+	if installedLangs == nil {
+		installedLangs = map[string]string{}
+	}
+	stats := newCommandStats(data, cfg, installedLangs)
+	for _, entry := range entries {
+		stats.add(entry)
+	}
+	stats.finish()
+}
+
+// Count one run of a command
+func (s *commandStats) add(entry CommandEntry) {
+	if s.installedLangs == nil {
+		s.installedLangs = map[string]string{}
+		if !s.cfg.noProbe {
+			s.installedLangs = getInstalledLanguages(s.cfg)
+		}
+	}
+	data := s.data
+	s.runs++
+	s.distinct[entry.Command] = true
+
+	if !entry.Timestamp.IsZero() {
+		s.timeOfDay[entry.Timestamp.Hour()]++
+		data.Insights.WorkPatterns.DailyActivity[entry.Timestamp.Format(DayLayout)]++
+		data.Insights.WorkPatterns.WorkHours.add(entry.Timestamp, s.cfg.WorkHours)
+	}
+
+	// Analyze each command chained with ;, && or ||
+	for _, cmd := range SplitCommand(entry.Command) {
+		s.subcommands++
+		base := BaseCommand(cmd)
+		s.commands[base]++
+
+		// Language usage analysis
+		for lang := range s.installedLangs {
+			if commandUsesLanguage(cmd, lang) {
+				s.langUsage[lang]++
+			}
+		}
+
+		// Development tool analysis
+		for _, tool := range devTools {
+			if base == tool && CheckToolInstalled(tool) {
+				s.toolUsage[tool]++
+			}
+		}
+
+		// Tools pinned in config
+		for i := range data.Insights.ToolUsage.Tracked {
+			if base != "" && base == data.Insights.ToolUsage.Tracked[i].Name {
+				data.Insights.ToolUsage.Tracked[i].Count++
+			}
+		}
+
+		// Editors and the files they open
+		detectEditorUse(cmd, &data.Insights.ToolUsage)
+
+		// Build tool analysis
+		if tool := detectBuildTool(cmd); tool != "" {
+			data.Insights.ToolUsage.BuildTools[tool]++
+		}
+
+		// Analyze command patterns
+		analyzeCommandPattern(cmd, s.commandPatterns)
+
+		// How cd targets are written
+		detectNavigation(cmd, &data.Insights.WorkPatterns.Navigation)
+
+		// Secret-management habits
+		detectHistorySecrets(cmd, &data.Insights.Security.Secrets, s.cfg.SecretEntropyThreshold)
+
+		// Commands run with elevated privileges
+		if _, privileged := unwrapCommand(cmd); privileged {
+			data.Insights.Security.PrivilegedCommands++
+		}
+	}
+}
+
+// Work out the insights that need every command counted first
+func (s *commandStats) finish() {
+	data := s.data
+
+	// Installed binaries the lists above don't cover
+	known := make(map[string]bool)
+	for _, tool := range devTools {
+		known[tool] = true
+	}
+	for lang := range s.installedLangs {
+		known[lang] = true
+		known[getPackageManager(lang)] = true
+	}
+	for tool, count := range detectOtherTools(s.commands, known) {
+		data.Insights.ToolUsage.Other[tool] += count
+	}
+
+	// Update TechnicalProfile
+	techProfile := &data.Insights.TechnicalProfile
+
+	// Language usage across shells feeds primary role inference
+	for lang, count := range s.langUsage {
+		techProfile.LanguageUsage[lang] += count
+		// Tools like git and docker are probed too, but aren't languages
+		if _, ok := languageVersionCommands[lang]; ok {
+			data.Insights.ToolUsage.Languages[lang] += count
+		}
+	}
+
+	// Calculate tech stack
+	techProfile.TechStack = make([]string, 0)
+	for lang := range s.installedLangs {
+		if s.langUsage[lang] > 0 {
+			techProfile.TechStack = append(techProfile.TechStack, lang)
+		}
+	}
+
+	// Calculate proficiency, as the share of commands using each, counted
+	// like their uses per command chained with ;, && or ||
+	if s.subcommands > 0 {
+		for lang, count := range s.langUsage {
+			techProfile.Proficiency[lang] = float64(count) / float64(s.subcommands)
+		}
+		for tool, count := range s.toolUsage {
+			techProfile.Proficiency[tool] = float64(count) / float64(s.subcommands)
+		}
+	}
+
+	// Update WorkPatterns
+	patterns := &data.Insights.WorkPatterns
+	patterns.PeakHours = getPeakHours(s.timeOfDay)
+
+	// Calculate productivity metrics based on command complexity and variety
+	patterns.Productivity = calculateProductivityMetrics(s.runs, len(s.distinct), s.subcommands, s.commandPatterns)
+}
+
+func getPackageManager(lang string) string {
+	managers := map[string]string{
+		"python": "pip",
+		"node":   "npm",
+		"go":     "go get",
+		"rust":   "cargo",
+		"ruby":   "gem",
+		"php":    "composer",
+	}
+	return managers[lang]
+}
+
+func analyzeCommandPattern(cmd string, patterns map[string]int) {
+	// Define common command patterns
+	patternMap := map[string]*regexp.Regexp{
+		"git_workflow": regexp.MustCompile(`git (commit|push|pull|merge)`),
+		"build":        regexp.MustCompile(`(make|build|compile)`),
+		"deploy":       regexp.MustCompile(`(deploy|kubectl|docker)`),
+		"test":         regexp.MustCompile(`test|spec|pytest`),
+	}
+
+	for pattern, regex := range patternMap {
+		if regex.MatchString(cmd) {
+			patterns[pattern]++
+		}
+	}
+}
+
+func getPeakHours(timeOfDay map[int]int) []int {
+	type hourCount struct {
+		hour  int
+		count int
+	}
+
+	var hours []hourCount
+	for h, c := range timeOfDay {
+		hours = append(hours, hourCount{h, c})
+	}
+
+	sort.Slice(hours, func(i, j int) bool {
+		return hours[i].count > hours[j].count
+	})
+
+	// Return top 3 peak hours
+	var peaks []int
+	for i := 0; i < len(hours) && i < 3; i++ {
+		peaks = append(peaks, hours[i].hour)
+	}
+	return peaks
+}
+
+func calculateProductivityMetrics(runs, distinct, subcommands int, patterns map[string]int) map[string]float64 {
+	metrics := make(map[string]float64)
+	if runs == 0 {
+		return metrics
+	}
+
+	// Command variety score
+	metrics["Command Variety"] = float64(distinct) / float64(runs)
+
+	// Workflow complexity score. Patterns are counted per chained
+	// command, and one command can match several, so it's capped at 1.
+	if subcommands > 0 {
+		workflowScore := float64(patterns["git_workflow"]+patterns["build"]+
+			patterns["deploy"]+patterns["test"]) / float64(subcommands)
+		metrics["Workflow Complexity"] = min(workflowScore, 1)
+	}
+
+	return metrics
+}
+
+// Version commands for programming language runtimes, the only probed
+// tools reported as languages
+var languageVersionCommands = map[string]string{
+	"python":  "python --version",
+	"python3": "python3 --version",
+	"node":    "node --version",
+	"go":      "go version",
+	"java":    "java -version",
+	"ruby":    "ruby --version",
+	"php":     "php --version",
+	"rust":    "rustc --version",
+	"perl":    "perl --version",
+	"scala":   "scala -version",
+	"kotlin":  "kotlin -version",
+	"swift":   "swift --version",
+	"r":       "R --version",
+	"julia":   "julia --version",
+	"haskell": "ghc --version",
+	"elixir":  "elixir --version",
+	"erlang":  "erl -version",
+	"clang":   "clang --version",
+	"gcc":     "gcc --version",
+	"dotnet":  "dotnet --version",
+	"lua":     "lua -v",
+	"ocaml":   "ocaml -version",
+	"dart":    "dart --version",
+	"zig":     "zig version",
+	"nim":     "nim --version",
+}
+
+// Version commands for the other tools probed alongside the languages
+var toolVersionCommands = map[string]string{
+	// Build Tools & Package Managers
+	"maven":    "mvn --version",
+	"gradle":   "gradle --version",
+	"npm":      "npm --version",
+	"yarn":     "yarn --version",
+	"pnpm":     "pnpm --version",
+	"pip":      "pip --version",
+	"cargo":    "cargo --version",
+	"composer": "composer --version",
+	"bundler":  "bundle --version",
+
+	// DevOps & Cloud Tools
+	"docker":    "docker --version",
+	"kubectl":   "kubectl version --client",
+	"terraform": "terraform version",
+	"ansible":   "ansible --version",
+	"vagrant":   "vagrant --version",
+	"helm":      "helm version",
+	"aws":       "aws --version",
+	"gcloud":    "gcloud --version",
+	"azure":     "az --version",
+
+	// Version Control
+	"git":       "git --version",
+	"svn":       "svn --version",
+	"mercurial": "hg --version",
+
+	// Databases
+	"mysql":   "mysql --version",
+	"psql":    "psql --version",
+	"mongodb": "mongod --version",
+	"redis":   "redis-cli --version",
+
+	// Web Servers & Tools
+	"nginx":   "nginx -v",
+	"apache2": "apache2 -v",
+	"curl":    "curl --version",
+	"wget":    "wget --version",
+
+	// Text Editors & IDEs
+	"vim":   "vim --version",
+	"nvim":  "nvim --version",
+	"emacs": "emacs --version",
+	"code":  "code --version",
+
+	// Shell & Terminal Tools
+	"zsh":  "zsh --version",
+	"bash": "bash --version",
+	"fish": "fish --version",
+	"tmux": "tmux -V",
+}
+
+// The installed languages and tools, with their versions
+func getInstalledLanguages(cfg Config) map[string]string {
+	commands := maps.Clone(languageVersionCommands)
+	maps.Copy(commands, toolVersionCommands)
+
+	// Every installed one is kept: usage is counted from history
+	// afterwards, so a cap here would drop languages at random
+	return runProbes(commands, cfg.ProbeConcurrency, cfg.CommandTimeout())
+}
+
+// Read and parse a shell's config files. Files that can't be read are
+// skipped and returned as errors, so one unreadable file doesn't hide the
+// rest. ~ in config paths stands for home, or the user's home directory
+// when it's empty.
+func analyzeShellConfigs(shell, home string) (ShellConfig, []error) {
+	configPaths := DefaultConfigPaths()
+
+	config := ShellConfig{
+		ConfigFiles: make(map[string]ConfigInfo),
+		Aliases:     make(map[string]string),
+		Environment: make(map[string]string),
+		Plugins:     make([]PluginInfo, 0),
+		Secrets:     NewSecretHygiene(),
+
+		AliasDefinitions: make(map[string][]AliasDefinition),
+		HistoryOptions:   make(map[string]bool),
+		home:             home,
+	}
+
+	// Read and analyze config files, and the files they source
+	var errs []error
+	for _, path := range configPaths[shell] {
+		errs = append(errs, readShellConfig(shell, path, 0, &config)...)
+	}
+
+	// Detect plugins based on shell type
+	errs = append(errs, detectPlugins(shell, &config)...)
+
+	return config, errs
+}
+
+func parseShellConfig(shell, path, content string, config *ShellConfig) {
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	// The file is already in memory, so allow lines as long as all of it
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), len(content)+1)
+	lineNumber := 0
+	for scanner.Scan() {
+		// Also strip a \r left by \r\r\n endings, which some sync tools write
+		line := strings.TrimRight(scanner.Text(), "\r")
+		lineNumber++
+
+		// Parse aliases and environment variables, which may go on over
+		// several lines inside quotes or after a trailing backslash
+		definition := strings.TrimSpace(line)
+		isAlias := isAliasDefinition(shell, definition)
+		if isAlias || isEnvironmentDefinition(shell, line) {
+			start := lineNumber
+			words, complete := ShellWords(definition)
+			for !complete && scanner.Scan() {
+				definition += "\n" + strings.TrimRight(scanner.Text(), "\r")
+				lineNumber++
+				words, complete = ShellWords(definition)
+			}
+
+			if isAlias {
+				for _, alias := range parseAliasDefinition(shell, words) {
+					config.Aliases[alias.name] = alias.value
+					config.AliasDefinitions[alias.name] = append(config.AliasDefinitions[alias.name],
+						AliasDefinition{File: path, Line: start, Value: alias.value})
+				}
+			} else {
+				for _, variable := range parseEnvironmentDefinition(shell, words) {
+					config.Environment[variable.name] = variable.value
+				}
+			}
+		}
+
+		// Detect options that change what gets recorded in history
+		detectHistoryOptions(line, config.HistoryOptions)
+
+		// Detect secret-management setup
+		detectConfigSecrets(line, &config.Secrets)
+	}
+}
+
+// Expand ~ in a path to the home directory being analyzed
+func (c ShellConfig) ExpandPath(path string) string {
+	return expandHomePath(path, c.home)
+}
+
+// Find the shell's plugins. Only fish's are read from files, whose
+// errors are returned.
+func detectPlugins(shell string, config *ShellConfig) []error {
+	switch shell {
+	case "zsh":
+		detectZshPlugins(config)
+	case "fish":
+		return detectFishPlugins(config)
+	case "bash":
+		detectBashPlugins(config)
+	}
+	return nil
+}
+
+func detectZshPlugins(config *ShellConfig) {
+	// Check for common plugin managers
+	pluginManagers := []string{
+		"~/.oh-my-zsh",
+		"~/.antigen",
+		"~/.zinit",
+		"~/.zplug",
+	}
+
+	for _, manager := range pluginManagers {
+		path := config.ExpandPath(manager)
+		if info, err := os.Stat(path); err == nil && info.IsDir() {
+			config.Plugins = append(config.Plugins, PluginInfo{
+				Name:        filepath.Base(manager),
+				Source:      path,
+				LastUpdated: info.ModTime(),
+			})
+		}
+	}
+
+	// Plugins enabled or loaded in the config
+	config.Plugins = append(config.Plugins, ohMyZshPlugins(*config)...)
+	config.Plugins = append(config.Plugins, managedZshPlugins(*config)...)
+}
+
+func detectFishPlugins(config *ShellConfig) []error {
+	fishPluginPath := config.ExpandPath("~/.config/fish/conf.d")
+	files, err := os.ReadDir(fishPluginPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		config.Unreadable = append(config.Unreadable, fishPluginPath)
+		return []error{FileError(fishPluginPath, err)}
+	}
+
+	var errs []error
+	for _, file := range files {
+		if strings.HasSuffix(file.Name(), ".fish") {
+			path := filepath.Join(fishPluginPath, file.Name())
+			info, err := file.Info()
+			if err != nil {
+				// Removed since the directory was listed, or unreadable
+				if !errors.Is(err, fs.ErrNotExist) {
+					errs = append(errs, FileError(path, err))
+				}
+				continue
+			}
+			config.Plugins = append(config.Plugins, PluginInfo{
+				Name:        strings.TrimSuffix(file.Name(), ".fish"),
+				Source:      path,
+				LastUpdated: info.ModTime(),
+			})
+		}
+	}
+	return errs
+}
+
+func detectBashPlugins(config *ShellConfig) {
+	// Check for common bash plugin managers and extensions
+	bashPluginPaths := []string{
+		"~/.bash_it",
+		"~/.local/share/bash-completion",
+	}
+
+	for _, path := range bashPluginPaths {
+		expandedPath := config.ExpandPath(path)
+		if info, err := os.Stat(expandedPath); err == nil && info.IsDir() {
+			config.Plugins = append(config.Plugins, PluginInfo{
+				Name:        filepath.Base(path),
+				Source:      expandedPath,
+				LastUpdated: info.ModTime(),
+			})
+		}
+	}
+}
+
+func analyzeCommandComplexity(entries []CommandEntry) float64 {
+	var totalCommands, complexCommands float64
+
+	for _, entry := range entries {
+		totalCommands++
+
+		// Count pipes, redirections, substitutions and subshells
+		if strings.Contains(entry.Command, "|") ||
+			strings.Contains(entry.Command, ">") ||
+			strings.Contains(entry.Command, "<") ||
+			len(findSubstitutions(entry.Command, 0)) > 0 {
+			complexCommands++
+		}
+
+		// Count commands with multiple arguments
+		if len(strings.Fields(entry.Command)) > 2 {
+			complexCommands += 0.5
+		}
+	}
+
+	if totalCommands == 0 {
+		return 0
+	}
+
+	return complexCommands / totalCommands
+}
@@ -0,0 +1,270 @@
+package analyzer
+
+import (
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"testing"
+)
+
+func TestCleanHistoryLinePrefixes(t *testing.T) {
+	tests := []struct {
+		name   string
+		prefix string
+		line   string
+		want   string
+	}{
+		{"saved history output", `^\s*\d+\*?\s+`, "  42  git status", "git status"},
+		{"edited history entry", `^\s*\d+\*?\s+`, " 108* vim notes.md", "vim notes.md"},
+		{"oh-my-zsh HIST_STAMPS", `^\s*\d+\*?\s+\d{4}-\d{2}-\d{2} \d{2}:\d{2}\s+`,
+			"  42  2024-04-12 10:00  git status", "git status"},
+		{"PROMPT_COMMAND timestamp", `^\[[^\]]*\]\s*`, "[2024-04-12 10:00:00] git status", "git status"},
+		{"prefix must match at the start", `\d+\s+`, "sleep 10 && make", "sleep 10 && make"},
+		{"line without the prefix", `^\[[^\]]*\]\s*`, "git status", "git status"},
+		{"no prefix", "", "  ls -la  ", "ls -la"},
+	}
+	for _, test := range tests {
+		var prefix *regexp.Regexp
+		if test.prefix != "" {
+			prefix = regexp.MustCompile(test.prefix)
+		}
+		if got := cleanHistoryLine(test.line, prefix); got != test.want {
+			t.Errorf("%s: cleanHistoryLine(%q) = %q, want %q", test.name, test.line, got, test.want)
+		}
+	}
+}
+
+func TestCleanHistoryLineKeepsWholeCommand(t *testing.T) {
+	prefix := DefaultConfig().historyPrefixRegex
+	tests := []struct {
+		line string
+		want string
+	}{
+		{`git commit -m "fix"`, `git commit -m "fix"`},
+		{"docker run -it --rm ubuntu bash", "docker run -it --rm ubuntu bash"},
+		{"  42  git status", "git status"},
+		{" 108* vim notes.md", "vim notes.md"},
+		{"1 git log --oneline", "git log --oneline"},
+		{"git log -n 5", "git log -n 5"},
+		{"2to3 -w script.py", "2to3 -w script.py"},
+		{"42", "42"},
+		{"cd src && make\r", "cd src && make"},
+		{"   ", ""},
+	}
+	for _, test := range tests {
+		if got := cleanHistoryLine(test.line, prefix); got != test.want {
+			t.Errorf("cleanHistoryLine(%q) = %q, want %q", test.line, got, test.want)
+		}
+	}
+}
+
+func TestReadHistoryKeepsWholeCommands(t *testing.T) {
+	path := writeTestHistory(t, ".bash_history", "git commit -m \"fix\"\n  7  ls -la /tmp\nkubectl get pods -n kube-system\n")
+	entries, _, err := readHistory(path, DefaultConfig(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, entry := range entries {
+		got = append(got, entry.Command)
+	}
+	want := []string{`git commit -m "fix"`, "ls -la /tmp", "kubectl get pods -n kube-system"}
+	if !slices.Equal(got, want) {
+		t.Errorf("commands = %q, want %q", got, want)
+	}
+}
+
+func TestAnalyzeCommandsToolUsage(t *testing.T) {
+	var entries []CommandEntry
+	for _, cmd := range []string{
+		"go build ./...",
+		"go test ./... && git status",
+		"python3 app.py",
+		"vim main.go",
+		"make install",
+		"cargo build --release",
+		"git push",
+	} {
+		entries = append(entries, CommandEntry{Command: cmd})
+	}
+	installed := map[string]string{"go": "go1.22", "python3": "3.12", "git": "2.45", "cargo": "1.80"}
+
+	data := NewShellData()
+	analyzeCommands(entries, &data, DefaultConfig(), installed)
+	usage := data.Insights.ToolUsage
+
+	wantLanguages := map[string]int{"go": 2, "python3": 1}
+	if !maps.Equal(usage.Languages, wantLanguages) {
+		t.Errorf("Languages = %v, want %v without tools like git", usage.Languages, wantLanguages)
+	}
+	if usage.Editors["vim"] != 1 || usage.EditorFileTypes["vim"][".go"] != 1 {
+		t.Errorf("Editors = %v, file types = %v, want vim once on a .go file", usage.Editors, usage.EditorFileTypes)
+	}
+	for _, tool := range []string{"make", "cargo"} {
+		if usage.BuildTools[tool] == 0 {
+			t.Errorf("BuildTools = %v, want %s counted", usage.BuildTools, tool)
+		}
+	}
+}
+
+// Write a history fixture into a temp directory and return its path
+func writeTestHistory(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestReadHistoryCRLF(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{".bash_history", "#1712912400\r\ngit status\r\nls -la\r\n", []string{"git status", "ls -la"}},
+		{".zsh_history", ": 1712912400:0;git status\r\n: 1712912401:2;make\r\n", []string{"git status", "make"}},
+		{"fish_history", "- cmd: git status\r\n  when: 1712912400\r\n", []string{"git status"}},
+	}
+	for _, test := range tests {
+		path := writeTestHistory(t, test.name, test.content)
+		read := readHistory
+		if test.name == "fish_history" {
+			read = readFishHistory
+		}
+		entries, _, err := read(path, DefaultConfig(), nil)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		var got []string
+		for _, entry := range entries {
+			got = append(got, entry.Command)
+			if entry.Timestamp.IsZero() && test.name != ".bash_history" {
+				t.Errorf("%s: %q lost its timestamp", test.name, entry.Command)
+			}
+		}
+		if !slices.Equal(got, test.want) {
+			t.Errorf("%s: commands = %q, want %q", test.name, got, test.want)
+		}
+	}
+}
+
+func TestParseShellConfigCRLF(t *testing.T) {
+	config := newTestShellConfig()
+	content := "alias gs='git status'\r\nexport EDITOR=vim\r\nalias ll=\"ls \\\r\n  -la\"\r\n"
+	parseShellConfig("bash", "~/.bashrc", content, &config)
+
+	wantAliases := map[string]string{"gs": "git status", "ll": "ls   -la"}
+	if !maps.Equal(config.Aliases, wantAliases) {
+		t.Errorf("Aliases = %q, want %q", config.Aliases, wantAliases)
+	}
+	if config.Environment["EDITOR"] != "vim" {
+		t.Errorf("Environment = %q, want EDITOR=vim", config.Environment)
+	}
+}
+
+func TestRepeatedCommands(t *testing.T) {
+	content := "#1712912400\ngit status\n" +
+		"#1712912460\nmake\n" +
+		"#1712912520\ngit status\n" +
+		"#1712912580\ngit status\n"
+	home := writeTestHome(t, map[string]string{".bash_history": content})
+	data, err := Analyze(Options{HomeDir: home, Shells: []string{"bash"}, NoProbe: true, KeepEntries: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Repeats are merged into their first entry, counting every run
+	history := data.Histories["bash"]
+	if len(history) != 2 {
+		t.Fatalf("Histories[bash] = %+v, want git status and make merged", history)
+	}
+	gitEntry, makeEntry := history[0], history[1]
+	if gitEntry.Command != "git status" || gitEntry.Count != 3 {
+		t.Errorf("first entry = %q run %d times, want git status run 3 times", gitEntry.Command, gitEntry.Count)
+	}
+	if first, last := gitEntry.Seen(); first.Unix() != 1712912400 || last.Unix() != 1712912580 {
+		t.Errorf("git status seen from %d to %d, want 1712912400 to 1712912580", first.Unix(), last.Unix())
+	}
+	if gitEntry.Timestamp.Unix() != 1712912580 {
+		t.Errorf("git status Timestamp = %d, want its latest run", gitEntry.Timestamp.Unix())
+	}
+	if makeEntry.Command != "make" || makeEntry.Count != 1 || makeEntry.FirstSeen.Unix() != 1712912460 || !makeEntry.LastSeen.Equal(makeEntry.FirstSeen) {
+		t.Errorf("second entry = %+v, want make run once at 1712912460", makeEntry)
+	}
+	if data.CommonCmds["git"] != 3 {
+		t.Errorf("CommonCmds[git] = %d, want every run counted", data.CommonCmds["git"])
+	}
+
+	// 2 distinct commands out of 4 runs
+	if variety := data.Insights.WorkPatterns.Productivity["Command Variety"]; variety != 0.5 {
+		t.Errorf("Command Variety = %v, want 0.5", variety)
+	}
+
+	// Merging merged entries adds their counts up
+	merged := collapseRepeats(append(slices.Clone(history), history...))
+	if len(merged) != 2 || merged[0].Count != 6 || merged[1].Count != 2 {
+		t.Errorf("merging twice = %+v, want counts 6 and 2", merged)
+	}
+}
+
+func TestAllInstalledLanguagesKept(t *testing.T) {
+	// More languages than the old top-10 cap, each run by its own name
+	langs := []string{"python", "python3", "node", "go", "java", "ruby", "php", "perl", "scala", "kotlin",
+		"swift", "julia", "elixir", "clang", "gcc", "dotnet", "lua", "ocaml", "dart", "zig", "nim"}
+	fakeTools(t, langs...)
+
+	installed := getInstalledLanguages(DefaultConfig())
+	var entries []CommandEntry
+	for _, lang := range langs {
+		if _, ok := installed[lang]; !ok {
+			t.Errorf("installed %s was dropped", lang)
+		}
+		entries = append(entries, CommandEntry{Command: lang + " main"})
+	}
+
+	data := NewShellData()
+	analyzeCommands(entries, &data, DefaultConfig(), installed)
+	for _, lang := range langs {
+		if data.Insights.ToolUsage.Languages[lang] != 1 {
+			t.Errorf("Languages[%s] = %d, want 1", lang, data.Insights.ToolUsage.Languages[lang])
+		}
+	}
+}
+
+// Histories whose chained or multi-pattern commands once scored over 1
+var scoreHistories = []string{
+	"git add . && git commit -m x\n",
+	"make test\nmake test\ndocker build .\n",
+}
+
+func TestScoresStayWithinOne(t *testing.T) {
+	fakeTools(t, "git", "make", "docker")
+	for _, history := range scoreHistories {
+		home := writeTestHome(t, map[string]string{".bash_history": history})
+		data, err := Analyze(Options{HomeDir: home, Shells: []string{"bash"}, KeepEntries: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for tool, level := range data.Insights.TechnicalProfile.Proficiency {
+			if level < 0 || level > 1 {
+				t.Errorf("%q: Proficiency[%s] = %v, want within [0, 1]", history, tool, level)
+			}
+		}
+		for metric, value := range data.Insights.WorkPatterns.Productivity {
+			if value < 0 || value > 1 {
+				t.Errorf("%q: Productivity[%s] = %v, want within [0, 1]", history, metric, value)
+			}
+		}
+	}
+
+	// Both of git's commands use it
+	data := NewShellData()
+	analyzeCommands([]CommandEntry{{Command: "git add . && git commit -m x"}}, &data, DefaultConfig(), nil)
+	if got := data.Insights.TechnicalProfile.Proficiency["git"]; got != 1 {
+		t.Errorf("git proficiency = %v, want 1", got)
+	}
+}
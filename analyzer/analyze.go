@@ -0,0 +1,107 @@
+// Package analyzer reads shell histories and config files and finds the
+// habits, tools and risks in them. Analyze runs the whole analysis; the
+// shell-analyzer command draws its TUI and reports from the result.
+package analyzer
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+)
+
+// Options choose what Analyze reads
+type Options struct {
+	// Settings as read from the config file, or nil for the defaults
+	Config *Config
+
+	// History file paths by shell, replacing the default locations
+	HistoryPaths map[string]string
+
+	// Shells to analyze, like "zsh"; empty for every shell
+	Shells []string
+
+	// The home directory to analyze, like a directory of test fixtures,
+	// or empty for the user's. $HISTFILE and $XDG_DATA_HOME are ignored
+	// when it's set.
+	HomeDir string
+
+	// The login shell, like "zsh", which counts toward the primary shell.
+	// Empty for $SHELL, or for none when HomeDir is set.
+	LoginShell string
+
+	// Skip running installed languages to find their versions. Tech
+	// Profile then only shows what history alone tells.
+	NoProbe bool
+
+	// Keep the commands read in ShellData.Histories, repeats merged, for
+	// searching them and for the insights that follow runs in order, like
+	// projects, workflows and the primary role. Without it histories are
+	// only streamed into the totals and the insights drawn from one
+	// command at a time, which takes little memory however long they are.
+	KeepEntries bool
+
+	// Counts the shells analyzed so far, for a progress bar; may be nil
+	Progress *AnalysisProgress
+}
+
+// Analyze reads shell histories and config files and returns what they
+// show, without the terminal UI. Files that are missing or can't be read
+// are listed in ShellData.Errors rather than failing the analysis; the
+// error is only for options that can't be used.
+func Analyze(opts Options) (ShellData, error) {
+	cfg := opts.config()
+	var known []string
+	for _, source := range historySources(cfg, nil) {
+		known = append(known, source.Name())
+	}
+	for _, shell := range opts.Shells {
+		if !slices.Contains(known, shell) {
+			return ShellData{}, fmt.Errorf("unknown shell %q, expected one of %v", shell, known)
+		}
+	}
+	return runAnalysis(cfg, opts.Progress), nil
+}
+
+// HistorySources lists where Analyze reads each shell's history with
+// these options, including shells it's told to skip
+func HistorySources(opts Options) []HistorySource {
+	return historySources(opts.config(), nil)
+}
+
+// InputFiles lists the history and config files Analyze reads with these
+// options, whether or not they exist
+func InputFiles(opts Options) []string {
+	cfg := opts.config()
+	var paths []string
+	for _, source := range historySources(cfg, nil) {
+		paths = append(paths, source.Location())
+	}
+	for _, shellPaths := range DefaultConfigPaths() {
+		for _, path := range shellPaths {
+			paths = append(paths, cfg.ExpandPath(path))
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// The config with the options applied
+func (opts Options) config() Config {
+	cfg := DefaultConfig()
+	if opts.Config != nil {
+		cfg = *opts.Config
+	}
+
+	cfg.home = opts.HomeDir
+	cfg.login = opts.LoginShell
+	cfg.HistoryPaths = maps.Clone(cfg.HistoryPaths)
+	if cfg.HistoryPaths == nil {
+		cfg.HistoryPaths = make(map[string]string)
+	}
+	maps.Copy(cfg.HistoryPaths, opts.HistoryPaths)
+	cfg.shells = opts.Shells
+	cfg.noProbe = opts.NoProbe
+	cfg.countOnly = !opts.KeepEntries
+	return cfg
+}
@@ -0,0 +1,333 @@
+package analyzer
+
+import (
+	"maps"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Write files into a temp home directory, keyed by their path under it
+func writeTestHome(t testing.TB, files map[string]string) string {
+	t.Helper()
+	home := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(home, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return home
+}
+
+// Put fake tools on PATH that print a version and log each run, and
+// return the log's path. Nothing else is on PATH, so every other probe
+// finds its tool missing.
+func fakeTools(t testing.TB, tools ...string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake tools are POSIX shell scripts")
+	}
+	bin := t.TempDir()
+	log := filepath.Join(t.TempDir(), "runs.log")
+	sh, err := os.Readlink("/bin/sh")
+	if err != nil {
+		sh = "/bin/sh"
+	} else if !filepath.IsAbs(sh) {
+		sh = filepath.Join("/bin", sh)
+	}
+	if err := os.Symlink(sh, filepath.Join(bin, "sh")); err != nil {
+		t.Fatal(err)
+	}
+	for _, tool := range tools {
+		script := "#!/bin/sh\necho " + tool + " >> " + log + "\necho " + tool + " 1.0\n"
+		if err := os.WriteFile(filepath.Join(bin, tool), []byte(script), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("PATH", bin)
+
+	// Lookups made with the old PATH no longer hold
+	resetLookPathCache()
+	t.Cleanup(resetLookPathCache)
+	return log
+}
+
+func resetLookPathCache() {
+	lookPathCache.Lock()
+	defer lookPathCache.Unlock()
+	clear(lookPathCache.found)
+}
+
+// The tools run so far, one line per run
+func toolRuns(t testing.TB, log string) []string {
+	t.Helper()
+	content, err := os.ReadFile(log)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.Fields(string(content))
+}
+
+// A history for each of bash, zsh and fish
+var threeShellHome = map[string]string{
+	".bash_history":                  "go build ./...\ngit status\n",
+	".zsh_history":                   ": 1712912400:0;go test ./...\n",
+	".local/share/fish/fish_history": "- cmd: python3 app.py\n  when: 1712912400\n",
+}
+
+func TestAnalyzeProbesOnce(t *testing.T) {
+	log := fakeTools(t, "go", "python3", "git")
+	data, err := Analyze(Options{HomeDir: writeTestHome(t, threeShellHome), Shells: []string{"bash", "zsh", "fish"}, KeepEntries: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data.Histories) != 3 {
+		t.Fatalf("analyzed %d shells, want 3", len(data.Histories))
+	}
+
+	// Each installed tool runs once for all three shells
+	runs := toolRuns(t, log)
+	if len(runs) != 3 {
+		t.Errorf("tools ran %d times (%v), want once each", len(runs), runs)
+	}
+	if data.Insights.ToolUsage.Languages["go"] != 2 {
+		t.Errorf("Languages = %v, want go from two shells", data.Insights.ToolUsage.Languages)
+	}
+}
+
+func TestAnalyzeNoProbe(t *testing.T) {
+	log := fakeTools(t, "go")
+	if _, err := Analyze(Options{HomeDir: writeTestHome(t, threeShellHome), NoProbe: true, KeepEntries: true}); err != nil {
+		t.Fatal(err)
+	}
+	if runs := toolRuns(t, log); len(runs) != 0 {
+		t.Errorf("NoProbe ran %v", runs)
+	}
+}
+
+// Reports the version probes started per analysis of three shells, which
+// is one per installed tool rather than one per tool and shell
+func BenchmarkAnalyzeProbes(b *testing.B) {
+	log := fakeTools(b, "go", "python3", "git", "node", "docker")
+	home := writeTestHome(b, threeShellHome)
+	b.ResetTimer()
+	for range b.N {
+		if _, err := Analyze(Options{HomeDir: home, Shells: []string{"bash", "zsh", "fish"}, KeepEntries: true}); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.StopTimer()
+	b.ReportMetric(float64(len(toolRuns(b, log)))/float64(b.N), "probes/op")
+}
+
+// A home with history and config for each of bash, zsh and fish
+var fixtureHome = map[string]string{
+	".bash_history": "cd ~/work/api\ngo test ./...\ngo vet ./...\ngit status\ngit diff\ngit push\n",
+	".bashrc":       "alias gs='git status'\nexport EDITOR=vim\n",
+	".zsh_history":  ": 1712912400:0;make build\n: 1712912460:3;k get pods\n",
+	".zshrc":        "plugins=(git docker)\nalias k=kubectl\n",
+
+	".local/share/fish/fish_history":        "- cmd: python3 app.py\n  when: 1712912400\n",
+	".config/fish/config.fish":              "alias ll 'ls -la'\nset -gx PAGER less\n",
+	".config/fish/conf.d/fzf_bindings.fish": "",
+}
+
+func TestAnalyzeFixtureHome(t *testing.T) {
+	// The environment describes the user's own home, not the fixture
+	t.Setenv("SHELL", "/usr/bin/fish")
+	t.Setenv("HISTFILE", filepath.Join(t.TempDir(), "elsewhere"))
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+
+	home := writeTestHome(t, fixtureHome)
+	// Work in a private directory given by its full path
+	private := "cd " + filepath.Join(home, "private", "diary") + "\nvim a.md\nvim b.md\nls\ngit add .\ngit commit\n"
+	if err := os.WriteFile(filepath.Join(home, ".bash_history"), []byte(fixtureHome[".bash_history"]+private), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := DefaultConfig()
+	cfg.PrivatePaths = []string{"~/private"}
+	data, err := Analyze(Options{Config: &cfg, HomeDir: home, NoProbe: true, Shells: []string{"bash", "zsh", "fish"}, KeepEntries: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for shell, want := range map[string]int{"bash": 12, "zsh": 2, "fish": 1} {
+		if got := len(data.Histories[shell]); got != want {
+			t.Errorf("%s history has %d entries, want %d", shell, got, want)
+		}
+	}
+	if got := data.Histories["zsh"][1]; got.Command != "k get pods" || got.Duration != 3*time.Second {
+		t.Errorf("zsh entry = %+v, want k get pods taking 3s", got)
+	}
+	if len(data.Errors) != 0 {
+		t.Errorf("errors = %v", data.Errors)
+	}
+
+	bash, zsh, fish := data.ShellConfigs["bash"], data.ShellConfigs["zsh"], data.ShellConfigs["fish"]
+	if bash.Aliases["gs"] != "git status" || bash.Environment["EDITOR"] != "vim" {
+		t.Errorf("bash config = %q, %q", bash.Aliases, bash.Environment)
+	}
+	if bash.ConfigFiles["~/.bashrc"].Path != filepath.Join(home, ".bashrc") {
+		t.Errorf("~/.bashrc read from %q, want the fixture", bash.ConfigFiles["~/.bashrc"].Path)
+	}
+	if got := pluginNames(zsh.Plugins); !slices.Equal(got, []string{"oh-my-zsh git", "oh-my-zsh docker"}) {
+		t.Errorf("zsh plugins = %q", got)
+	}
+	if fish.Aliases["ll"] != "ls -la" || fish.Environment["PAGER"] != "less" {
+		t.Errorf("fish config = %q, %q", fish.Aliases, fish.Environment)
+	}
+	if len(fish.Plugins) != 1 || fish.Plugins[0].Name != "fzf_bindings" {
+		t.Errorf("fish plugins = %+v, want fzf_bindings from conf.d", fish.Plugins)
+	}
+
+	// zsh's k alias is expanded
+	if got := data.AliasExpansions["zsh"]["k"]; got.Expansion != "kubectl" || got.Hits != 1 {
+		t.Errorf("AliasExpansions[zsh] = %+v, want k expanded once", data.AliasExpansions["zsh"])
+	}
+
+	// ~ in private_paths is the fixture home
+	if data.Insights.PrivateProjects.Projects != 1 || len(data.Insights.Projects) != 1 {
+		t.Errorf("Projects = %+v, PrivateProjects = %+v; want ~/work/api shown and the diary hidden",
+			data.Insights.Projects, data.Insights.PrivateProjects)
+	}
+	for _, project := range data.Insights.Projects {
+		if strings.Contains(project.Path, "private") {
+			t.Errorf("private project %s listed", project.Path)
+		}
+	}
+
+	// $SHELL isn't the fixture's login shell, so bash's volume wins
+	if data.PrimaryShell != "bash" {
+		t.Errorf("PrimaryShell = %q, want bash", data.PrimaryShell)
+	}
+}
+
+func TestAnalyzeLoginShell(t *testing.T) {
+	t.Setenv("SHELL", "/bin/bash")
+	home := writeTestHome(t, map[string]string{
+		".bash_history": "ls\n",
+		".zsh_history":  "ls\n",
+	})
+	for _, login := range []string{"bash", "zsh"} {
+		data, err := Analyze(Options{HomeDir: home, NoProbe: true, LoginShell: login, KeepEntries: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if data.PrimaryShell != login {
+			t.Errorf("PrimaryShell = %q with login shell %s", data.PrimaryShell, login)
+		}
+	}
+}
+
+func TestOpenHistoryDecryptsInHome(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the decrypt command is a shell script")
+	}
+	// The key only exists in the fixture home, so ~ must expand to it
+	home := writeTestHome(t, map[string]string{
+		".config/test/key":  "",
+		".bash_history.enc": "git status\n",
+	})
+	cfg := DefaultConfig()
+	cfg.home = home
+	cfg.DecryptCommands = map[string][]string{".enc": {"sh", "-c", `test -f "$0" && cat "$1"`, "~/.config/test/key"}}
+
+	entries, _, err := readHistory(filepath.Join(home, ".bash_history.enc"), cfg, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Command != "git status" {
+		t.Errorf("entries = %+v, want git status", entries)
+	}
+}
+
+func TestAnalyzeCountOnly(t *testing.T) {
+	home := writeTestHome(t, map[string]string{
+		".bash_history": "sudo apt update\nHunter2!pass\ngo build ./...\ngit status\n",
+		".zsh_history":  ": 1712912400:0;go test ./...\n",
+	})
+	data, err := Analyze(Options{HomeDir: home, Shells: []string{"bash", "zsh"}, NoProbe: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Nothing is kept, but everything is counted
+	for shell, history := range data.Histories {
+		if len(history) != 0 {
+			t.Errorf("kept %d %s entries without KeepEntries", len(history), shell)
+		}
+	}
+	if want := map[string]int{"apt": 1, "go": 2, "git": 1}; !maps.Equal(data.CommonCmds, want) {
+		t.Errorf("CommonCmds = %v, want %v", data.CommonCmds, want)
+	}
+	if data.TimePatterns[time.Unix(1712912400, 0).Format("15")] != 1 {
+		t.Errorf("TimePatterns = %v, want the zsh command's hour", data.TimePatterns)
+	}
+	if data.CategoryTotals["development"] == 0 {
+		t.Errorf("CategoryTotals = %v, want development", data.CategoryTotals)
+	}
+
+	// The password was still screened out as it streamed past
+	if orphans := data.Insights.Security.OrphanPasswords; len(orphans) != 1 || orphans[0].After != "sudo apt update" {
+		t.Errorf("OrphanPasswords = %+v, want the one after sudo", orphans)
+	}
+}
+
+func TestAnalyzeCountOnlyMatchesKept(t *testing.T) {
+	fakeTools(t, "git", "docker", "make", "go", "python3", "kubectl")
+	home := writeTestHome(t, fixtureHome)
+	run := func(keep bool, maxEntries int) ShellData {
+		t.Helper()
+		cfg := DefaultConfig()
+		cfg.MaxEntries = maxEntries
+		cfg.TrackedTools = []string{"git"}
+		data, err := Analyze(Options{Config: &cfg, HomeDir: home, Shells: []string{"bash", "zsh", "fish"}, KeepEntries: keep})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return data
+	}
+
+	// The totals and the insights drawn from one command at a time are
+	// the same whether every command, a few or none are kept
+	kept := run(true, 0)
+	for _, data := range []ShellData{run(false, 0), run(true, 2)} {
+		if !maps.Equal(data.CommonCmds, kept.CommonCmds) || !maps.Equal(data.TimePatterns, kept.TimePatterns) ||
+			!maps.Equal(data.CategoryTotals, kept.CategoryTotals) {
+			t.Errorf("totals = %v, %v, %v, want %v, %v, %v", data.CommonCmds, data.TimePatterns, data.CategoryTotals,
+				kept.CommonCmds, kept.TimePatterns, kept.CategoryTotals)
+		}
+
+		profile, keptProfile := data.Insights.TechnicalProfile, kept.Insights.TechnicalProfile
+		if len(keptProfile.LanguageUsage) == 0 || !maps.Equal(profile.LanguageUsage, keptProfile.LanguageUsage) ||
+			!maps.Equal(profile.Proficiency, keptProfile.Proficiency) {
+			t.Errorf("LanguageUsage = %v, Proficiency = %v, want %v, %v",
+				profile.LanguageUsage, profile.Proficiency, keptProfile.LanguageUsage, keptProfile.Proficiency)
+		}
+		usage, keptUsage := data.Insights.ToolUsage, kept.Insights.ToolUsage
+		if !maps.Equal(usage.BuildTools, keptUsage.BuildTools) || !slices.Equal(usage.Tracked, keptUsage.Tracked) {
+			t.Errorf("BuildTools = %v, Tracked = %v, want %v, %v", usage.BuildTools, usage.Tracked, keptUsage.BuildTools, keptUsage.Tracked)
+		}
+		patterns, keptPatterns := data.Insights.WorkPatterns, kept.Insights.WorkPatterns
+		if !maps.Equal(patterns.DailyActivity, keptPatterns.DailyActivity) || patterns.WorkHours != keptPatterns.WorkHours ||
+			!maps.Equal(patterns.Productivity, keptPatterns.Productivity) {
+			t.Errorf("DailyActivity = %v, WorkHours = %v, Productivity = %v, want %v, %v, %v",
+				patterns.DailyActivity, patterns.WorkHours, patterns.Productivity,
+				keptPatterns.DailyActivity, keptPatterns.WorkHours, keptPatterns.Productivity)
+		}
+	}
+	if kept.CommonCmds["kubectl"] != 1 || kept.Insights.ToolUsage.Tracked[0].Count != 3 {
+		t.Errorf("CommonCmds = %v, Tracked = %v, want the aliased kubectl and every git", kept.CommonCmds, kept.Insights.ToolUsage.Tracked)
+	}
+}
@@ -0,0 +1,120 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Optional file of categories kept next to the config file, in the same
+// form as the config file's categories key
+const categoriesFile = "categories.json"
+
+// Confidence for a command that only starts with a pattern (gofmt for go)
+const prefixMatchWeight = 0.5
+
+// Commands that plausibly belong to several categories
+var ambiguousCategories = map[string][]CategoryMatch{
+	"make": {{"development", 0.7}, {"system", 0.3}},
+}
+
+// Categorizer maps each category to the commands that belong to it. A
+// pattern matches a command exactly, or as a prefix with less confidence.
+type Categorizer map[string][]string
+
+// Built-in categories. Categories in categories.json and then the config
+// file replace the default of the same name and add any new ones.
+func DefaultCategories() Categorizer {
+	return Categorizer{
+		"development":     {"npm", "go", "python", "cargo", "node"},
+		"system":          {"sudo", "systemctl", "ps", "top"},
+		"file":            {"ls", "cd", "cp", "mv", "rm"},
+		"network":         {"ssh", "scp", "rsync", "curl", "wget", "nc", "ping", "dig", "nslookup", "telnet"},
+		"containers":      {"docker", "podman", "kubectl", "helm"},
+		"cloud":           {"aws", "gcloud", "az", "terraform"},
+		"editing":         {"vim", "nvim", "nano", "emacs", "code"},
+		"search":          {"grep", "rg", "find", "locate"},
+		"version-control": {"git", "hg", "svn"},
+	}
+}
+
+// Read a categories file into c, replacing a category of the same name and
+// adding new ones. A missing file leaves c as it is.
+func loadCategories(path string, c Categorizer) error {
+	content, err := os.ReadFile(ExpandPath(path))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return FileError(path, err)
+	}
+	if err := json.Unmarshal(content, &c); err != nil {
+		return parseError(path, err)
+	}
+	return nil
+}
+
+func (c Categorizer) categorizeCommand(cmd string) []CategoryMatch {
+	categories := []CategoryMatch{}
+
+	base := BaseCommand(cmd)
+	if base == "" {
+		return categories
+	}
+	if matches, ok := ambiguousCategories[base]; ok {
+		// Only the categories still defined
+		for _, match := range matches {
+			if _, defined := c[match.Name]; defined {
+				categories = append(categories, match)
+			}
+		}
+		return categories
+	}
+
+	for category, patterns := range c {
+		weight := 0.0
+		for _, pattern := range patterns {
+			// An exact command match is certain, a prefix match is a guess
+			if base == pattern {
+				weight = 1
+				break
+			}
+			if strings.HasPrefix(base, pattern) {
+				weight = prefixMatchWeight
+			}
+		}
+		if weight > 0 {
+			categories = append(categories, CategoryMatch{category, weight})
+		}
+	}
+
+	sort.Slice(categories, func(i, j int) bool {
+		if categories[i].Weight != categories[j].Weight {
+			return categories[i].Weight > categories[j].Weight
+		}
+		return categories[i].Name < categories[j].Name
+	})
+	return categories
+}
+
+// Categories of every sub-command in a compound command line, keeping the
+// highest confidence seen for each category
+func (c Categorizer) commandCategories(cmd string) []CategoryMatch {
+	index := make(map[string]int)
+	categories := []CategoryMatch{}
+	for _, sub := range SplitCommand(cmd) {
+		for _, match := range c.categorizeCommand(sub) {
+			i, seen := index[match.Name]
+			if !seen {
+				index[match.Name] = len(categories)
+				categories = append(categories, match)
+				continue
+			}
+			if match.Weight > categories[i].Weight {
+				categories[i].Weight = match.Weight
+			}
+		}
+	}
+	return categories
+}
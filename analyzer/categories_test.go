@@ -0,0 +1,104 @@
+package analyzer
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// Write config.json and, unless empty, categories.json into one directory
+// and return the config file's path
+func writeCategoryFiles(t *testing.T, config, categories string) string {
+	t.Helper()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.json")
+	if config != "" {
+		if err := os.WriteFile(path, []byte(config), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if categories != "" {
+		if err := os.WriteFile(filepath.Join(dir, categoriesFile), []byte(categories), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return path
+}
+
+func categoryNames(matches []CategoryMatch) []string {
+	var names []string
+	for _, match := range matches {
+		names = append(names, match.Name)
+	}
+	return names
+}
+
+func TestCategorizeCommandDefaults(t *testing.T) {
+	categories := DefaultCategories()
+	tests := []struct {
+		cmd  string
+		want []CategoryMatch
+	}{
+		{"git status", []CategoryMatch{{"version-control", 1}}},
+		{"gofmt -l .", []CategoryMatch{{"development", prefixMatchWeight}}},
+		{"ssh host", []CategoryMatch{{"network", 1}}},
+		{"make install", []CategoryMatch{{"development", 0.7}, {"system", 0.3}}},
+		{"docker ps", []CategoryMatch{{"containers", 1}}},
+		{"kubectl get pods", []CategoryMatch{{"containers", 1}}},
+		{"aws s3 ls", []CategoryMatch{{"cloud", 1}}},
+		{"terraform plan", []CategoryMatch{{"cloud", 1}}},
+		{"vim notes", []CategoryMatch{{"editing", 1}}},
+		{"rg TODO", []CategoryMatch{{"search", 1}}},
+		{"find . -name '*.go'", []CategoryMatch{{"search", 1}}},
+		{"svn update", []CategoryMatch{{"version-control", 1}}},
+		{"cargo build", []CategoryMatch{{"development", 1}}},
+		{"xdg-open .", nil},
+		{"", nil},
+	}
+	for _, test := range tests {
+		got := categories.categorizeCommand(test.cmd)
+		if len(got) == 0 && len(test.want) == 0 {
+			continue
+		}
+		if !slices.Equal(got, test.want) {
+			t.Errorf("categorizeCommand(%q) = %v, want %v", test.cmd, got, test.want)
+		}
+	}
+}
+
+func TestLoadConfigCategories(t *testing.T) {
+	tests := []struct {
+		name       string
+		config     string
+		categories string
+		cmd        string
+		want       []string
+	}{
+		{"no files", "", "", "docker ps", []string{"containers"}},
+		{"config without categories", `{}`, "", "ssh host", []string{"network"}},
+		{"categories.json adds", "", `{"databases": ["psql", "mysql"]}`, "mysql -e status", []string{"databases"}},
+		{"categories.json replaces", "", `{"network": ["mosh"]}`, "ssh host", nil},
+		{"defaults kept beside custom", "", `{"deploy": ["docker"]}`, "docker ps", []string{"containers", "deploy"}},
+		{"config key alone", `{"categories": {"cloud": ["aws"]}}`, "", "aws s3 ls", []string{"cloud"}},
+		{"config key wins", `{"categories": {"cloud": ["gcloud"]}}`, `{"cloud": ["aws"], "editing": ["vim"]}`, "aws s3 ls", nil},
+		{"both files merge", `{"categories": {"cloud": ["gcloud"]}}`, `{"cloud": ["aws"], "editing": ["vim"]}`, "vim notes", []string{"editing"}},
+	}
+	for _, test := range tests {
+		cfg, err := LoadConfig(writeCategoryFiles(t, test.config, test.categories))
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if got := categoryNames(cfg.Categories.categorizeCommand(test.cmd)); !slices.Equal(got, test.want) {
+			t.Errorf("%s: categories of %q = %v, want %v", test.name, test.cmd, got, test.want)
+		}
+	}
+}
+
+func TestLoadConfigInvalidCategories(t *testing.T) {
+	_, err := LoadConfig(writeCategoryFiles(t, "", `{"containers": "docker"}`))
+	if !errors.Is(err, ErrParse) {
+		t.Errorf("err = %v, want a parse error", err)
+	}
+}
@@ -0,0 +1,303 @@
+package analyzer
+
+import "strings"
+
+// Split a command line into the simple commands chained by ;, &&, || or
+// newlines. Separators inside quotes, $(...) or backtick substitutions, or
+// after a backslash are left alone. Heredoc bodies are skipped, and groups
+// like "(cd src && make)" or "{ cd src; make; }" yield their inner commands.
+func SplitCommand(cmd string) []string {
+	var parts []string
+	var current strings.Builder
+	var heredocs []string
+
+	inSingle, inDouble, inBacktick := false, false, false
+	depth := 0
+
+	flush := func() {
+		part := strings.TrimSpace(current.String())
+		current.Reset()
+
+		// Brace groups split naturally on their ;, so just drop the braces
+		part = strings.TrimSpace(strings.TrimPrefix(part, "{ "))
+		if part == "" || part == "}" {
+			return
+		}
+		if inner, ok := unwrapGroup(part); ok {
+			parts = append(parts, SplitCommand(inner)...)
+			return
+		}
+		parts = append(parts, part)
+	}
+
+	for i := 0; i < len(cmd); i++ {
+		c := cmd[i]
+
+		switch {
+		case c == '\\' && !inSingle && i+1 < len(cmd):
+			current.WriteByte(c)
+			current.WriteByte(cmd[i+1])
+			i++
+			continue
+		case c == '\'' && !inDouble && !inBacktick:
+			inSingle = !inSingle
+		case c == '"' && !inSingle && !inBacktick:
+			inDouble = !inDouble
+		case c == '`' && !inSingle:
+			inBacktick = !inBacktick
+		}
+
+		quoted := inSingle || inDouble || inBacktick
+		if quoted || (c == '`') {
+			current.WriteByte(c)
+			continue
+		}
+
+		switch {
+		case c == '(':
+			depth++
+		case c == ')' && depth > 0:
+			depth--
+		}
+		if depth > 0 || c == ')' {
+			current.WriteByte(c)
+			continue
+		}
+
+		switch {
+		case c == '<' && strings.HasPrefix(cmd[i:], "<<<"):
+			// A here-string, whose second < mustn't start a heredoc
+			current.WriteString("<<<")
+			i += 2
+		case c == '<' && strings.HasPrefix(cmd[i:], "<<"):
+			delimiter, length := heredocDelimiter(cmd[i+2:])
+			if delimiter != "" {
+				heredocs = append(heredocs, delimiter)
+			}
+			current.WriteString(cmd[i : i+2+length])
+			i += 1 + length
+		case c == '\n':
+			flush()
+			// Skip the bodies of any heredocs opened on the previous line
+			for _, delimiter := range heredocs {
+				for i+1 < len(cmd) {
+					end := strings.IndexByte(cmd[i+1:], '\n')
+					var line string
+					if end < 0 {
+						line = cmd[i+1:]
+						i = len(cmd)
+					} else {
+						line = cmd[i+1 : i+1+end]
+						i += 1 + end
+					}
+					if strings.TrimLeft(line, "\t") == delimiter {
+						break
+					}
+				}
+			}
+			heredocs = nil
+		case c == ';':
+			flush()
+		case (c == '&' || c == '|') && i+1 < len(cmd) && cmd[i+1] == c:
+			flush()
+			i++
+		default:
+			current.WriteByte(c)
+		}
+	}
+	flush()
+
+	return parts
+}
+
+// Reserved words that come before a command rather than being one
+var commandKeywords = map[string]bool{
+	"if": true, "then": true, "else": true, "elif": true, "while": true,
+	"until": true, "do": true, "!": true, "time": true,
+}
+
+// The length of the separators, group braces, keywords and spaces at
+// cmd[i:] before the next simple command starts
+func commandGap(cmd string, i int) int {
+	start := i
+	for i < len(cmd) {
+		c := cmd[i]
+		if strings.IndexByte(" \t\n;&|()", c) >= 0 {
+			i++
+			continue
+		}
+		word := cmd[i:]
+		if end := strings.IndexAny(word, " \t\n"); end >= 0 {
+			word = word[:end]
+		}
+		if word == "{" || word == "}" || commandKeywords[word] {
+			i += len(word)
+			continue
+		}
+		break
+	}
+	return i - start
+}
+
+// Where the simple command starting at cmd[start] ends: at the next ;, &,
+// |, newline or unmatched ) outside quotes, substitutions and groups, or
+// at the end. Also returns the delimiters of the heredocs it opens.
+func simpleCommandEnd(cmd string, start int) (int, []string) {
+	var heredocs []string
+	inSingle, inDouble, inBacktick := false, false, false
+	depth := 0
+
+	for i := start; i < len(cmd); i++ {
+		c := cmd[i]
+		switch {
+		case c == '\\' && !inSingle:
+			i++
+			continue
+		case c == '\'' && !inDouble && !inBacktick:
+			inSingle = !inSingle
+			continue
+		case c == '"' && !inSingle && !inBacktick:
+			inDouble = !inDouble
+			continue
+		case c == '`' && !inSingle:
+			inBacktick = !inBacktick
+			continue
+		}
+		if inSingle || inDouble || inBacktick {
+			continue
+		}
+
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			if depth == 0 {
+				return i, heredocs
+			}
+			depth--
+		case ';', '\n':
+			if depth == 0 {
+				return i, heredocs
+			}
+		case '&', '|':
+			// Redirections like 2>&1, &> and >| don't end the command
+			redirect := i > start && (cmd[i-1] == '>' || cmd[i-1] == '<') ||
+				c == '&' && i+1 < len(cmd) && cmd[i+1] == '>'
+			if depth == 0 && !redirect {
+				return i, heredocs
+			}
+		case '<':
+			if depth > 0 || !strings.HasPrefix(cmd[i:], "<<") {
+				continue
+			}
+			if strings.HasPrefix(cmd[i:], "<<<") {
+				i += 2
+				continue
+			}
+			delimiter, length := heredocDelimiter(cmd[i+2:])
+			if delimiter != "" {
+				heredocs = append(heredocs, delimiter)
+			}
+			i += 1 + length
+		}
+	}
+	return len(cmd), heredocs
+}
+
+// Skip the bodies of heredocs from the newline at cmd[i] that ends the
+// line opening them, returning the index of the newline after the last
+// delimiter line, or the end of cmd
+func heredocBodiesEnd(cmd string, i int, delimiters []string) int {
+	for _, delimiter := range delimiters {
+		for i < len(cmd) {
+			start := i + 1
+			end := strings.IndexByte(cmd[start:], '\n')
+			var line string
+			if end < 0 {
+				line, i = cmd[start:], len(cmd)
+			} else {
+				line, i = cmd[start:start+end], start+end
+			}
+			if strings.TrimLeft(line, "\t") == delimiter {
+				break
+			}
+		}
+	}
+	return i
+}
+
+// Read the delimiter word after a heredoc operator, returning it unquoted
+// along with how many bytes of input it consumed
+func heredocDelimiter(rest string) (string, int) {
+	i := 0
+	if i < len(rest) && rest[i] == '-' {
+		i++
+	}
+	for i < len(rest) && rest[i] == ' ' {
+		i++
+	}
+	start := i
+	for i < len(rest) && !strings.ContainsRune(" \t\n;&|<>()", rune(rest[i])) {
+		i++
+	}
+	return strings.Trim(rest[start:i], `'"\`), i
+}
+
+// Strip the parentheses from a subshell group
+func unwrapGroup(part string) (string, bool) {
+	if strings.HasPrefix(part, "(") && strings.HasSuffix(part, ")") {
+		return part[1 : len(part)-1], true
+	}
+	return "", false
+}
+
+// Commands that run another command with elevated privileges
+var privilegeWrappers = map[string]bool{"sudo": true, "doas": true}
+
+// Options of sudo, doas and env that take a value as the next word
+var wrapperOptionArgs = map[string]bool{
+	"-u": true, "-g": true, "-C": true, "-D": true, "-h": true, "-p": true, "-r": true, "-t": true,
+	"-U": true, "-T": true, "--user": true, "--group": true, "--unset": true, "--chdir": true,
+}
+
+// The words of a simple command from the program it really runs, after
+// any sudo, doas or env wrappers and variable assignments, and whether a
+// privilege wrapper was stripped. A wrapper with nothing after it, like a
+// bare sudo -i, is kept as the program.
+func unwrapCommand(cmd string) ([]string, bool) {
+	fields := strings.Fields(cmd)
+	privileged := false
+	for len(fields) > 0 {
+		wrapper := fields[0]
+		switch {
+		case privilegeWrappers[wrapper] || wrapper == "env":
+			rest := fields[1:]
+			for len(rest) > 0 && strings.HasPrefix(rest[0], "-") {
+				if wrapperOptionArgs[rest[0]] && len(rest) > 1 {
+					rest = rest[1:]
+				}
+				rest = rest[1:]
+			}
+			if len(rest) == 0 {
+				return fields, privileged || privilegeWrappers[wrapper]
+			}
+			privileged = privileged || privilegeWrappers[wrapper]
+			fields = rest
+		case strings.Contains(wrapper, "=") && !strings.HasPrefix(wrapper, "="):
+			fields = fields[1:]
+		default:
+			return fields, privileged
+		}
+	}
+	return fields, privileged
+}
+
+// The program a simple command runs, its first word after any sudo, doas
+// or env wrapper, or "" when empty
+func BaseCommand(cmd string) string {
+	fields, _ := unwrapCommand(cmd)
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[0]
+}
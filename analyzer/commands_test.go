@@ -0,0 +1,130 @@
+package analyzer
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSplitCommand(t *testing.T) {
+	tests := []struct {
+		cmd  string
+		want []string
+	}{
+		{"ls", []string{"ls"}},
+		{"cd src && make || echo failed; ls", []string{"cd src", "make", "echo failed", "ls"}},
+		{"git add . &&git commit", []string{"git add .", "git commit"}},
+		{"cat log | grep error", []string{"cat log | grep error"}},
+		{"sleep 1 & echo done", []string{"sleep 1 & echo done"}},
+
+		// Separators that must not split
+		{`echo "a; b && c"`, []string{`echo "a; b && c"`}},
+		{`echo 'x || y'; pwd`, []string{`echo 'x || y'`, "pwd"}},
+		{`grep "it's; fine" file`, []string{`grep "it's; fine" file`}},
+		{`echo a\;b && ls`, []string{`echo a\;b`, "ls"}},
+		{"echo $(date; whoami) && ls", []string{"echo $(date; whoami)", "ls"}},
+		{"echo `date; whoami`", []string{"echo `date; whoami`"}},
+		{`find . -exec rm {} \;`, []string{`find . -exec rm {} \;`}},
+
+		// Groups yield their inner commands
+		{"(cd src && make)", []string{"cd src", "make"}},
+		{"{ cd src; make; }", []string{"cd src", "make"}},
+
+		// Heredoc bodies aren't commands
+		{"cat <<EOF\nrm -rf /; reboot\nEOF\nls", []string{"cat <<EOF", "ls"}},
+		{"cat <<-'END' > out\n\tnot; a && command\n\tEND", []string{"cat <<-'END' > out"}},
+		{"cat <<< 'a; b'", []string{"cat <<< 'a; b'"}},
+
+		{"", nil},
+		{" ; ;", nil},
+	}
+	for _, test := range tests {
+		if got := SplitCommand(test.cmd); !slices.Equal(got, test.want) {
+			t.Errorf("splitCommand(%q) = %q, want %q", test.cmd, got, test.want)
+		}
+	}
+}
+
+func TestCommandCategoriesOfCompoundCommands(t *testing.T) {
+	categories := DefaultCategories()
+	tests := []struct {
+		cmd  string
+		want []string
+	}{
+		{"git pull && ssh host", []string{"version-control", "network"}},
+		{"cd src; ls", []string{"file"}},
+		{`echo "ssh host; curl x"`, nil},
+		{"(cd src && docker build .)", []string{"file", "containers"}},
+	}
+	for _, test := range tests {
+		var got []string
+		for _, match := range categories.commandCategories(test.cmd) {
+			got = append(got, match.Name)
+		}
+		if !slices.Equal(got, test.want) {
+			t.Errorf("commandCategories(%q) = %v, want %v", test.cmd, got, test.want)
+		}
+	}
+}
+
+func TestBaseCommand(t *testing.T) {
+	tests := map[string]string{
+		`git commit -m "fix"`: "git",
+		"/usr/bin/python3 -V": "/usr/bin/python3",
+		"  ls":                "ls",
+		"":                    "",
+	}
+	for cmd, want := range tests {
+		if got := BaseCommand(cmd); got != want {
+			t.Errorf("baseCommand(%q) = %q, want %q", cmd, got, want)
+		}
+	}
+}
+
+func TestUnwrapCommand(t *testing.T) {
+	tests := []struct {
+		cmd        string
+		want       []string
+		privileged bool
+	}{
+		{"docker ps", []string{"docker", "ps"}, false},
+		{"sudo docker ps", []string{"docker", "ps"}, true},
+		{"sudo -u deploy -H systemctl restart nginx", []string{"systemctl", "restart", "nginx"}, true},
+		{"doas apk add curl", []string{"apk", "add", "curl"}, true},
+		{"env GOOS=linux go build", []string{"go", "build"}, false},
+		{"env -u HOME make", []string{"make"}, false},
+		{"FOO=1 BAR=2 npm test", []string{"npm", "test"}, false},
+		{"sudo env PATH=/opt/bin make install", []string{"make", "install"}, true},
+
+		// A wrapper on its own is the command
+		{"sudo -i", []string{"sudo", "-i"}, true},
+		{"env", []string{"env"}, false},
+		{"", nil, false},
+	}
+	for _, test := range tests {
+		got, privileged := unwrapCommand(test.cmd)
+		if !slices.Equal(got, test.want) || privileged != test.privileged {
+			t.Errorf("unwrapCommand(%q) = %q, %v; want %q, %v", test.cmd, got, privileged, test.want, test.privileged)
+		}
+	}
+	if got := BaseCommand("sudo docker ps"); got != "docker" {
+		t.Errorf("baseCommand(sudo docker ps) = %q, want docker", got)
+	}
+}
+
+func TestCategorizeWrappedCommands(t *testing.T) {
+	categories := DefaultCategories()
+	for cmd, want := range map[string][]string{
+		"sudo docker ps":               {"containers"},
+		"sudo systemctl restart nginx": {"system"},
+		"doas ssh host":                {"network"},
+		"env DEBUG=1 git status":       {"version-control"},
+	} {
+		var got []string
+		for _, match := range categories.categorizeCommand(cmd) {
+			got = append(got, match.Name)
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("categorizeCommand(%q) = %v, want %v", cmd, got, want)
+		}
+	}
+}
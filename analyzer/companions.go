@@ -0,0 +1,30 @@
+package analyzer
+
+import "strings"
+
+// CompanionRule suggests a tool or feature to users of another one: if
+// Uses is run but none of Suggest ever is, Tip is shown. Both match
+// command prefixes, so "git stash" matches "git stash pop".
+type CompanionRule struct {
+	Uses    string   `json:"uses"`
+	Suggest []string `json:"suggest"`
+	Tip     string   `json:"tip"`
+}
+
+func defaultCompanionRules() []CompanionRule {
+	return []CompanionRule{
+		{"git", []string{"git stash"}, "Use `git stash` to set work aside instead of committing half-done changes"},
+		{"git", []string{"git switch", "git worktree"}, "Try `git switch` for changing branches, or `git worktree` to check out several at once"},
+		{"docker", []string{"docker compose", "docker-compose"}, "Use `docker compose` to describe multi-container setups in a file instead of long `docker run` lines"},
+		{"kubectl", []string{"k9s", "kubectx", "kubens"}, "Try k9s for browsing clusters, or kubectx/kubens for switching contexts"},
+		{"cd", []string{"z", "zoxide", "j", "autojump"}, "Try zoxide (`z`) to jump to frequent directories by name"},
+		{"grep", []string{"rg", "ag"}, "Try ripgrep (`rg`), a much faster grep that respects .gitignore"},
+		{"find", []string{"fd", "fdfind"}, "Try fd, a simpler and faster alternative to find"},
+		{"curl", []string{"jq"}, "Pipe JSON responses through jq to read and filter them"},
+		{"history", []string{"fzf", "atuin"}, "Try fzf or atuin for searching your history interactively"},
+	}
+}
+
+func MatchesCommandPrefix(cmd, prefix string) bool {
+	return cmd == prefix || strings.HasPrefix(cmd, prefix+" ")
+}
@@ -0,0 +1,260 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"time"
+)
+
+// A leading history index, like "  42  git status" from saved history
+// output or " 108* vim" for an edited entry
+const defaultHistoryPrefix = `^\s*\d+\*?\s+`
+
+// Config holds user settings read from the config file
+type Config struct {
+	AliasExpansionDepth int         `json:"alias_expansion_depth"`
+	TrackedTools        []string    `json:"tracked_tools"`
+	RoleWeights         RoleWeights `json:"role_weights"`
+	Theme               Theme       `json:"theme"`
+
+	// Switches for reading history and config files, globally and per
+	// shell. Both default to on.
+	AnalyzeHistory *bool                    `json:"analyze_history"`
+	AnalyzeConfig  *bool                    `json:"analyze_config"`
+	Shells         map[string]ShellSettings `json:"shells"`
+
+	// "dashboard" to start with all main sections shown at once
+	Layout string `json:"layout"`
+
+	// Tools grouped into stacks like frontend, backend and devops
+	Stacks map[string][]string `json:"stacks"`
+
+	// Commands grouped into categories like development and network
+	Categories Categorizer `json:"categories"`
+
+	// Named filters selectable in the Queries view
+	SavedQueries []SavedQuery `json:"saved_queries"`
+
+	// "If you use X but not Y, suggest Y" rules for the Tips view
+	CompanionRules []CompanionRule `json:"companion_rules"`
+
+	// Decrypt commands for encrypted history files, keyed by extension
+	DecryptCommands map[string][]string `json:"decrypt_commands"`
+
+	// Bits per character above which a command argument is flagged as a
+	// probable secret
+	SecretEntropyThreshold float64 `json:"secret_entropy_threshold"`
+
+	// How many version probes run at once, and how long any external
+	// command may run before it's killed
+	ProbeConcurrency      int     `json:"probe_concurrency"`
+	CommandTimeoutSeconds float64 `json:"command_timeout_seconds"`
+
+	// How long a decrypt command may run before it's killed
+	DecryptTimeoutSeconds float64 `json:"decrypt_timeout_seconds"`
+
+	// Longest history line read, in bytes; longer ones are skipped
+	MaxLineBytes int `json:"max_line_bytes"`
+
+	// Most commands kept per history, newest first; older ones only
+	// update totals. 0 keeps everything.
+	MaxEntries int `json:"max_entries"`
+
+	// Regex stripped from the start of every history line before parsing.
+	// Defaults to a leading history index; "" strips nothing.
+	HistoryPrefix      string `json:"history_prefix"`
+	historyPrefixRegex *regexp.Regexp
+
+	// Regexes for history lines that are prompt output rather than
+	// commands. Replaces the built-in patterns when set.
+	PromptNoisePatterns []string `json:"prompt_noise_patterns"`
+	promptNoise         []*regexp.Regexp
+
+	// Regexes for text that must never be written to the log file
+	LogDenylist []string `json:"log_denylist"`
+	logDenylist []*regexp.Regexp
+
+	// Glob patterns for directories kept out of the Projects view and
+	// exports, like "~/clients/*"
+	PrivatePaths []string `json:"private_paths"`
+
+	// When the user means to be working, for the in-hours vs off-hours
+	// breakdown in Work Patterns
+	WorkHours WorkHours `json:"work_hours"`
+
+	// How often the TUI re-runs the analysis, or 0 to never refresh
+	RefreshIntervalMinutes float64 `json:"refresh_interval_minutes"`
+
+	// Merge all shells into one timeline, set with -timeline
+	Timeline bool `json:"-"`
+
+	// History file paths per shell, set with -history
+	HistoryPaths map[string]string `json:"-"`
+
+	// The only shells analyzed, or empty for all of them, and whether to
+	// skip probing installed languages. Set through Analyze.
+	shells  []string
+	noProbe bool
+
+	// The home directory ~ stands for when reading histories, configs and
+	// snapshots, or empty for the user's. Set through Analyze.
+	home string
+
+	// Only count commands into the totals as histories are read, keeping
+	// none of them. Set through Analyze unless entries are asked for.
+	countOnly bool
+
+	// The login shell, like "zsh", or empty for $SHELL. Set through
+	// Analyze.
+	login string
+
+	// Leave config file contents out of JSON exports, set with
+	// -omit-config-contents
+	OmitConfigContents bool `json:"-"`
+
+	// The only view text, Markdown and HTML reports include, like
+	// "tech-profile", set with -section. Empty for all of them.
+	Section string `json:"-"`
+}
+
+// Theme holds colors used by the visualizations
+type Theme struct {
+	// From no activity to the most activity, as hex colors
+	CalendarColors []string `json:"calendar_colors"`
+}
+
+var defaultCalendarColors = []string{"#2d333b", "#0e4429", "#006d32", "#26a641", "#39d353"}
+
+func (t Theme) CalendarPalette() []string {
+	if len(t.CalendarColors) < 2 {
+		return defaultCalendarColors
+	}
+	return t.CalendarColors
+}
+
+func DefaultConfig() Config {
+	// The built-in patterns are known to compile
+	promptNoise, _ := compilePromptNoise(defaultPromptNoisePatterns)
+
+	return Config{
+		AliasExpansionDepth: 5,
+		RoleWeights:         defaultRoleWeights(),
+		DecryptCommands:     defaultDecryptCommands(),
+		Stacks:              defaultStacks(),
+		Categories:          DefaultCategories(),
+		CompanionRules:      defaultCompanionRules(),
+		MaxLineBytes:        defaultMaxLineBytes,
+		ProbeConcurrency:    defaultProbeConcurrency,
+		WorkHours:           defaultWorkHours(),
+
+		HistoryPrefix:      defaultHistoryPrefix,
+		historyPrefixRegex: regexp.MustCompile(defaultHistoryPrefix),
+
+		PromptNoisePatterns: slices.Clone(defaultPromptNoisePatterns),
+		promptNoise:         promptNoise,
+
+		SecretEntropyThreshold: defaultSecretEntropyThreshold,
+	}
+}
+
+// Expand ~ in a path to the home directory being analyzed
+func (c Config) ExpandPath(path string) string {
+	return expandHomePath(path, c.home)
+}
+
+// The login shell of the user being analyzed. $SHELL only describes the
+// user's own, so another home directory has none unless one was given.
+func (c Config) loginShell() string {
+	if c.login != "" || c.home != "" {
+		return c.login
+	}
+	return loginShell()
+}
+
+func (c Config) CommandTimeout() time.Duration {
+	if c.CommandTimeoutSeconds <= 0 {
+		return defaultCommandTimeout
+	}
+	return time.Duration(c.CommandTimeoutSeconds * float64(time.Second))
+}
+
+func (c Config) decryptTimeout() time.Duration {
+	if c.DecryptTimeoutSeconds <= 0 {
+		return defaultDecryptTimeout
+	}
+	return time.Duration(c.DecryptTimeoutSeconds * float64(time.Second))
+}
+
+// Load the config file, falling back to defaults when it doesn't exist
+func LoadConfig(path string) (Config, error) {
+	config := DefaultConfig()
+
+	// categories.json next to the config file comes first, so the config
+	// file's own categories key wins over it
+	if err := loadCategories(filepath.Join(filepath.Dir(path), categoriesFile), config.Categories); err != nil {
+		return config, err
+	}
+
+	content, err := os.ReadFile(ExpandPath(path))
+	if os.IsNotExist(err) {
+		return config, nil
+	}
+	if err != nil {
+		return config, FileError(path, err)
+	}
+
+	if err := json.Unmarshal(content, &config); err != nil {
+		return DefaultConfig(), parseError(path, err)
+	}
+
+	config.historyPrefixRegex = nil
+	if config.HistoryPrefix != "" {
+		regex, err := regexp.Compile(config.HistoryPrefix)
+		if err != nil {
+			return DefaultConfig(), parseError(path, err)
+		}
+		config.historyPrefixRegex = regex
+	}
+
+	if config.promptNoise, err = compilePromptNoise(config.PromptNoisePatterns); err != nil {
+		return DefaultConfig(), parseError(path, err)
+	}
+
+	for _, pattern := range config.LogDenylist {
+		regex, err := regexp.Compile(pattern)
+		if err != nil {
+			return DefaultConfig(), parseError(path, err)
+		}
+		config.logDenylist = append(config.logDenylist, regex)
+	}
+
+	if err := validatePrivatePaths(config.PrivatePaths); err != nil {
+		return DefaultConfig(), parseError(path, err)
+	}
+
+	if err := validateSavedQueries(config.SavedQueries); err != nil {
+		return DefaultConfig(), parseError(path, err)
+	}
+
+	if err := config.WorkHours.compile(); err != nil {
+		return DefaultConfig(), parseError(path, err)
+	}
+
+	if config.AliasExpansionDepth < 0 {
+		config.AliasExpansionDepth = 0
+	}
+	if config.SecretEntropyThreshold <= 0 {
+		config.SecretEntropyThreshold = defaultSecretEntropyThreshold
+	}
+	if config.ProbeConcurrency <= 0 {
+		config.ProbeConcurrency = defaultProbeConcurrency
+	}
+	if config.MaxLineBytes <= 0 {
+		config.MaxLineBytes = defaultMaxLineBytes
+	}
+
+	return config, nil
+}
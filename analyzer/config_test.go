@@ -0,0 +1,52 @@
+package analyzer
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Write a config file into a temp directory and return its path
+func writeTestConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadConfigHistoryPrefix(t *testing.T) {
+	cfg, err := LoadConfig(writeTestConfig(t, `{"history_prefix": "^\\[[^\\]]*\\]\\s*"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := cleanHistoryLine("[10:00] make test", cfg.historyPrefixRegex); got != "make test" {
+		t.Errorf("configured prefix left %q", got)
+	}
+
+	_, err = LoadConfig(writeTestConfig(t, `{"history_prefix": "(unclosed"}`))
+	if !errors.Is(err, ErrParse) {
+		t.Errorf("invalid history_prefix: err = %v, want a parse error", err)
+	}
+}
+
+func TestLoadConfigHistoryPrefixDefault(t *testing.T) {
+	cfg, err := LoadConfig(writeTestConfig(t, `{}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := cleanHistoryLine("  42  git status", cfg.historyPrefixRegex); got != "git status" {
+		t.Errorf("default prefix left %q", got)
+	}
+
+	// An empty prefix turns stripping off
+	cfg, err = LoadConfig(writeTestConfig(t, `{"history_prefix": ""}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := cleanHistoryLine("42 git status", cfg.historyPrefixRegex); got != "42 git status" {
+		t.Errorf("with stripping off got %q", got)
+	}
+}
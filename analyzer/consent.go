@@ -0,0 +1,28 @@
+package analyzer
+
+// Parts of the analysis that can be turned off
+const (
+	SkippedHistory = "history"
+	SkippedConfig  = "config"
+)
+
+// ShellSettings overrides the global analysis switches for one shell.
+// Unset fields inherit the global value.
+type ShellSettings struct {
+	AnalyzeHistory *bool `json:"analyze_history"`
+	AnalyzeConfig  *bool `json:"analyze_config"`
+}
+
+func (c Config) HistoryEnabled(shell string) bool {
+	if setting := c.Shells[shell].AnalyzeHistory; setting != nil {
+		return *setting
+	}
+	return c.AnalyzeHistory == nil || *c.AnalyzeHistory
+}
+
+func (c Config) analyzeConfig(shell string) bool {
+	if setting := c.Shells[shell].AnalyzeConfig; setting != nil {
+		return *setting
+	}
+	return c.AnalyzeConfig == nil || *c.AnalyzeConfig
+}
@@ -0,0 +1,133 @@
+package analyzer
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// DangerRule recognizes a simple command that can destroy data or open up
+// the machine, with why it's dangerous
+type DangerRule struct {
+	Reason string
+	Match  func(fields []string, cmd string) bool
+}
+
+// DangerousAlias is an alias whose expansion, following alias chains,
+// runs a dangerous command
+type DangerousAlias struct {
+	Shell     string
+	Name      string
+	Expansion string
+	Reason    string
+}
+
+var (
+	pipeToShellRegex = regexp.MustCompile(`\b(curl|wget)\b[^|]*\|\s*(sudo\s+)?(ba|z|k|da|fi)?sh\b`)
+	forkBombRegex    = regexp.MustCompile(`:\(\)\s*\{\s*:\s*\|\s*:\s*&\s*\}\s*;\s*:`)
+	rawDiskRegex     = regexp.MustCompile(`\bof=/dev/(sd|hd|nvme|disk|mmcblk)`)
+)
+
+var dangerousCommands = []DangerRule{
+	{"deletes recursively without asking", func(fields []string, cmd string) bool {
+		if fields[0] != "rm" {
+			return false
+		}
+		recursive, force := false, false
+		for _, arg := range fields[1:] {
+			switch {
+			case arg == "--recursive":
+				recursive = true
+			case arg == "--force":
+				force = true
+			case strings.HasPrefix(arg, "-") && !strings.HasPrefix(arg, "--"):
+				recursive = recursive || strings.ContainsAny(arg, "rR")
+				force = force || strings.Contains(arg, "f")
+			}
+		}
+		return recursive && force
+	}},
+	{"pipes a download straight into a shell", func(fields []string, cmd string) bool {
+		return pipeToShellRegex.MatchString(cmd)
+	}},
+	{"makes files writable by everyone", func(fields []string, cmd string) bool {
+		if fields[0] != "chmod" {
+			return false
+		}
+		for _, arg := range fields[1:] {
+			if arg == "777" || arg == "0777" || arg == "a+rwx" {
+				return true
+			}
+		}
+		return false
+	}},
+	{"overwrites a disk or partition", func(fields []string, cmd string) bool {
+		return strings.HasPrefix(fields[0], "mkfs") || fields[0] == "dd" && rawDiskRegex.MatchString(cmd)
+	}},
+	{"overwrites remote history", func(fields []string, cmd string) bool {
+		if len(fields) < 2 || fields[0] != "git" || fields[1] != "push" {
+			return false
+		}
+		for _, arg := range fields[2:] {
+			if arg == "--force" || arg == "-f" {
+				return true
+			}
+		}
+		return false
+	}},
+	{"discards uncommitted work", func(fields []string, cmd string) bool {
+		if len(fields) < 3 || fields[0] != "git" {
+			return false
+		}
+		switch fields[1] {
+		case "reset":
+			return fields[2] == "--hard"
+		case "clean":
+			return strings.HasPrefix(fields[2], "-") && strings.Contains(fields[2], "f")
+		}
+		return false
+	}},
+}
+
+// Why a command line is dangerous, or "" when it isn't. Each chained
+// command is checked on its own, ignoring a leading sudo, doas or env
+// wrapper, except for fork bombs which only exist as a chain.
+func dangerReason(line string) string {
+	if forkBombRegex.MatchString(line) {
+		return "is a fork bomb"
+	}
+	for _, cmd := range SplitCommand(line) {
+		fields, _ := unwrapCommand(cmd)
+		if len(fields) == 0 {
+			continue
+		}
+		for _, rule := range dangerousCommands {
+			if rule.Match(fields, cmd) {
+				return rule.Reason
+			}
+		}
+	}
+	return ""
+}
+
+// Aliases in every shell's config that expand to a dangerous command
+func findDangerousAliases(configs map[string]ShellConfig, depth int) []DangerousAlias {
+	var found []DangerousAlias
+	for shell, config := range configs {
+		for name := range config.Aliases {
+			// Follow chains like rmf → rmr → rm -rf, at least one level deep
+			expansion, _, _ := expandAlias(name, config.Aliases, max(depth, 1))
+			if reason := dangerReason(expansion); reason != "" {
+				found = append(found, DangerousAlias{shell, name, expansion, reason})
+			}
+		}
+	}
+
+	sort.Slice(found, func(i, j int) bool {
+		if found[i].Shell != found[j].Shell {
+			return found[i].Shell < found[j].Shell
+		}
+		return found[i].Name < found[j].Name
+	})
+	return found
+}
@@ -0,0 +1,89 @@
+package analyzer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+var ErrDecrypt = errors.New("decryption failed")
+
+// Decrypting a large history can take a while, so it gets longer than
+// other commands
+const defaultDecryptTimeout = 30 * time.Second
+
+// Commands used to decrypt history files by extension. The file path is
+// appended as the last argument and plaintext is read from stdout.
+func defaultDecryptCommands() map[string][]string {
+	return map[string][]string{
+		".age": {"age", "--decrypt", "--identity", "~/.config/age/keys.txt"},
+		".gpg": {"gpg", "--quiet", "--batch", "--decrypt"},
+	}
+}
+
+// Use an encrypted copy of a history file when the plain one doesn't exist
+func resolveHistoryPath(path string, decrypt map[string][]string) string {
+	if _, err := os.Stat(path); err == nil {
+		return path
+	}
+	for ext := range decrypt {
+		if _, err := os.Stat(path + ext); err == nil {
+			return path + ext
+		}
+	}
+	return path
+}
+
+// Open a history file, decrypting it in memory when its extension has a
+// decrypt command configured. Plaintext never touches the disk.
+func openHistory(path string, cfg Config) (io.ReadCloser, error) {
+	args, encrypted := cfg.DecryptCommands[filepath.Ext(path)]
+	if !encrypted || len(args) == 0 {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, FileError(path, err)
+		}
+		return file, nil
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		return nil, FileError(path, err)
+	}
+
+	expanded := make([]string, 0, len(args)+1)
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "~") {
+			arg = cfg.ExpandPath(arg)
+		}
+		expanded = append(expanded, arg)
+	}
+	expanded = append(expanded, path)
+
+	timeout := cfg.decryptTimeout()
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, expanded[0], expanded[1:]...)
+	cmd.Stderr = &stderr
+	cmd.WaitDelay = timeout
+	plaintext, err := cmd.Output()
+	if err != nil {
+		cause := err
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			cause = fmt.Errorf("%s timed out after %s", expanded[0], timeout)
+		} else if message := strings.TrimSpace(stderr.String()); message != "" {
+			cause = fmt.Errorf("%w: %s", err, message)
+		}
+		return nil, &AnalysisError{Path: path, Kind: ErrDecrypt, Cause: cause}
+	}
+
+	return io.NopCloser(bytes.NewReader(plaintext)), nil
+}
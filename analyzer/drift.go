@@ -0,0 +1,200 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// What changed in a shell config
+const (
+	driftAlias  = "alias"
+	driftPlugin = "plugin"
+	driftEnv    = "env"
+
+	DriftAdded   = "added"
+	DriftRemoved = "removed"
+	DriftChanged = "changed"
+)
+
+// ConfigSummary is what a snapshot records of one shell's config, enough
+// to tell what changed between runs. Environment values are left out,
+// since they may hold secrets.
+type ConfigSummary struct {
+	Aliases     map[string]string `json:"aliases"`
+	Plugins     []string          `json:"plugins"`
+	Environment []string          `json:"environment"`
+}
+
+// ConfigChange is one alias, plugin or environment variable added,
+// removed or changed in a shell's config between two runs
+type ConfigChange struct {
+	Time   time.Time
+	Shell  string
+	Kind   string
+	Name   string
+	Change string
+}
+
+// ConfigDrift is how the shell configs changed across snapshots and the
+// current run
+type ConfigDrift struct {
+	// When the first snapshot with a config summary was taken, zero when
+	// there's none yet
+	Since time.Time
+
+	// Snapshots with config summaries, and older ones taken before
+	// snapshots recorded configs
+	Recorded   int
+	Unrecorded int
+
+	Changes []ConfigChange
+}
+
+func summarizeConfigs(configs map[string]ShellConfig) map[string]ConfigSummary {
+	summaries := make(map[string]ConfigSummary)
+	for shell, config := range configs {
+		summary := ConfigSummary{Aliases: make(map[string]string)}
+		for name, expansion := range config.Aliases {
+			summary.Aliases[name] = expansion
+		}
+		for _, plugin := range config.Plugins {
+			summary.Plugins = append(summary.Plugins, plugin.Name)
+		}
+		for name := range config.Environment {
+			summary.Environment = append(summary.Environment, name)
+		}
+		sort.Strings(summary.Plugins)
+		sort.Strings(summary.Environment)
+		summaries[shell] = summary
+	}
+	return summaries
+}
+
+// Every snapshot listed in the manifest, oldest first. Snapshots that
+// can't be read are skipped, so one corrupt file doesn't hide the rest.
+func loadSnapshots(dir string) ([]Snapshot, error) {
+	manifest, err := loadManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshots []Snapshot
+	for _, entry := range manifest.Snapshots {
+		content, err := os.ReadFile(filepath.Join(dir, entry.File))
+		if err != nil {
+			continue
+		}
+		var snapshot Snapshot
+		if err := json.Unmarshal(content, &snapshot); err != nil {
+			continue
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	return snapshots, nil
+}
+
+// Names added to and removed from a sorted list
+func diffNames(before, after []string) (added, removed []string) {
+	inBefore := make(map[string]bool)
+	for _, name := range before {
+		inBefore[name] = true
+	}
+	inAfter := make(map[string]bool)
+	for _, name := range after {
+		inAfter[name] = true
+		if !inBefore[name] {
+			added = append(added, name)
+		}
+	}
+	for _, name := range before {
+		if !inAfter[name] {
+			removed = append(removed, name)
+		}
+	}
+	return added, removed
+}
+
+// The changes from one set of config summaries to the next. Only shells
+// in both are compared, so a shell whose config analysis was turned off
+// for one run doesn't show every alias as removed.
+func diffConfigs(before, after map[string]ConfigSummary, at time.Time) []ConfigChange {
+	var changes []ConfigChange
+	add := func(shell, kind, change string, names []string) {
+		for _, name := range names {
+			changes = append(changes, ConfigChange{Time: at, Shell: shell, Kind: kind, Name: name, Change: change})
+		}
+	}
+
+	for shell, next := range after {
+		prev, ok := before[shell]
+		if !ok {
+			continue
+		}
+
+		var prevAliases, nextAliases, changed []string
+		for name, expansion := range prev.Aliases {
+			prevAliases = append(prevAliases, name)
+			if newExpansion, ok := next.Aliases[name]; ok && newExpansion != expansion {
+				changed = append(changed, name)
+			}
+		}
+		for name := range next.Aliases {
+			nextAliases = append(nextAliases, name)
+		}
+		sort.Strings(prevAliases)
+		sort.Strings(nextAliases)
+		sort.Strings(changed)
+
+		added, removed := diffNames(prevAliases, nextAliases)
+		add(shell, driftAlias, DriftAdded, added)
+		add(shell, driftAlias, DriftRemoved, removed)
+		add(shell, driftAlias, DriftChanged, changed)
+
+		added, removed = diffNames(prev.Plugins, next.Plugins)
+		add(shell, driftPlugin, DriftAdded, added)
+		add(shell, driftPlugin, DriftRemoved, removed)
+
+		added, removed = diffNames(prev.Environment, next.Environment)
+		add(shell, driftEnv, DriftAdded, added)
+		add(shell, driftEnv, DriftRemoved, removed)
+	}
+	return changes
+}
+
+// Compare each snapshot's configs with the one before it, and the last
+// with the current run
+func analyzeConfigDrift(snapshots []Snapshot, current map[string]ConfigSummary, now time.Time) ConfigDrift {
+	var drift ConfigDrift
+	var previous map[string]ConfigSummary
+	for _, snapshot := range snapshots {
+		if snapshot.Config == nil {
+			drift.Unrecorded++
+			continue
+		}
+		if previous == nil {
+			drift.Since = snapshot.Taken
+		} else {
+			drift.Changes = append(drift.Changes, diffConfigs(previous, snapshot.Config, snapshot.Taken)...)
+		}
+		drift.Recorded++
+		previous = snapshot.Config
+	}
+	if previous != nil {
+		drift.Changes = append(drift.Changes, diffConfigs(previous, current, now)...)
+	}
+
+	sort.SliceStable(drift.Changes, func(i, j int) bool {
+		a, b := drift.Changes[i], drift.Changes[j]
+		if !a.Time.Equal(b.Time) {
+			return a.Time.After(b.Time)
+		}
+		if a.Shell != b.Shell {
+			return a.Shell < b.Shell
+		}
+		return a.Kind < b.Kind
+	})
+	return drift
+}
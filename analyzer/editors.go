@@ -0,0 +1,74 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// Editor commands, mapped to the name reported for them
+var editorCommands = map[string]string{
+	"vi":          "vim",
+	"vim":         "vim",
+	"nvim":        "neovim",
+	"emacs":       "emacs",
+	"emacsclient": "emacs",
+	"nano":        "nano",
+	"micro":       "micro",
+	"hx":          "helix",
+	"kak":         "kakoune",
+	"code":        "vscode",
+	"codium":      "vscode",
+	"subl":        "sublime",
+	"gedit":       "gedit",
+}
+
+const (
+	fileTypeDirectory   = "directory"
+	fileTypeNoExtension = "no extension"
+)
+
+// Count editor invocations and the kinds of files they were opened on
+func detectEditorUse(cmd string, usage *ToolUsage) {
+	fields, _ := unwrapCommand(cmd)
+	if len(fields) == 0 {
+		return
+	}
+	editor, ok := editorCommands[filepath.Base(fields[0])]
+	if !ok {
+		return
+	}
+	usage.Editors[editor]++
+
+	for _, arg := range fields[1:] {
+		// Skip flags and vim-style +line arguments
+		if strings.HasPrefix(arg, "-") || strings.HasPrefix(arg, "+") {
+			continue
+		}
+		path := strings.Trim(arg, `"'`)
+		if path == "" {
+			continue
+		}
+		fileType := editorFileType(path)
+		if usage.EditorFileTypes[editor] == nil {
+			usage.EditorFileTypes[editor] = make(map[string]int)
+		}
+		usage.EditorFileTypes[editor][fileType]++
+	}
+}
+
+// Classify an editor argument by extension, or as a directory for things
+// like "code ." that open a whole project
+func editorFileType(path string) string {
+	if path == "." || path == ".." || strings.HasSuffix(path, "/") {
+		return fileTypeDirectory
+	}
+	base := filepath.Base(path)
+	if ext := strings.ToLower(filepath.Ext(path)); ext != "" && ext != base {
+		return ext
+	}
+	// Dotfiles and well-known files like Makefile or Dockerfile
+	if strings.HasPrefix(base, ".") || base[0] >= 'A' && base[0] <= 'Z' {
+		return base
+	}
+	return fileTypeNoExtension
+}
@@ -0,0 +1,56 @@
+package analyzer
+
+import (
+	"maps"
+	"testing"
+)
+
+func TestEditorFileType(t *testing.T) {
+	tests := map[string]string{
+		"main.go":          ".go",
+		"src/App.TSX":      ".tsx",
+		".":                fileTypeDirectory,
+		"project/":         fileTypeDirectory,
+		".zshrc":           ".zshrc",
+		"Makefile":         "Makefile",
+		"notes":            fileTypeNoExtension,
+		"~/.config/a.toml": ".toml",
+	}
+	for path, want := range tests {
+		if got := editorFileType(path); got != want {
+			t.Errorf("editorFileType(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestDetectEditorUse(t *testing.T) {
+	usage := ToolUsage{
+		Editors:         make(map[string]int),
+		EditorFileTypes: make(map[string]map[string]int),
+	}
+	for _, cmd := range []string{
+		"vim main.go",
+		"nvim +42 main.go util.go",
+		"vi -O README.md 'docs/guide.md'",
+		"/usr/bin/code .",
+		"ls main.go",
+		"",
+	} {
+		detectEditorUse(cmd, &usage)
+	}
+
+	wantEditors := map[string]int{"vim": 2, "neovim": 1, "vscode": 1}
+	if !maps.Equal(usage.Editors, wantEditors) {
+		t.Errorf("Editors = %v, want %v", usage.Editors, wantEditors)
+	}
+	wantTypes := map[string]map[string]int{
+		"vim":    {".go": 1, ".md": 2},
+		"neovim": {".go": 2},
+		"vscode": {fileTypeDirectory: 1},
+	}
+	for editor, want := range wantTypes {
+		if got := usage.EditorFileTypes[editor]; !maps.Equal(got, want) {
+			t.Errorf("EditorFileTypes[%s] = %v, want %v", editor, got, want)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package analyzer
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+)
+
+const (
+	// Bits per character above which a token looks random. Hex hashes top
+	// out at 4 bits, so they stay below the default.
+	defaultSecretEntropyThreshold = 4.5
+
+	// Shorter tokens don't have enough characters for a meaningful entropy
+	minSecretLength = 20
+
+	// Leaked commands kept as redacted examples
+	MaxSecretSamples = 5
+)
+
+var (
+	// Token-like strings: letters, digits and the base64/url-safe extras
+	secretTokenRegex = regexp.MustCompile(`^[A-Za-z0-9+/=_\-.]+$`)
+
+	// Clipboard contents assigned to a secret-looking variable
+	clipboardSecretRegex = regexp.MustCompile(`(?i)[A-Z_]*(SECRET|TOKEN|PASSWORD|PASSWD|API_?KEY|ACCESS_KEY|PRIVATE_KEY|CREDENTIAL)[A-Z_]*=["']?\$\(\s*(pbpaste|xclip|xsel|wl-paste|Get-Clipboard|powershell\.exe\s+Get-Clipboard)`)
+)
+
+// Shannon entropy of a string in bits per character
+func shannonEntropy(s string) float64 {
+	if s == "" {
+		return 0
+	}
+	counts := make(map[rune]int)
+	for _, r := range s {
+		counts[r]++
+	}
+	length := float64(len([]rune(s)))
+	var entropy float64
+	for _, count := range counts {
+		p := float64(count) / length
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
+
+// Find an argument that looks like a pasted secret, returning it or ""
+func findHighEntropyToken(cmd string, threshold float64) string {
+	fields := strings.Fields(cmd)
+	if len(fields) < 2 {
+		return ""
+	}
+	for _, field := range fields[1:] {
+		// Check the value of NAME=value and --flag=value
+		if i := strings.Index(field, "="); i >= 0 && i < len(field)-1 {
+			field = field[i+1:]
+		}
+		token := strings.Trim(field, `"'`)
+		if len(token) < minSecretLength || !secretTokenRegex.MatchString(token) {
+			continue
+		}
+		// Paths and domains are long but not secret
+		if strings.Count(token, "/") > 1 || strings.Count(token, ".") > 1 {
+			continue
+		}
+		if shannonEntropy(token) >= threshold {
+			return token
+		}
+	}
+	return ""
+}
+
+// Replace a secret in a command with a short, safe preview of it
+func redactSecret(cmd, secret string) string {
+	preview := fmt.Sprintf("%s…(%d chars)", secret[:4], len(secret))
+	return strings.ReplaceAll(cmd, secret, preview)
+}
@@ -0,0 +1,117 @@
+package analyzer
+
+import (
+	"regexp"
+	"strings"
+)
+
+// A valid shell variable name
+var variableNameRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Options of export that don't set variables: -f exports functions, -n
+// unexports and -p prints
+var exportNonAssignments = map[string]bool{"-f": true, "-n": true, "-p": true, "-fn": true, "-nf": true}
+
+// Options of fish's set that erase, query or show variables, or make
+// them local to a block
+var fishSetNonAssignments = map[string]bool{
+	"-e": true, "--erase": true, "-q": true, "--query": true, "-n": true, "--names": true,
+	"-S": true, "--show": true, "-l": true, "--local": true, "-f": true, "--function": true,
+}
+
+// Whether a config line may set variables in shell. Plain assignments
+// only count at the top level, since indented ones are mostly locals
+// inside functions.
+func isEnvironmentDefinition(shell, line string) bool {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return false
+	}
+	if shell == "fish" {
+		// fish also has export, for compatibility with other shells
+		return fields[0] == "set" || fields[0] == "export"
+	}
+	switch fields[0] {
+	case "export", "typeset", "declare":
+		return true
+	}
+	name, _, found := strings.Cut(fields[0], "=")
+	return found && variableNameRegex.MatchString(name) && !strings.HasPrefix(line, " ") && !strings.HasPrefix(line, "\t")
+}
+
+// The variables an environment statement sets, from its words:
+//   - bash and zsh: export NAME=value, typeset -x or declare -x NAME=value,
+//     and plain NAME=value with no command after it
+//   - fish: set [-gx] NAME value..., and export NAME=value
+func parseEnvironmentDefinition(shell string, words []string) []configAssignment {
+	if len(words) == 0 {
+		return nil
+	}
+	if shell == "fish" && words[0] == "set" {
+		return parseFishSet(words[1:])
+	}
+
+	var args []string
+	switch words[0] {
+	case "export":
+		args = words[1:]
+		for _, arg := range args {
+			if exportNonAssignments[arg] {
+				return nil
+			}
+		}
+	case "typeset", "declare":
+		// Only exported variables, like typeset -gx
+		exported := false
+		for _, arg := range words[1:] {
+			if strings.HasPrefix(arg, "-") && !strings.HasPrefix(arg, "--") {
+				exported = exported || strings.Contains(arg, "x")
+				if strings.Contains(arg, "f") {
+					return nil
+				}
+			}
+		}
+		if !exported {
+			return nil
+		}
+		args = words[1:]
+	default:
+		// NAME=value cmd sets NAME for that one command only
+		for _, word := range words {
+			if name, _, found := strings.Cut(word, "="); !found || !variableNameRegex.MatchString(name) {
+				return nil
+			}
+		}
+		args = words
+	}
+
+	var assignments []configAssignment
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "-") {
+			continue
+		}
+		if name, value, found := strings.Cut(arg, "="); found && variableNameRegex.MatchString(name) {
+			assignments = append(assignments, configAssignment{name, value})
+		}
+	}
+	return assignments
+}
+
+// The variable a fish set statement sets, from the words after set.
+// Values with several words are joined with spaces, like fish prints them.
+func parseFishSet(args []string) []configAssignment {
+	var positional []string
+	for _, arg := range args {
+		if len(positional) == 0 && strings.HasPrefix(arg, "-") {
+			if fishSetNonAssignments[arg] || (!strings.HasPrefix(arg, "--") && strings.ContainsAny(arg, "eqnSlf")) {
+				return nil
+			}
+			continue
+		}
+		positional = append(positional, arg)
+	}
+	if len(positional) == 0 || !variableNameRegex.MatchString(positional[0]) {
+		return nil
+	}
+	return []configAssignment{{positional[0], strings.Join(positional[1:], " ")}}
+}
@@ -0,0 +1,48 @@
+package analyzer
+
+import (
+	"maps"
+	"testing"
+)
+
+func TestParseShellConfigEnvironment(t *testing.T) {
+	tests := []struct {
+		shell   string
+		content string
+		want    map[string]string
+	}{
+		// bash and zsh
+		{"bash", `export EDITOR=vim`, map[string]string{"EDITOR": "vim"}},
+		{"bash", `export GREETING="hello world"`, map[string]string{"GREETING": "hello world"}},
+		{"bash", `export A=1 B='two words'`, map[string]string{"A": "1", "B": "two words"}},
+		{"bash", `PAGER=less`, map[string]string{"PAGER": "less"}},
+		{"bash", `declare -x LANG=en_US.UTF-8`, map[string]string{"LANG": "en_US.UTF-8"}},
+		{"zsh", `typeset -gx PATH_EXTRA="/opt/my tools/bin"`, map[string]string{"PATH_EXTRA": "/opt/my tools/bin"}},
+		{"zsh", `MSG='two words'`, map[string]string{"MSG": "two words"}},
+
+		// Statements that don't set environment variables
+		{"bash", `export -f my_function`, map[string]string{}},
+		{"bash", `export -n EDITOR`, map[string]string{}},
+		{"bash", `export -p`, map[string]string{}},
+		{"bash", `declare -fx my_function`, map[string]string{}},
+		{"zsh", `typeset -g LOCAL_ONLY=1`, map[string]string{}},
+		{"bash", `LANG=C sort file`, map[string]string{}},
+		{"bash", "f() {\n  local_var=1\n}", map[string]string{}},
+
+		// fish
+		{"fish", `set -x EDITOR vim`, map[string]string{"EDITOR": "vim"}},
+		{"fish", `set -gx GREETING hello world`, map[string]string{"GREETING": "hello world"}},
+		{"fish", `set --export MSG 'two words'`, map[string]string{"MSG": "two words"}},
+		{"fish", `export PAGER=less`, map[string]string{"PAGER": "less"}},
+		{"fish", `set -e EDITOR`, map[string]string{}},
+		{"fish", `set -q EDITOR`, map[string]string{}},
+		{"fish", `set -l local_var 1`, map[string]string{}},
+	}
+	for _, test := range tests {
+		config := newTestShellConfig()
+		parseShellConfig(test.shell, "~/.config", test.content, &config)
+		if !maps.Equal(config.Environment, test.want) {
+			t.Errorf("%s: %q set %q, want %q", test.shell, test.content, config.Environment, test.want)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package analyzer
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+)
+
+var (
+	ErrHistoryNotFound = errors.New("history not found")
+	ErrPermission      = errors.New("permission denied")
+	ErrParse           = errors.New("parse error")
+	ErrLineTooLong     = errors.New("lines too long")
+	ErrNoTimestamps    = errors.New("no timestamps")
+)
+
+// AnalysisError ties a failure to the shell and file it came from. Kind is
+// one of the sentinel errors above so callers can use errors.Is on it.
+type AnalysisError struct {
+	Shell string
+	Path  string
+	Kind  error
+	Cause error
+}
+
+func (e *AnalysisError) Error() string {
+	var source string
+	if e.Shell != "" {
+		source = e.Shell + ": "
+	}
+	if e.Cause != nil && e.Kind != ErrHistoryNotFound && e.Kind != ErrPermission {
+		return fmt.Sprintf("%s%v in %s: %v", source, e.Kind, e.Path, e.Cause)
+	}
+	return fmt.Sprintf("%s%v (%s)", source, e.Kind, e.Path)
+}
+
+func (e *AnalysisError) Unwrap() []error {
+	return []error{e.Kind, e.Cause}
+}
+
+// Classify a file access error into one of the sentinel kinds
+func FileError(path string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	kind := ErrParse
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		kind = ErrHistoryNotFound
+	case errors.Is(err, fs.ErrPermission):
+		kind = ErrPermission
+	}
+	return &AnalysisError{Path: path, Kind: kind, Cause: err}
+}
+
+func parseError(path string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &AnalysisError{Path: path, Kind: ErrParse, Cause: err}
+}
+
+func withShell(err error, shell string) error {
+	var analysisErr *AnalysisError
+	if errors.As(err, &analysisErr) {
+		analysisErr.Shell = shell
+	}
+	return err
+}
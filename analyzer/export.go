@@ -0,0 +1,113 @@
+package analyzer
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// Version of the export structure, written into every export format.
+// Bump SchemaVersion when a field is renamed, removed or changes meaning;
+// adding fields is not a breaking change. Raise minSchemaVersion when this
+// build can no longer read exports as old as it.
+//
+// Version 2 dropped the always-zero Count of history entries; version 1
+// exports read the same without it. Version 3 collapsed repeated commands
+// into one entry with a Count; older exports are collapsed on import.
+const (
+	SchemaVersion    = 3
+	minSchemaVersion = 1
+)
+
+var (
+	ErrUnsupportedVersion = errors.New("unsupported export version")
+	ErrUnknownFormat      = errors.New("unknown export format")
+	ErrUnknownSection     = errors.New("unknown section")
+)
+
+type jsonExport struct {
+	SchemaVersion int       `json:"schema_version"`
+	Generated     time.Time `json:"generated"`
+	Data          ShellData `json:"data"`
+}
+
+// A copy of data without the raw contents of config files, which can be
+// large. Config Health can't measure configs imported without them.
+func WithoutConfigContents(data ShellData) ShellData {
+	configs := make(map[string]ShellConfig, len(data.ShellConfigs))
+	for shell, config := range data.ShellConfigs {
+		files := make(map[string]ConfigInfo, len(config.ConfigFiles))
+		for name, file := range config.ConfigFiles {
+			file.Content = ""
+			files[name] = file
+		}
+		config.ConfigFiles = files
+		configs[shell] = config
+	}
+	data.ShellConfigs = configs
+	return data
+}
+
+// MarshalReport encodes the analysis as a versioned JSON export, like
+// -format json, without the raw contents of config files
+func (d ShellData) MarshalReport() ([]byte, error) {
+	var report bytes.Buffer
+	if err := ExportJSON(WithoutConfigContents(d), &report); err != nil {
+		return nil, err
+	}
+	return report.Bytes(), nil
+}
+
+// Write the full analysis as versioned JSON
+func ExportJSON(data ShellData, w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(jsonExport{
+		SchemaVersion: SchemaVersion,
+		Generated:     time.Now(),
+		Data:          data,
+	})
+}
+
+// Read a JSON export, rejecting versions this build doesn't understand
+func importJSON(r io.Reader) (ShellData, error) {
+	var export jsonExport
+	if err := json.NewDecoder(r).Decode(&export); err != nil {
+		return ShellData{}, err
+	}
+
+	switch {
+	case export.SchemaVersion == 0:
+		return ShellData{}, fmt.Errorf("%w: missing schema_version, not a shell-analyser export", ErrUnsupportedVersion)
+	case export.SchemaVersion > SchemaVersion:
+		return ShellData{}, fmt.Errorf("%w: version %d is newer than supported version %d, upgrade shell-analyser",
+			ErrUnsupportedVersion, export.SchemaVersion, SchemaVersion)
+	case export.SchemaVersion < minSchemaVersion:
+		return ShellData{}, fmt.Errorf("%w: version %d is older than the oldest supported version %d, re-export it with a newer shell-analyser",
+			ErrUnsupportedVersion, export.SchemaVersion, minSchemaVersion)
+	case export.SchemaVersion < 3:
+		for shell, history := range export.Data.Histories {
+			export.Data.Histories[shell] = collapseRepeats(history)
+		}
+	}
+
+	return export.Data, nil
+}
+
+func ImportFile(path string) (ShellData, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return ShellData{}, FileError(path, err)
+	}
+	defer file.Close()
+
+	data, err := importJSON(file)
+	if err != nil {
+		return ShellData{}, parseError(path, err)
+	}
+	return data, nil
+}
@@ -0,0 +1,171 @@
+package analyzer
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// A ShellData with something in each part that JSON exports carry
+func testShellData() ShellData {
+	when := time.Date(2024, 4, 12, 10, 0, 0, 0, time.UTC)
+	data := NewShellData()
+	data.Histories["zsh"] = []CommandEntry{
+		{Command: "git status", Timestamp: when, Duration: 2 * time.Second,
+			Categories: []CategoryMatch{{Name: "development", Weight: 1}}},
+		{Command: "make test", Timestamp: when.Add(time.Minute)},
+	}
+	data.CommonCmds = map[string]int{"git": 1, "make": 1}
+	data.TimePatterns = map[string]int{"10": 2}
+	data.PrimaryShell = "zsh"
+	data.Insights.TechnicalProfile.PrimaryRole = "Backend Developer"
+	data.Insights.TechnicalProfile.LanguageUsage = map[string]int{"go": 3}
+	data.Insights.WorkPatterns.PeakHours = []int{10, 14}
+	data.Insights.ToolUsage.BuildTools = map[string]int{"make": 1}
+	data.ShellConfigs["zsh"] = ShellConfig{
+		ConfigFiles: map[string]ConfigInfo{
+			".zshrc": {Path: "~/.zshrc", Modified: when, Content: "alias gs='git status'\n"},
+		},
+		Aliases:     map[string]string{"gs": "git status"},
+		Environment: map[string]string{"EDITOR": "vim"},
+	}
+	return data
+}
+
+func TestExportJSONRoundTrip(t *testing.T) {
+	data := testShellData()
+
+	var export bytes.Buffer
+	if err := ExportJSON(data, &export); err != nil {
+		t.Fatal(err)
+	}
+	got, err := importJSON(&export)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entries := got.Histories["zsh"]
+	if len(entries) != 2 {
+		t.Fatalf("Histories[zsh] has %d entries, want 2", len(entries))
+	}
+	want := data.Histories["zsh"][0]
+	if entries[0].Command != want.Command || !entries[0].Timestamp.Equal(want.Timestamp) ||
+		entries[0].Duration != want.Duration || !slices.Equal(entries[0].Categories, want.Categories) {
+		t.Errorf("first entry = %+v, want %+v", entries[0], want)
+	}
+	if !maps.Equal(got.CommonCmds, data.CommonCmds) {
+		t.Errorf("CommonCmds = %v, want %v", got.CommonCmds, data.CommonCmds)
+	}
+	if !maps.Equal(got.TimePatterns, data.TimePatterns) {
+		t.Errorf("TimePatterns = %v, want %v", got.TimePatterns, data.TimePatterns)
+	}
+	if got.PrimaryShell != "zsh" {
+		t.Errorf("PrimaryShell = %q, want zsh", got.PrimaryShell)
+	}
+	profile := got.Insights.TechnicalProfile
+	if profile.PrimaryRole != "Backend Developer" || profile.LanguageUsage["go"] != 3 {
+		t.Errorf("TechnicalProfile = %+v", profile)
+	}
+	if !slices.Equal(got.Insights.WorkPatterns.PeakHours, []int{10, 14}) {
+		t.Errorf("PeakHours = %v, want [10 14]", got.Insights.WorkPatterns.PeakHours)
+	}
+	config := got.ShellConfigs["zsh"]
+	if config.Aliases["gs"] != "git status" || config.Environment["EDITOR"] != "vim" {
+		t.Errorf("ShellConfigs[zsh] = %+v", config)
+	}
+	if file := config.ConfigFiles[".zshrc"]; file.Content == "" || !file.Modified.Equal(data.ShellConfigs["zsh"].ConfigFiles[".zshrc"].Modified) {
+		t.Errorf("ConfigFiles[.zshrc] = %+v, want its content and modification time", file)
+	}
+}
+
+func TestMarshalReport(t *testing.T) {
+	report, err := testShellData().MarshalReport()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var raw struct {
+		SchemaVersion int    `json:"schema_version"`
+		Generated     string `json:"generated"`
+		Data          struct {
+			Histories map[string][]struct{ Timestamp string }
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(report, &raw); err != nil {
+		t.Fatal(err)
+	}
+	if raw.SchemaVersion != SchemaVersion {
+		t.Errorf("schema_version = %d, want %d", raw.SchemaVersion, SchemaVersion)
+	}
+	for _, ts := range []string{raw.Generated, raw.Data.Histories["zsh"][0].Timestamp} {
+		if _, err := time.Parse(time.RFC3339, ts); err != nil {
+			t.Errorf("timestamp %q isn't RFC3339: %v", ts, err)
+		}
+	}
+	if raw.Data.Histories["zsh"][0].Timestamp != "2024-04-12T10:00:00Z" {
+		t.Errorf("entry timestamp = %q", raw.Data.Histories["zsh"][0].Timestamp)
+	}
+	if strings.Contains(string(report), "alias gs=") {
+		t.Error("MarshalReport kept the raw config file contents")
+	}
+
+	data, err := importJSON(bytes.NewReader(report))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data.ShellConfigs["zsh"].ConfigFiles[".zshrc"].Path != "~/.zshrc" {
+		t.Errorf("config file metadata lost: %+v", data.ShellConfigs["zsh"].ConfigFiles)
+	}
+}
+
+func TestImportJSONSchemaVersions(t *testing.T) {
+	// A version 1 export, whose history entries had an always-zero Count
+	// and kept every run
+	v1 := `{"schema_version": 1, "generated": "2024-04-12T10:00:00Z", "data": {"Histories": {"bash": [
+		{"Command": "git status", "Timestamp": "2024-04-12T10:00:00Z", "Duration": 0, "Count": 0},
+		{"Command": "git status", "Timestamp": "2024-04-12T11:00:00Z", "Duration": 0, "Count": 0}
+	]}}}`
+	data, err := importJSON(strings.NewReader(v1))
+	if err != nil {
+		t.Fatalf("version 1 export: %v", err)
+	}
+	if entries := data.Histories["bash"]; len(entries) != 1 || entries[0].Command != "git status" || entries[0].Count != 2 {
+		t.Errorf("version 1 Histories = %+v, want the runs merged", data.Histories)
+	}
+
+	for _, export := range []string{
+		`{"data": {}}`,
+		fmt.Sprintf(`{"schema_version": %d, "data": {}}`, SchemaVersion+1),
+	} {
+		if _, err := importJSON(strings.NewReader(export)); !errors.Is(err, ErrUnsupportedVersion) {
+			t.Errorf("importJSON(%s) error = %v, want ErrUnsupportedVersion", export, err)
+		}
+	}
+}
+
+func TestExportJSONEntryCounts(t *testing.T) {
+	data := testShellData()
+	data.Histories["zsh"] = collapseRepeats(append(data.Histories["zsh"], data.Histories["zsh"][0]))
+	var export bytes.Buffer
+	if err := ExportJSON(data, &export); err != nil {
+		t.Fatal(err)
+	}
+
+	imported, err := importJSON(&export)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var counts []int
+	for _, entry := range imported.Histories["zsh"] {
+		counts = append(counts, entry.Count)
+	}
+	if want := []int{2, 1}; !slices.Equal(counts, want) {
+		t.Errorf("imported counts = %v, want %v", counts, want)
+	}
+}
@@ -0,0 +1,109 @@
+package analyzer
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Undo fish's escaping of history commands, which keeps a multiline
+// command on one line by writing newlines as \n and backslashes as \\
+func unescapeFishCommand(cmd string) string {
+	if !strings.Contains(cmd, `\`) {
+		return cmd
+	}
+	var unescaped strings.Builder
+	for i := 0; i < len(cmd); i++ {
+		if cmd[i] == '\\' && i+1 < len(cmd) {
+			switch cmd[i+1] {
+			case 'n':
+				unescaped.WriteByte('\n')
+				i++
+				continue
+			case '\\':
+				unescaped.WriteByte('\\')
+				i++
+				continue
+			}
+		}
+		unescaped.WriteByte(cmd[i])
+	}
+	return unescaped.String()
+}
+
+// Read fish's history file, a list of "- cmd: ..." items each followed by
+// indented fields like "when: 1681305600". A file without any "- cmd:"
+// item, such as one copied from another shell, is read as plain lines.
+func readFishHistory(path string, cfg Config, totals *CommandTotals) ([]CommandEntry, historyReadStats, error) {
+	var stats historyReadStats
+	file, err := openHistory(path, cfg)
+	if err != nil {
+		return nil, stats, err
+	}
+	defer file.Close()
+
+	window := entryWindow{limit: cfg.MaxEntries, countOnly: cfg.countOnly}
+	skipped := 0
+	scanner := newLineScanner(file, cfg.MaxLineBytes, &skipped)
+
+	// The item being read, added once its fields are done
+	var pending *CommandEntry
+	found := false
+	flush := func() {
+		if pending == nil {
+			return
+		}
+		entry := *pending
+		pending = nil
+		if isPromptNoise(entry.Command, cfg.promptNoise) {
+			stats.PromptNoise++
+			return
+		}
+		entry.Categories = cfg.Categories.commandCategories(entry.Command)
+		if totals.Add(entry) {
+			window.add(entry)
+		}
+	}
+
+	lastSkipped := 0
+	for scanner.Scan() {
+		line := scanner.Text()
+		// A line skipped for its length was most likely the next item's
+		// "- cmd:", whose "when:" mustn't date the item before it
+		if skipped != lastSkipped {
+			flush()
+			lastSkipped = skipped
+		}
+		if cmd, ok := strings.CutPrefix(line, "- cmd: "); ok {
+			flush()
+			found = true
+			if cmd = strings.TrimSpace(unescapeFishCommand(cmd)); cmd != "" {
+				pending = &CommandEntry{Command: cmd}
+			}
+			continue
+		}
+		if when, ok := strings.CutPrefix(strings.TrimSpace(line), "when: "); ok && pending != nil {
+			if epoch, err := strconv.ParseInt(when, 10, 64); err == nil {
+				pending.Timestamp = time.Unix(epoch, 0)
+			}
+		}
+		// Other fields, like the paths a command used, aren't needed
+	}
+	flush()
+
+	if !found && scanner.Err() == nil {
+		return readHistory(path, cfg, totals)
+	}
+
+	entries := window.ordered()
+	stats.Dropped = window.dropped
+	if err := scanner.Err(); err != nil {
+		return entries, stats, parseError(path, err)
+	}
+	if skipped > 0 {
+		return entries, stats, &AnalysisError{Path: path, Kind: ErrLineTooLong,
+			Cause: fmt.Errorf("skipped %d line(s) of %d bytes or more, raise max_line_bytes to read them", skipped, cfg.MaxLineBytes)}
+	}
+	return entries, stats, nil
+}
@@ -0,0 +1,73 @@
+package analyzer
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestUnescapeFishCommand(t *testing.T) {
+	tests := map[string]string{
+		"git status":                 "git status",
+		`for f in *\n  echo $f\nend`: "for f in *\n  echo $f\nend",
+		`echo C:\\Users`:             `echo C:\Users`,
+		`echo \\n`:                   `echo \n`,
+		`printf "%s\t"`:              `printf "%s\t"`,
+		`trailing \`:                 `trailing \`,
+	}
+	for escaped, want := range tests {
+		if got := unescapeFishCommand(escaped); got != want {
+			t.Errorf("unescapeFishCommand(%q) = %q, want %q", escaped, got, want)
+		}
+	}
+}
+
+func TestReadFishHistory(t *testing.T) {
+	content := "- cmd: git status\n" +
+		"  when: 1681305600\n" +
+		"- cmd: for f in *.go\\n  gofmt -l $f\\nend\n" +
+		"  when: 1681305660\n" +
+		"  paths:\n" +
+		"    - *.go\n" +
+		"- cmd: make test\n" +
+		"- cmd:    \n" +
+		"  when: 1681305700\n"
+	path := writeTestHistory(t, "fish_history", content)
+
+	entries, _, err := readFishHistory(path, DefaultConfig(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []struct {
+		cmd string
+		ts  time.Time
+	}{
+		{"git status", time.Unix(1681305600, 0)},
+		{"for f in *.go\n  gofmt -l $f\nend", time.Unix(1681305660, 0)},
+		{"make test", time.Time{}},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("read %d entries, want %d: %+v", len(entries), len(want), entries)
+	}
+	for i, w := range want {
+		if entries[i].Command != w.cmd || !entries[i].Timestamp.Equal(w.ts) {
+			t.Errorf("entry %d = %q at %v, want %q at %v", i, entries[i].Command, entries[i].Timestamp, w.cmd, w.ts)
+		}
+	}
+}
+
+func TestReadFishHistoryPlainLines(t *testing.T) {
+	// A file without "- cmd:" items is read one command per line
+	path := writeTestHistory(t, "fish_history", "git status\nls -la\n")
+	entries, _, err := readFishHistory(path, DefaultConfig(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, entry := range entries {
+		got = append(got, entry.Command)
+	}
+	if want := []string{"git status", "ls -la"}; !slices.Equal(got, want) {
+		t.Errorf("commands = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,82 @@
+package analyzer
+
+import (
+	"sort"
+	"time"
+)
+
+const (
+	// Active days needed before a gap can stand out from the usual rhythm
+	minGapActiveDays = 14
+
+	// Gaps are only reported for shells used at least every few days, and
+	// when they're both this long and many times the usual spacing
+	maxUsualDayGap   = 3 * 24 * time.Hour
+	minHistoryGap    = 14 * 24 * time.Hour
+	historyGapFactor = 7
+
+	// Gaps shown per shell in the Overview
+	MaxHistoryGaps = 3
+)
+
+// HistoryGap is a stretch with no history in the middle of a shell's
+// otherwise regular use, often from `set +o history` or a cleared file
+type HistoryGap struct {
+	From time.Time
+	To   time.Time
+}
+
+func (g HistoryGap) Days() int {
+	return int(g.To.Sub(g.From).Hours() / 24)
+}
+
+// Stretches with no commands that are far longer than the shell's usual
+// spacing between active days, longest first
+func findHistoryGaps(entries []CommandEntry) []HistoryGap {
+	var times []time.Time
+	for _, entry := range entries {
+		if !entry.Timestamp.IsZero() {
+			times = append(times, entry.Timestamp)
+		}
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+
+	// The last command of each active day, so spacing is between days
+	// rather than between commands
+	var days []time.Time
+	for i, t := range times {
+		if i+1 == len(times) || times[i+1].Format(DayLayout) != t.Format(DayLayout) {
+			days = append(days, t)
+		}
+	}
+	if len(days) < minGapActiveDays {
+		return nil
+	}
+
+	spacing := make([]time.Duration, 0, len(days)-1)
+	for i := 1; i < len(days); i++ {
+		spacing = append(spacing, days[i].Sub(days[i-1]))
+	}
+	sorted := append([]time.Duration(nil), spacing...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	usual := sorted[len(sorted)/2]
+	if usual > maxUsualDayGap {
+		return nil
+	}
+
+	threshold := max(minHistoryGap, usual*historyGapFactor)
+	var gaps []HistoryGap
+	for i, gap := range spacing {
+		if gap < threshold {
+			continue
+		}
+		// From the last command before the gap to the first one after it
+		next := sort.Search(len(times), func(j int) bool { return times[j].After(days[i]) })
+		gaps = append(gaps, HistoryGap{From: days[i], To: times[next]})
+	}
+
+	sort.SliceStable(gaps, func(i, j int) bool {
+		return gaps[i].To.Sub(gaps[i].From) > gaps[j].To.Sub(gaps[j].From)
+	})
+	return gaps
+}
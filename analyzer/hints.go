@@ -0,0 +1,208 @@
+package analyzer
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	// Distinct commands checked, most frequent first
+	maxHintCommands = 200
+	maxHintExamples = 3
+)
+
+// HintRule is a shellcheck-inspired check on a single simple command.
+// Code is the matching shellcheck code, for looking up the details.
+type HintRule struct {
+	Code    string
+	Message string
+	Match   func(cmd string) bool
+}
+
+// Hint is a rule that matched some of the commands in the history
+type Hint struct {
+	Code     string
+	Message  string
+	Runs     int
+	Examples []string
+}
+
+var (
+	// "$name" or "${name}", but not special parameters like $? or $#
+	variableRegex = regexp.MustCompile(`^\$(\{[A-Za-z_]|[A-Za-z_])`)
+	recursiveFlag = regexp.MustCompile(`^-[a-zA-Z]*[rR]`)
+)
+
+var hintRules = []HintRule{
+	{"SC2086", "Double quote variables to prevent word splitting and globbing", hasUnquotedVariable},
+	{"SC2115", "Use \"${var:?}\" so a recursive rm never runs on / when the variable is empty", hasRecursiveRmOnVariable},
+	{"SC2035", "Use ./*glob* or -- *glob* so file names starting with - aren't taken as options", hasLeadingGlob},
+	{"SC2002", "Pass the file to the command instead of piping cat into it", hasUselessCat},
+	{"SC2006", "Use $(...) instead of legacy backticks", hasBackticks},
+}
+
+// Positions of $ that start a variable expansion outside any quotes
+func unquotedVariables(cmd string) []int {
+	var positions []int
+	inSingle, inDouble := false, false
+	for i := 0; i < len(cmd); i++ {
+		switch c := cmd[i]; {
+		case c == '\\' && !inSingle:
+			i++
+		case c == '\'' && !inDouble:
+			inSingle = !inSingle
+		case c == '"' && !inSingle:
+			inDouble = !inDouble
+		case c == '$' && !inSingle && !inDouble && variableRegex.MatchString(cmd[i:]):
+			positions = append(positions, i)
+		}
+	}
+	return positions
+}
+
+// Arguments of a command, skipping leading variable assignments
+func commandArgs(cmd string) (name string, args []string) {
+	fields := strings.Fields(cmd)
+	for i, field := range fields {
+		if !strings.Contains(field, "=") || strings.HasPrefix(field, "-") {
+			return field, fields[i+1:]
+		}
+	}
+	return "", nil
+}
+
+func hasUnquotedVariable(cmd string) bool {
+	// Assignments don't word split, so "FOO=$BAR" is fine on its own
+	for _, pos := range unquotedVariables(cmd) {
+		start := strings.LastIndexAny(cmd[:pos], " \t") + 1
+		if !strings.Contains(cmd[start:pos], "=") {
+			return true
+		}
+	}
+	return false
+}
+
+func hasRecursiveRmOnVariable(cmd string) bool {
+	name, args := commandArgs(cmd)
+	if name == "sudo" && len(args) > 0 {
+		name, args = args[0], args[1:]
+	}
+	if name != "rm" {
+		return false
+	}
+	recursive, variable := false, false
+	for _, arg := range args {
+		if recursiveFlag.MatchString(arg) || arg == "--recursive" {
+			recursive = true
+		}
+		if strings.Contains(arg, "$") && !strings.Contains(arg, ":?") {
+			variable = true
+		}
+	}
+	return recursive && variable
+}
+
+func hasLeadingGlob(cmd string) bool {
+	name, args := commandArgs(cmd)
+	switch name {
+	case "rm", "mv", "cp", "chmod", "chown":
+	default:
+		return false
+	}
+	for _, arg := range args {
+		if arg == "--" {
+			return false
+		}
+		if strings.HasPrefix(arg, "*") {
+			return true
+		}
+	}
+	return false
+}
+
+func hasUselessCat(cmd string) bool {
+	stages := splitPipeline(cmd)
+	if len(stages) < 2 {
+		return false
+	}
+	name, args := commandArgs(stages[0])
+	return name == "cat" && len(args) == 1 && !strings.HasPrefix(args[0], "-")
+}
+
+func hasBackticks(cmd string) bool {
+	inSingle := false
+	for i := 0; i < len(cmd); i++ {
+		switch cmd[i] {
+		case '\\':
+			i++
+		case '\'':
+			inSingle = !inSingle
+		case '`':
+			if !inSingle {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Run the hint rules over the most frequent commands. Examples have
+// probable secrets redacted since they're shown on screen and exported.
+func analyzeHints(entries []CommandEntry, entropyThreshold float64) []Hint {
+	counts := make(map[string]int)
+	for _, entry := range entries {
+		counts[entry.Command]++
+	}
+	var commands []string
+	for cmd := range counts {
+		commands = append(commands, cmd)
+	}
+	sort.Slice(commands, func(i, j int) bool {
+		if counts[commands[i]] != counts[commands[j]] {
+			return counts[commands[i]] > counts[commands[j]]
+		}
+		return commands[i] < commands[j]
+	})
+	if len(commands) > maxHintCommands {
+		commands = commands[:maxHintCommands]
+	}
+
+	hints := make([]Hint, len(hintRules))
+	for i, rule := range hintRules {
+		hints[i] = Hint{Code: rule.Code, Message: rule.Message}
+	}
+	for _, cmd := range commands {
+		for i, rule := range hintRules {
+			matched := false
+			for _, sub := range SplitCommand(cmd) {
+				if rule.Match(sub) {
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				continue
+			}
+			hints[i].Runs += counts[cmd]
+			if len(hints[i].Examples) < maxHintExamples {
+				example := cmd
+				if token := findHighEntropyToken(cmd, entropyThreshold); token != "" {
+					example = redactSecret(cmd, token)
+				}
+				hints[i].Examples = append(hints[i].Examples, example)
+			}
+		}
+	}
+
+	var found []Hint
+	for _, hint := range hints {
+		if hint.Runs > 0 {
+			found = append(found, hint)
+		}
+	}
+	sort.SliceStable(found, func(i, j int) bool {
+		return found[i].Runs > found[j].Runs
+	})
+	return found
+}
@@ -0,0 +1,101 @@
+package analyzer
+
+import (
+	"regexp"
+	"strings"
+)
+
+// History-related options, keyed by the name shown to the user
+var zshHistoryOptions = []string{
+	"APPEND_HISTORY",
+	"EXTENDED_HISTORY",
+	"HIST_EXPIRE_DUPS_FIRST",
+	"HIST_FIND_NO_DUPS",
+	"HIST_IGNORE_ALL_DUPS",
+	"HIST_IGNORE_DUPS",
+	"HIST_IGNORE_SPACE",
+	"HIST_REDUCE_BLANKS",
+	"HIST_SAVE_NO_DUPS",
+	"INC_APPEND_HISTORY",
+	"INC_APPEND_HISTORY_TIME",
+	"SHARE_HISTORY",
+}
+
+var bashHistoryOptions = []string{
+	"cmdhist",
+	"histappend",
+	"lithist",
+}
+
+var (
+	setoptRegex      = regexp.MustCompile(`^\s*(setopt|unsetopt)\s+(.+)$`)
+	shoptRegex       = regexp.MustCompile(`^\s*shopt\s+(-[su])\s+(.+)$`)
+	histControlRegex = regexp.MustCompile(`^\s*(?:export\s+)?HISTCONTROL=["']?([^"'\s]*)`)
+	histTimeRegex    = regexp.MustCompile(`^\s*(?:export\s+)?HISTTIMEFORMAT=`)
+)
+
+// zsh ignores case and underscores in option names
+func normalizeZshOption(name string) string {
+	return strings.ToUpper(strings.ReplaceAll(name, "_", ""))
+}
+
+// Record history options set by a config line. Options explicitly turned
+// off are kept as false so they can be told apart from unset ones.
+func detectHistoryOptions(line string, options map[string]bool) {
+	if match := setoptRegex.FindStringSubmatch(line); match != nil {
+		for _, name := range strings.Fields(match[2]) {
+			if strings.HasPrefix(name, "#") {
+				break
+			}
+			enabled := match[1] == "setopt"
+			normalized := normalizeZshOption(name)
+			// "setopt no_share_history" turns the option off
+			if strings.HasPrefix(normalized, "NO") {
+				for _, option := range zshHistoryOptions {
+					if normalizeZshOption(option) == normalized[2:] {
+						enabled = !enabled
+						normalized = normalized[2:]
+						break
+					}
+				}
+			}
+			for _, option := range zshHistoryOptions {
+				if normalizeZshOption(option) == normalized {
+					options[option] = enabled
+				}
+			}
+		}
+		return
+	}
+
+	if match := shoptRegex.FindStringSubmatch(line); match != nil {
+		for _, name := range strings.Fields(match[2]) {
+			if strings.HasPrefix(name, "#") {
+				break
+			}
+			for _, option := range bashHistoryOptions {
+				if option == name {
+					options[option] = match[1] == "-s"
+				}
+			}
+		}
+		return
+	}
+
+	if match := histControlRegex.FindStringSubmatch(line); match != nil {
+		for _, value := range strings.Split(match[1], ":") {
+			switch value {
+			case "ignoreboth":
+				options["HISTCONTROL=ignoredups"] = true
+				options["HISTCONTROL=ignorespace"] = true
+			case "ignoredups", "ignorespace", "erasedups":
+				options["HISTCONTROL="+value] = true
+			}
+		}
+		return
+	}
+
+	if histTimeRegex.MatchString(line) {
+		options["HISTTIMEFORMAT"] = true
+	}
+}
@@ -0,0 +1,26 @@
+package analyzer
+
+import (
+	"maps"
+	"testing"
+	"time"
+)
+
+func TestTimePatternsBuckets(t *testing.T) {
+	day := time.Date(2024, 4, 12, 0, 0, 0, 0, time.Local)
+	totals := newCommandTotals()
+	for _, entry := range []CommandEntry{
+		{Command: "git status", Timestamp: day.Add(9*time.Hour + 5*time.Minute)},
+		{Command: "git push", Timestamp: day.Add(9*time.Hour + 59*time.Minute)},
+		{Command: "make", Timestamp: day.Add(14 * time.Hour)},
+		{Command: "ls", Timestamp: day.Add(23*time.Hour + 30*time.Minute)},
+		{Command: "vim", Timestamp: day.Add(24 * time.Hour)},
+		{Command: "untimed"},
+	} {
+		totals.Add(entry)
+	}
+	want := map[string]int{"00": 1, "09": 2, "14": 1, "23": 1}
+	if got := totals.timePatterns(); !maps.Equal(got, want) {
+		t.Errorf("timePatterns = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,124 @@
+package analyzer
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// How many levels of source directives are followed from a shell's own
+// config files
+const maxSourceDepth = 5
+
+// Words that may come before a command in a compound statement
+var sourceKeywords = map[string]bool{"then": true, "else": true, "do": true, "{": true, "and": true, "or": true}
+
+// Files a config sources with "source FILE" or ". FILE", including
+// guarded ones like "[ -f ~/.aliases ] && source ~/.aliases". $HOME and
+// variables the config sets are expanded; paths using anything else,
+// like process substitutions, are left out. Relative paths are taken
+// from the home directory, where login shells start.
+func sourcedFiles(content string, environment map[string]string) []string {
+	var files []string
+	for _, line := range strings.Split(content, "\n") {
+		// CRLF files leave a \r on every line
+		line = strings.TrimSuffix(line, "\r")
+		for _, cmd := range SplitCommand(strings.TrimSpace(line)) {
+			words, _ := ShellWords(cmd)
+			// Like "if [ -f ~/.aliases ]; then source ~/.aliases; fi"
+			for len(words) > 0 && sourceKeywords[words[0]] {
+				words = words[1:]
+			}
+			if len(words) < 2 || (words[0] != "source" && words[0] != ".") {
+				continue
+			}
+			if path, ok := resolveSourcePath(words[1], environment); ok {
+				files = append(files, path)
+			}
+		}
+	}
+	return files
+}
+
+func resolveSourcePath(path string, environment map[string]string) (string, bool) {
+	if strings.ContainsAny(path, "()<>*?") {
+		return "", false
+	}
+
+	resolved := true
+	lookup := func(name string) string {
+		if name == "HOME" {
+			return "~"
+		}
+		if value, ok := environment[name]; ok {
+			return value
+		}
+		resolved = false
+		return ""
+	}
+	// Variables set in the config may themselves use $HOME
+	path = os.Expand(os.Expand(path, lookup), lookup)
+	if !resolved || path == "" {
+		return "", false
+	}
+
+	if !filepath.IsAbs(path) && path != "~" && !strings.HasPrefix(path, "~/") {
+		path = "~/" + path
+	}
+	return path, true
+}
+
+// Read a config file into config, then the files it sources up to
+// maxSourceDepth levels deep. Files already read are skipped, which also
+// stops sourcing cycles. Missing files are expected; files that exist but
+// can't be read are recorded in config.Unreadable and returned as errors.
+func readShellConfig(shell, path string, depth int, config *ShellConfig) []error {
+	expandedPath := config.ExpandPath(path)
+	for _, file := range config.ConfigFiles {
+		if file.Path == expandedPath {
+			return nil
+		}
+	}
+
+	info, err := os.Stat(expandedPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		config.Unreadable = append(config.Unreadable, path)
+		return []error{FileError(expandedPath, err)}
+	}
+	if info.IsDir() {
+		// Sourced paths must be files; a shell's own config list also has
+		// directories like fish's conf.d, noted without content
+		if depth == 0 {
+			config.ConfigFiles[path] = ConfigInfo{Path: expandedPath, Modified: info.ModTime()}
+		}
+		return nil
+	}
+
+	content, err := os.ReadFile(expandedPath)
+	if err != nil {
+		config.Unreadable = append(config.Unreadable, path)
+		return []error{FileError(expandedPath, err)}
+	}
+	config.ConfigFiles[path] = ConfigInfo{
+		Path:     expandedPath,
+		Modified: info.ModTime(),
+		Content:  string(content),
+	}
+
+	// Parse the config file
+	parseShellConfig(shell, expandedPath, string(content), config)
+
+	if depth == maxSourceDepth {
+		return nil
+	}
+	var errs []error
+	for _, sourced := range sourcedFiles(string(content), config.Environment) {
+		errs = append(errs, readShellConfig(shell, sourced, depth+1, config)...)
+	}
+	return errs
+}
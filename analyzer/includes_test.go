@@ -0,0 +1,142 @@
+package analyzer
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestSourcedFiles(t *testing.T) {
+	content := "source ~/.zsh_aliases\r\n" +
+		". $HOME/.config/aliases\r\n" +
+		"[ -f ~/.fzf.zsh ] && source ~/.fzf.zsh\n" +
+		"if [ -f ~/.local.sh ]; then . ~/.local.sh; fi\n" +
+		"export DOTFILES=$HOME/dotfiles\n" +
+		"source $DOTFILES/functions.sh\n" +
+		"source .profile_extra\n" +
+		"source /etc/profile.d/custom.sh\n" +
+		"source <(kubectl completion zsh)\n" +
+		"source $UNSET_VAR/file\n" +
+		"source ~/plugins/*.zsh\n" +
+		"echo source ~/not-a-source\n"
+	environment := map[string]string{"DOTFILES": "$HOME/dotfiles"}
+
+	want := []string{
+		"~/.zsh_aliases",
+		"~/.config/aliases",
+		"~/.fzf.zsh",
+		"~/.local.sh",
+		"~/dotfiles/functions.sh",
+		"~/.profile_extra",
+		"/etc/profile.d/custom.sh",
+	}
+	if got := sourcedFiles(content, environment); !slices.Equal(got, want) {
+		t.Errorf("sourcedFiles = %q, want %q", got, want)
+	}
+}
+
+func TestAnalyzeShellConfigsFollowsSources(t *testing.T) {
+	home := writeTestHome(t, map[string]string{
+		// The root config sources two others, one of them with CRLF endings
+		".zshrc":          "alias gs='git status'\nsource ~/.zsh_aliases\n. $HOME/.config/aliases\n",
+		".zsh_aliases":    "alias k=kubectl\r\nsource ~/.zshrc\r\n",
+		".config/aliases": "alias ll='ls -la'\nsource ~/.missing\n",
+	})
+
+	config, errs := analyzeShellConfigs("zsh", home)
+	if len(errs) > 0 {
+		t.Fatalf("errors: %v", errs)
+	}
+	want := map[string]string{"gs": "git status", "k": "kubectl", "ll": "ls -la"}
+	if !maps.Equal(config.Aliases, want) {
+		t.Errorf("Aliases = %q, want %q", config.Aliases, want)
+	}
+	for _, path := range []string{"~/.zshrc", "~/.zsh_aliases", "~/.config/aliases"} {
+		if _, ok := config.ConfigFiles[path]; !ok {
+			t.Errorf("%s wasn't read; read %v", path, slices.Sorted(maps.Keys(config.ConfigFiles)))
+		}
+	}
+	if len(config.ConfigFiles) != 3 {
+		t.Errorf("read %d files, want each once", len(config.ConfigFiles))
+	}
+}
+
+func TestAnalyzeShellConfigsSourceDepth(t *testing.T) {
+	// .zshrc sources a chain of files one level deeper each
+	files := map[string]string{".zshrc": "source ~/.level1\n"}
+	for level := 1; level <= maxSourceDepth+2; level++ {
+		files[fmt.Sprintf(".level%d", level)] = fmt.Sprintf("alias l%d=true\nsource ~/.level%d\n", level, level+1)
+	}
+	config, _ := analyzeShellConfigs("zsh", writeTestHome(t, files))
+
+	for level := 1; level <= maxSourceDepth+2; level++ {
+		_, ok := config.Aliases[fmt.Sprintf("l%d", level)]
+		if want := level <= maxSourceDepth; ok != want {
+			t.Errorf("alias from level %d read = %v, want %v", level, ok, want)
+		}
+	}
+}
+
+// Make a file unreadable. Root reads files whatever their mode, so there
+// it's replaced with a symlink to itself, which nobody can open.
+func makeUnreadable(t *testing.T, path string) {
+	t.Helper()
+	if err := os.Chmod(path, 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.ReadFile(path); err == nil {
+		if err := os.Remove(path); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Symlink(filepath.Base(path), path); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestAnalyzeShellConfigsUnreadable(t *testing.T) {
+	home := writeTestHome(t, map[string]string{
+		".zshrc":       "alias gs='git status'\nsource ~/.zsh_aliases\nsource ~/.zsh_env\n",
+		".zsh_aliases": "alias k=kubectl\n",
+		".zsh_env":     "export EDITOR=vim\n",
+	})
+	makeUnreadable(t, filepath.Join(home, ".zsh_aliases"))
+
+	config, errs := analyzeShellConfigs("zsh", home)
+
+	// The unreadable file is reported and the others still read
+	if len(errs) != 1 {
+		t.Fatalf("errors = %v, want one for .zsh_aliases", errs)
+	}
+	var analysisErr *AnalysisError
+	if !errors.As(errs[0], &analysisErr) || analysisErr.Path != filepath.Join(home, ".zsh_aliases") {
+		t.Errorf("error = %v, want an AnalysisError for .zsh_aliases", errs[0])
+	}
+	if !slices.Equal(config.Unreadable, []string{"~/.zsh_aliases"}) {
+		t.Errorf("Unreadable = %q, want ~/.zsh_aliases", config.Unreadable)
+	}
+	if config.Aliases["gs"] != "git status" || config.Environment["EDITOR"] != "vim" {
+		t.Errorf("Aliases = %q, Environment = %q; want the readable files parsed", config.Aliases, config.Environment)
+	}
+}
+
+func TestDetectFishPluginsUnreadable(t *testing.T) {
+	home := writeTestHome(t, map[string]string{".config/fish/config.fish": ""})
+	confd := filepath.Join(home, ".config/fish/conf.d")
+	if err := os.Symlink("conf.d", confd); err != nil {
+		t.Fatal(err)
+	}
+
+	config := newTestShellConfig()
+	config.home = home
+	if errs := detectFishPlugins(&config); len(errs) != 1 {
+		t.Errorf("errors = %v, want one for conf.d", errs)
+	}
+	if !slices.Equal(config.Unreadable, []string{confd}) {
+		t.Errorf("Unreadable = %q, want %s", config.Unreadable, confd)
+	}
+}
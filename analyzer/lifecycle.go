@@ -0,0 +1,127 @@
+package analyzer
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// Recency thresholds for tool lifecycle classification, measured back from
+// the newest timestamp in the history rather than today, so an old history
+// file isn't reported as entirely abandoned
+const (
+	newToolWindow       = 30 * 24 * time.Hour
+	abandonedToolWindow = 90 * 24 * time.Hour
+	minLifecycleUses    = 3
+
+	// New tools used at least this often are being learned rather than
+	// just tried
+	MinLearningUses = 10
+)
+
+const (
+	StatusNew       = "new"
+	StatusActive    = "active"
+	StatusDeclining = "declining"
+	StatusAbandoned = "abandoned"
+)
+
+type ToolLifecycle struct {
+	Name   string
+	First  time.Time
+	Last   time.Time
+	Count  int
+	Recent int
+	Status string
+
+	// Uses per week since first use, counting at least a week so a tool
+	// first run yesterday isn't inflated
+	PerWeek float64
+}
+
+func analyzeToolLifecycle(entries []CommandEntry) []ToolLifecycle {
+	tools := make(map[string]*ToolLifecycle)
+	var newest time.Time
+
+	for _, entry := range entries {
+		if entry.Timestamp.IsZero() {
+			continue
+		}
+		if entry.Timestamp.After(newest) {
+			newest = entry.Timestamp
+		}
+
+		for _, cmd := range SplitCommand(entry.Command) {
+			name := BaseCommand(cmd)
+			if name == "" || ShellBuiltins[name] || strings.ContainsAny(name, "/=$") {
+				continue
+			}
+
+			tool, ok := tools[name]
+			if !ok {
+				tool = &ToolLifecycle{Name: name, First: entry.Timestamp, Last: entry.Timestamp}
+				tools[name] = tool
+			}
+			if entry.Timestamp.Before(tool.First) {
+				tool.First = entry.Timestamp
+			}
+			if entry.Timestamp.After(tool.Last) {
+				tool.Last = entry.Timestamp
+			}
+			tool.Count++
+		}
+	}
+
+	// Second pass for usage inside the recent window, now that newest is known
+	recentStart := newest.Add(-newToolWindow)
+	for _, entry := range entries {
+		if entry.Timestamp.IsZero() || entry.Timestamp.Before(recentStart) {
+			continue
+		}
+		for _, cmd := range SplitCommand(entry.Command) {
+			if tool, ok := tools[BaseCommand(cmd)]; ok {
+				tool.Recent++
+			}
+		}
+	}
+
+	var lifecycle []ToolLifecycle
+	for _, tool := range tools {
+		if tool.Count < minLifecycleUses {
+			continue
+		}
+		tool.Status = classifyTool(*tool, newest)
+		weeks := max(newest.Sub(tool.First).Hours()/(24*7), 1)
+		tool.PerWeek = float64(tool.Count) / weeks
+		lifecycle = append(lifecycle, *tool)
+	}
+
+	sort.Slice(lifecycle, func(i, j int) bool {
+		if lifecycle[i].Count != lifecycle[j].Count {
+			return lifecycle[i].Count > lifecycle[j].Count
+		}
+		return lifecycle[i].Name < lifecycle[j].Name
+	})
+	return lifecycle
+}
+
+func classifyTool(tool ToolLifecycle, newest time.Time) string {
+	switch {
+	case newest.Sub(tool.First) <= newToolWindow:
+		return StatusNew
+	case newest.Sub(tool.Last) > abandonedToolWindow:
+		return StatusAbandoned
+	}
+
+	// Declining when the recent daily rate is under half the earlier rate
+	earlierDays := newest.Add(-newToolWindow).Sub(tool.First).Hours() / 24
+	if earlierDays <= 0 {
+		return StatusActive
+	}
+	earlierRate := float64(tool.Count-tool.Recent) / earlierDays
+	recentRate := float64(tool.Recent) / (newToolWindow.Hours() / 24)
+	if recentRate < earlierRate/2 {
+		return StatusDeclining
+	}
+	return StatusActive
+}
@@ -0,0 +1,29 @@
+package analyzer
+
+import "sync/atomic"
+
+// AnalysisProgress counts the shells an analysis has finished. It's
+// updated by the analysis while the TUI reads it, so the fields are atomic.
+type AnalysisProgress struct {
+	done  atomic.Int64
+	total atomic.Int64
+}
+
+// Record that done of total shells are finished. A nil progress records
+// nothing, for analyses nobody is watching.
+func (p *AnalysisProgress) set(done, total int) {
+	if p == nil {
+		return
+	}
+	p.total.Store(int64(total))
+	p.done.Store(int64(done))
+}
+
+// Share of the shells finished, from 0 to 1
+func (p *AnalysisProgress) Percent() float64 {
+	total := p.total.Load()
+	if total == 0 {
+		return 0
+	}
+	return float64(p.done.Load()) / float64(total)
+}
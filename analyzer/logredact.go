@@ -0,0 +1,55 @@
+package analyzer
+
+import (
+	"io"
+	"regexp"
+	"strings"
+)
+
+const redactedText = "[redacted]"
+
+// Most secrets redacted from one log line, so a line of random-looking
+// data can't keep the redaction loop going
+const maxLogRedactions = 10
+
+// redactingWriter scrubs log output before it reaches the log file, so
+// the log never keeps a secret that was part of a command or an error
+type redactingWriter struct {
+	w         io.Writer
+	denylist  []*regexp.Regexp
+	threshold float64
+}
+
+func NewRedactingWriter(w io.Writer, cfg Config) io.Writer {
+	return redactingWriter{w: w, denylist: cfg.logDenylist, threshold: cfg.SecretEntropyThreshold}
+}
+
+func (r redactingWriter) Write(p []byte) (int, error) {
+	if _, err := io.WriteString(r.w, redactLogText(string(p), r.denylist, r.threshold)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Remove anything matching the denylist, then anything that looks like a
+// pasted secret
+func redactLogText(text string, denylist []*regexp.Regexp, threshold float64) string {
+	for _, pattern := range denylist {
+		text = pattern.ReplaceAllString(text, redactedText)
+	}
+
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		for range maxLogRedactions {
+			// Log lines start with a prefix like "INFO:", which the token
+			// search skips the same way it skips a command's name
+			token := findHighEntropyToken(line, threshold)
+			if token == "" {
+				break
+			}
+			line = strings.ReplaceAll(line, token, redactedText)
+		}
+		lines[i] = line
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,90 @@
+package analyzer
+
+import "strings"
+
+// NavigationStats describes how cd is used: where it goes and how the
+// targets are written
+type NavigationStats struct {
+	Total int
+
+	// Targets that aren't paths: bare "cd" or "cd ~", and "cd -"
+	Home int
+	Back int
+
+	// Targets by how they're written; ".." and "../.." count as Up
+	Absolute     int
+	HomeRelative int
+	Relative     int
+	Up           int
+
+	// Path components across all path targets, for the average depth
+	Depth int
+
+	// Targets ending in "/", which shell completion adds to directories
+	TrailingSlash int
+}
+
+// Targets with a path, which depth and style are measured over
+func (s NavigationStats) Paths() int {
+	return s.Absolute + s.HomeRelative + s.Relative + s.Up
+}
+
+func (s NavigationStats) AverageDepth() float64 {
+	if s.Paths() == 0 {
+		return 0
+	}
+	return float64(s.Depth) / float64(s.Paths())
+}
+
+// Record a cd command's target
+func detectNavigation(cmd string, stats *NavigationStats) {
+	fields := strings.Fields(cmd)
+	if len(fields) == 0 || fields[0] != "cd" {
+		return
+	}
+	stats.Total++
+
+	// Skip options like -P, and "--" which ends them
+	target := ""
+	for _, arg := range fields[1:] {
+		if arg == "-" || !strings.HasPrefix(arg, "-") {
+			target = strings.Trim(arg, `"'`)
+			break
+		}
+	}
+
+	switch {
+	case target == "" || target == "~" || target == "$HOME":
+		stats.Home++
+		return
+	case target == "-":
+		stats.Back++
+		return
+	}
+
+	if strings.HasSuffix(target, "/") && target != "/" {
+		stats.TrailingSlash++
+	}
+
+	var path string
+	switch {
+	case strings.HasPrefix(target, "/"):
+		stats.Absolute++
+		path = target
+	case strings.HasPrefix(target, "~/"), strings.HasPrefix(target, "$HOME/"):
+		stats.HomeRelative++
+		_, path, _ = strings.Cut(target, "/")
+	case target == ".." || strings.HasPrefix(target, "../") && strings.Trim(target, "./") == "":
+		stats.Up++
+		path = target
+	default:
+		stats.Relative++
+		path = target
+	}
+
+	for _, part := range strings.Split(path, "/") {
+		if part != "" && part != "." {
+			stats.Depth++
+		}
+	}
+}
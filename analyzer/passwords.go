@@ -0,0 +1,146 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Commands that prompt for a password. When sudo's prompt times out, or a
+// login fails, the next thing typed is run as a command and lands in the
+// history.
+var passwordPromptCommands = map[string]bool{
+	"sudo": true, "su": true, "doas": true, "passwd": true, "ssh": true,
+	"scp": true, "sftp": true, "ssh-add": true, "mysql": true, "psql": true,
+	"gpg": true, "kinit": true,
+}
+
+// Orphan passwords need fewer signals right after a password prompt
+const (
+	minPromptedPasswordLength = 6
+	minOrphanPasswordLength   = 10
+	maxOrphanPasswordLength   = 64
+
+	// Bits per character, so most characters differ
+	minOrphanPasswordEntropy = 3.0
+)
+
+// OrphanPassword is a history entry that looks like a password typed at
+// the prompt. The password itself is never kept, only its length.
+type OrphanPassword struct {
+	Shell     string
+	Length    int
+	After     string
+	Timestamp time.Time
+}
+
+// How many of lowercase, uppercase, digits and symbols a string mixes.
+// The separators in names like release-2024_v2.1 aren't symbols.
+func characterClasses(s string) (classes int, hasDigitOrSymbol bool) {
+	var lower, upper, digit, symbol bool
+	for _, r := range s {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		case r == '-' || r == '_' || r == '.':
+		default:
+			symbol = true
+		}
+	}
+	for _, has := range []bool{lower, upper, digit, symbol} {
+		if has {
+			classes++
+		}
+	}
+	return classes, digit || symbol
+}
+
+func promptsForPassword(cmd string) bool {
+	for _, part := range SplitCommand(cmd) {
+		if fields := strings.Fields(part); len(fields) > 0 && passwordPromptCommands[filepath.Base(fields[0])] {
+			return true
+		}
+	}
+	return false
+}
+
+// Whether a command is a lone token that's more likely a password than a
+// command. It errs towards keeping commands: anything that looks like a
+// path, flag, assignment or known command never matches.
+func looksLikeOrphanPassword(cmd, previous string, known func(string) bool) bool {
+	token := strings.TrimSpace(cmd)
+	if len(token) < minPromptedPasswordLength || len(token) > maxOrphanPasswordLength ||
+		strings.ContainsFunc(token, unicode.IsSpace) {
+		return false
+	}
+	if strings.ContainsAny(token, "/=") || strings.ContainsAny(token[:1], ".-$~#!") {
+		return false
+	}
+	if ShellBuiltins[token] || known(token) {
+		return false
+	}
+
+	classes, hasDigitOrSymbol := characterClasses(token)
+	if promptsForPassword(previous) {
+		return classes >= 2 && hasDigitOrSymbol
+	}
+	return len(token) >= minOrphanPasswordLength && classes == 4 &&
+		shannonEntropy(token) >= minOrphanPasswordEntropy
+}
+
+// passwordScreen picks out the commands of one shell's history that look
+// like passwords, one entry at a time as the history is read. Aliases and
+// commands on PATH are never treated as passwords.
+type passwordScreen struct {
+	shell    string
+	aliases  map[string]string
+	previous string
+	orphans  []OrphanPassword
+}
+
+func newPasswordScreen(shell string, aliases map[string]string) *passwordScreen {
+	return &passwordScreen{shell: shell, aliases: aliases}
+}
+
+func (s *passwordScreen) known(name string) bool {
+	_, isAlias := s.aliases[name]
+	return isAlias || CheckToolInstalled(name)
+}
+
+// Whether to keep an entry, recording it as an orphan password if not
+func (s *passwordScreen) keep(entry CommandEntry) bool {
+	if looksLikeOrphanPassword(entry.Command, s.previous, s.known) {
+		s.orphans = append(s.orphans, OrphanPassword{
+			Shell:     s.shell,
+			Length:    len(strings.TrimSpace(entry.Command)),
+			After:     s.previous,
+			Timestamp: entry.Timestamp,
+		})
+		return false
+	}
+	s.previous = entry.Command
+	return true
+}
+
+// Remove the entries of one shell's history that look like passwords,
+// and uncount them from totals, which may be nil
+func redactOrphanPasswords(shell string, history []CommandEntry, aliases map[string]string, totals *CommandTotals) ([]CommandEntry, []OrphanPassword) {
+	screen := newPasswordScreen(shell, aliases)
+	var kept []CommandEntry
+	for _, entry := range history {
+		if !screen.keep(entry) {
+			totals.remove(entry)
+			continue
+		}
+		kept = append(kept, entry)
+	}
+	if len(screen.orphans) == 0 {
+		return history, nil
+	}
+	return kept, screen.orphans
+}
@@ -0,0 +1,148 @@
+package analyzer
+
+import (
+	"context"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+)
+
+var windowsEnvRegex = regexp.MustCompile(`%([A-Za-z_][A-Za-z0-9_()]*)%`)
+
+// History file locations for each shell on the current OS
+func defaultHistoryPaths() map[string]string {
+	if runtime.GOOS == "windows" {
+		return map[string]string{
+			"powershell": `%APPDATA%\Microsoft\Windows\PowerShell\PSReadLine\ConsoleHost_history.txt`,
+			"cmd":        `%LOCALAPPDATA%\clink\clink_history`,
+			// Git Bash and MSYS2 keep Unix-style history in the user profile
+			"bash": "~/.bash_history",
+			"zsh":  "~/.zsh_history",
+		}
+	}
+
+	return map[string]string{
+		"bash": "~/.bash_history",
+		"zsh":  "~/.zsh_history",
+		"fish": "~/.local/share/fish/fish_history",
+	}
+}
+
+// History file locations after the environment and -history overrides.
+// $HISTFILE, when exported, is the login shell's history; fish keeps its
+// history under $XDG_DATA_HOME when that's set. overrides win over both.
+// The environment is ignored when analyzing another home directory than
+// the user's, since it describes the user's.
+func resolveHistoryPaths(overrides map[string]string, home string) map[string]string {
+	paths := defaultHistoryPaths()
+	if home != "" {
+		maps.Copy(paths, overrides)
+		return paths
+	}
+	if _, ok := paths["fish"]; ok {
+		if dataHome := os.Getenv("XDG_DATA_HOME"); dataHome != "" {
+			paths["fish"] = filepath.Join(dataHome, "fish", "fish_history")
+		}
+	}
+	if histFile := os.Getenv("HISTFILE"); histFile != "" {
+		if shell := loginShell(); shell != "" {
+			paths[shell] = histFile
+		}
+	}
+	for shell, path := range overrides {
+		paths[shell] = path
+	}
+	return paths
+}
+
+// Config file locations for each shell on the current OS
+func DefaultConfigPaths() map[string][]string {
+	paths := map[string][]string{
+		"bash": {
+			"~/.bashrc",
+			"~/.bash_profile",
+			"~/.bash_aliases",
+		},
+		"zsh": {
+			"~/.zshrc",
+			"~/.zsh_plugins",
+			"~/.zprofile",
+		},
+		"fish": {
+			"~/.config/fish/config.fish",
+			"~/.config/fish/functions",
+			"~/.config/fish/conf.d",
+		},
+	}
+
+	if runtime.GOOS == "windows" {
+		paths["powershell"] = []string{
+			"~/Documents/PowerShell/Microsoft.PowerShell_profile.ps1",
+			"~/Documents/WindowsPowerShell/Microsoft.PowerShell_profile.ps1",
+		}
+		paths["cmd"] = []string{
+			`%LOCALAPPDATA%\clink\clink_settings`,
+		}
+	}
+
+	return paths
+}
+
+// Build a command that runs through the platform's shell, killed along
+// with the processes it started when ctx is done
+func shellCommandContext(ctx context.Context, cmd string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "cmd", "/C", cmd)
+	}
+	command := exec.CommandContext(ctx, "sh", "-c", cmd)
+	killGroupOnCancel(command)
+	return command
+}
+
+// Expand %VAR% references the way cmd.exe does, leaving unknown ones intact
+func expandWindowsEnv(path string) string {
+	return windowsEnvRegex.ReplaceAllStringFunc(path, func(match string) string {
+		if value, ok := os.LookupEnv(strings.Trim(match, "%")); ok {
+			return value
+		}
+		return match
+	})
+}
+
+func homeDir() (string, error) {
+	if runtime.GOOS == "windows" {
+		if profile := os.Getenv("USERPROFILE"); profile != "" {
+			return profile, nil
+		}
+	}
+	return os.UserHomeDir()
+}
+
+func ExpandPath(path string) string {
+	return expandHomePath(path, "")
+}
+
+// Like ExpandPath, with ~ standing for home instead of the user's home
+// directory when home is set
+func expandHomePath(path, home string) string {
+	if runtime.GOOS == "windows" {
+		path = expandWindowsEnv(path)
+	}
+
+	if path == "~" || strings.HasPrefix(path, "~/") || strings.HasPrefix(path, `~\`) {
+		if home == "" {
+			var err error
+			if home, err = homeDir(); err != nil {
+				return path
+			}
+		}
+		path = filepath.Join(home, path[1:])
+	}
+
+	// Normalize separators so C:/Users/me and C:\Users\me resolve the same
+	return filepath.Clean(filepath.FromSlash(path))
+}
@@ -0,0 +1,77 @@
+package analyzer
+
+import (
+	"maps"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestResolveHistoryPaths(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fish and $HISTFILE defaults are Unix-only")
+	}
+	dataHome := filepath.Join(t.TempDir(), "data")
+	tests := []struct {
+		name      string
+		histFile  string
+		dataHome  string
+		overrides map[string]string
+		home      string
+		want      map[string]string
+	}{
+		{
+			name: "environment unset",
+			want: defaultHistoryPaths(),
+		},
+		{
+			name:     "HISTFILE for the login shell",
+			histFile: "/tmp/zsh-history",
+			want: map[string]string{
+				"bash": "~/.bash_history",
+				"zsh":  "/tmp/zsh-history",
+				"fish": "~/.local/share/fish/fish_history",
+			},
+		},
+		{
+			name:     "XDG_DATA_HOME for fish",
+			dataHome: dataHome,
+			want: map[string]string{
+				"bash": "~/.bash_history",
+				"zsh":  "~/.zsh_history",
+				"fish": filepath.Join(dataHome, "fish", "fish_history"),
+			},
+		},
+		{
+			name:      "overrides win over the environment",
+			histFile:  "/tmp/zsh-history",
+			dataHome:  dataHome,
+			overrides: map[string]string{"zsh": "/srv/zsh", "fish": "/srv/fish"},
+			want: map[string]string{
+				"bash": "~/.bash_history",
+				"zsh":  "/srv/zsh",
+				"fish": "/srv/fish",
+			},
+		},
+		{
+			name:      "another home ignores the environment",
+			histFile:  "/tmp/zsh-history",
+			dataHome:  dataHome,
+			overrides: map[string]string{"bash": "/srv/bash"},
+			home:      "/home/other",
+			want: map[string]string{
+				"bash": "/srv/bash",
+				"zsh":  "~/.zsh_history",
+				"fish": "~/.local/share/fish/fish_history",
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Setenv("SHELL", "/usr/bin/zsh")
+		t.Setenv("HISTFILE", test.histFile)
+		t.Setenv("XDG_DATA_HOME", test.dataHome)
+		if got := resolveHistoryPaths(test.overrides, test.home); !maps.Equal(got, test.want) {
+			t.Errorf("%s: resolveHistoryPaths = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// PipelineStats counts tools by where they appear in pipelines: sources
+// produce the input, sinks consume the final output, and filters sit in
+// between, both piped into and from
+type PipelineStats struct {
+	Pipelines int
+	Stages    int
+	Sources   map[string]int
+	Filters   map[string]int
+	Sinks     map[string]int
+}
+
+// Split a simple command into its pipeline stages on | and |&. Pipes
+// inside quotes, $(...) or backticks, and the >| redirection, don't split.
+func splitPipeline(cmd string) []string {
+	var stages []string
+	start := 0
+	inSingle, inDouble, inBacktick := false, false, false
+	depth := 0
+
+	for i := 0; i < len(cmd); i++ {
+		c := cmd[i]
+		switch {
+		case c == '\\' && !inSingle:
+			i++
+		case c == '\'' && !inDouble && !inBacktick:
+			inSingle = !inSingle
+		case c == '"' && !inSingle && !inBacktick:
+			inDouble = !inDouble
+		case c == '`' && !inSingle:
+			inBacktick = !inBacktick
+		case inSingle || inDouble || inBacktick:
+		case c == '(':
+			depth++
+		case c == ')' && depth > 0:
+			depth--
+		case c == '|' && depth == 0 && (i == 0 || cmd[i-1] != '>'):
+			stages = append(stages, strings.TrimSpace(cmd[start:i]))
+			// |& also pipes stderr
+			if i+1 < len(cmd) && cmd[i+1] == '&' {
+				i++
+			}
+			start = i + 1
+		}
+	}
+	return append(stages, strings.TrimSpace(cmd[start:]))
+}
+
+// The tool a pipeline stage runs, skipping variable assignments and sudo
+func pipelineTool(stage string) string {
+	for _, field := range strings.Fields(stage) {
+		if strings.Contains(field, "=") || field == "sudo" {
+			continue
+		}
+		return filepath.Base(field)
+	}
+	return ""
+}
+
+func analyzePipelines(entries []CommandEntry) PipelineStats {
+	stats := PipelineStats{
+		Sources: make(map[string]int),
+		Filters: make(map[string]int),
+		Sinks:   make(map[string]int),
+	}
+
+	for _, entry := range entries {
+		for _, cmd := range SplitCommand(entry.Command) {
+			stages := splitPipeline(cmd)
+			if len(stages) < 2 {
+				continue
+			}
+			stats.Pipelines++
+			stats.Stages += len(stages)
+
+			for i, stage := range stages {
+				tool := pipelineTool(stage)
+				if tool == "" {
+					continue
+				}
+				switch i {
+				case 0:
+					stats.Sources[tool]++
+				case len(stages) - 1:
+					stats.Sinks[tool]++
+				default:
+					stats.Filters[tool]++
+				}
+			}
+		}
+	}
+
+	return stats
+}
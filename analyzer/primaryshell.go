@@ -0,0 +1,102 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Weights of the signals the primary shell is inferred from. $SHELL is
+// worth as much as having all of the history, since it's what opens in
+// every new terminal.
+const (
+	primaryVolumeWeight  = 1.0
+	primaryRecencyWeight = 1.0
+	primaryConfigWeight  = 0.5
+	primaryLoginWeight   = 1.0
+
+	// Commands within this window count toward recency
+	primaryRecencyWindow = 30 * 24 * time.Hour
+)
+
+// The user's login shell from $SHELL, like "zsh", or "" when unset
+func loginShell() string {
+	shell := os.Getenv("SHELL")
+	if shell == "" {
+		return ""
+	}
+	return strings.TrimSuffix(filepath.Base(shell), ".exe")
+}
+
+// Score each analyzed shell by its share of all commands, of recent
+// commands, and of aliases and plugins, plus a bonus for the login shell
+func primaryShellScores(data ShellData, login string, now time.Time) map[string]float64 {
+	shells := make(map[string]bool)
+	for shell := range data.Histories {
+		shells[shell] = true
+	}
+	for shell := range data.ShellConfigs {
+		shells[shell] = true
+	}
+
+	commands, recent, config := make(map[string]int), make(map[string]int), make(map[string]int)
+	var totalCommands, totalRecent, totalConfig int
+	for shell := range shells {
+		for _, entry := range data.Histories[shell] {
+			commands[shell] += entry.Runs()
+			if !entry.Timestamp.IsZero() && now.Sub(entry.Timestamp) <= primaryRecencyWindow {
+				recent[shell]++
+			}
+		}
+		config[shell] = len(data.ShellConfigs[shell].Aliases) + len(data.ShellConfigs[shell].Plugins)
+		totalCommands += commands[shell]
+		totalRecent += recent[shell]
+		totalConfig += config[shell]
+	}
+
+	scores := make(map[string]float64)
+	for shell := range shells {
+		var score float64
+		if totalCommands > 0 {
+			score += primaryVolumeWeight * float64(commands[shell]) / float64(totalCommands)
+		}
+		if totalRecent > 0 {
+			score += primaryRecencyWeight * float64(recent[shell]) / float64(totalRecent)
+		}
+		if totalConfig > 0 {
+			score += primaryConfigWeight * float64(config[shell]) / float64(totalConfig)
+		}
+		if shell == login {
+			score += primaryLoginWeight
+		}
+		scores[shell] = score
+	}
+	return scores
+}
+
+// The shell with the highest score. Ties go to the login shell, then to
+// the shell that sorts first so the result is stable.
+func inferPrimaryShell(data ShellData, login string, now time.Time) string {
+	scores := primaryShellScores(data, login, now)
+	var shells []string
+	for shell, score := range scores {
+		if score > 0 {
+			shells = append(shells, shell)
+		}
+	}
+	if len(shells) == 0 {
+		return ""
+	}
+	sort.Slice(shells, func(i, j int) bool {
+		if scores[shells[i]] != scores[shells[j]] {
+			return scores[shells[i]] > scores[shells[j]]
+		}
+		if (shells[i] == login) != (shells[j] == login) {
+			return shells[i] == login
+		}
+		return shells[i] < shells[j]
+	})
+	return shells[0]
+}
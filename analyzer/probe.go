@@ -0,0 +1,61 @@
+package analyzer
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Defaults for running external commands like version probes
+const (
+	defaultProbeConcurrency = 8
+	defaultCommandTimeout   = 2 * time.Second
+)
+
+// Run shell commands concurrently, at most limit at a time, and return the
+// output of those that succeed, keyed like commands. A command that
+// doesn't finish within timeout is killed and left out, so one hanging
+// tool can't stall startup. A limit below 1 means the default.
+func runProbes(commands map[string]string, limit int, timeout time.Duration) map[string]string {
+	if limit < 1 {
+		limit = defaultProbeConcurrency
+	}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, limit)
+	results := make(map[string]string)
+
+	for name, cmd := range commands {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			out, err := RunWithTimeout(cmd, timeout)
+			if err != nil {
+				return
+			}
+			mu.Lock()
+			results[name] = out
+			mu.Unlock()
+		}()
+	}
+
+	wg.Wait()
+	return results
+}
+
+func RunWithTimeout(cmd string, timeout time.Duration) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	command := shellCommandContext(ctx, cmd)
+	// Children of the shell may keep its output open after it's killed
+	command.WaitDelay = timeout
+	out, err := command.Output()
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
@@ -0,0 +1,131 @@
+package analyzer
+
+import (
+	"bytes"
+	"maps"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRunProbesTimesOutSlowCommands(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sleep from a POSIX shell")
+	}
+
+	start := time.Now()
+	results := runProbes(map[string]string{
+		"fast":   "echo ok",
+		"slow":   "sleep 10",
+		"reads":  "read line; echo $line",
+		"failed": "exit 3",
+	}, 2, 200*time.Millisecond)
+
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("probes took %v; the slow one wasn't killed", elapsed)
+	}
+	// A command waiting for input gets EOF instead of hanging, so it
+	// succeeds with empty output
+	want := map[string]string{"fast": "ok\n", "reads": "\n"}
+	if !maps.Equal(results, want) {
+		t.Errorf("results = %q, want %q", results, want)
+	}
+}
+
+func TestRunProbesLimitsConcurrency(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sleep from a POSIX shell")
+	}
+
+	commands := map[string]string{"a": "sleep 0.2", "b": "sleep 0.2", "c": "sleep 0.2", "d": "sleep 0.2"}
+	start := time.Now()
+	results := runProbes(commands, 1, 5*time.Second)
+	if elapsed := time.Since(start); elapsed < 800*time.Millisecond {
+		t.Errorf("4 probes one at a time took %v, want at least 800ms", elapsed)
+	}
+	if len(results) != 4 {
+		t.Errorf("got %d results, want 4", len(results))
+	}
+
+	// A limit below 1 falls back to the default instead of blocking
+	done := make(chan map[string]string)
+	go func() { done <- runProbes(map[string]string{"a": "echo a"}, 0, time.Second) }()
+	select {
+	case results := <-done:
+		if results["a"] != "a\n" {
+			t.Errorf("results = %q", results)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("runProbes blocked with a limit of 0")
+	}
+}
+
+// Whether pid is a live process, reading /proc so an unreaped zombie
+// counts as gone
+func processRunning(pid int) bool {
+	stat, err := os.ReadFile(filepath.Join("/proc", strconv.Itoa(pid), "stat"))
+	if err != nil {
+		return false
+	}
+	_, state, _ := bytes.Cut(stat, []byte(") "))
+	return len(state) > 0 && state[0] != 'Z'
+}
+
+func TestRunProbesKillsChildrenOnTimeout(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("checks processes through /proc")
+	}
+
+	// The shell starts a child that would outlive it
+	pidFile := filepath.Join(t.TempDir(), "pid")
+	start := time.Now()
+	results := runProbes(map[string]string{
+		"hung": "sleep 30 & echo $! > " + pidFile + "; wait",
+	}, 1, 300*time.Millisecond)
+	if len(results) != 0 {
+		t.Errorf("results = %q, want the hung probe left out", results)
+	}
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Errorf("probe took %v after timing out", elapsed)
+	}
+
+	content, err := os.ReadFile(pidFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(content)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for deadline := time.Now().Add(2 * time.Second); processRunning(pid); {
+		if time.Now().After(deadline) {
+			t.Fatalf("child %d of the timed-out probe is still running", pid)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func TestGetInstalledLanguagesSkipsHungTools(t *testing.T) {
+	fakeTools(t, "go", "node", "git")
+	// A python3 that never answers
+	hung := filepath.Join(os.Getenv("PATH"), "python3")
+	if err := os.WriteFile(hung, []byte("#!/bin/sh\nexec sleep 30\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := DefaultConfig()
+	cfg.CommandTimeoutSeconds = 0.3
+	cfg.ProbeConcurrency = 2
+	want := map[string]string{"go": "go 1.0\n", "node": "node 1.0\n", "git": "git 1.0\n"}
+
+	// The same tools are found however the probes are scheduled
+	for range 3 {
+		if got := getInstalledLanguages(cfg); !maps.Equal(got, want) {
+			t.Errorf("getInstalledLanguages = %q, want %q without the hung python3", got, want)
+		}
+	}
+}
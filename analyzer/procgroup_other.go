@@ -0,0 +1,8 @@
+//go:build !unix
+
+package analyzer
+
+import "os/exec"
+
+// Process groups are Unix-only; elsewhere only cmd itself is killed
+func killGroupOnCancel(cmd *exec.Cmd) {}
@@ -0,0 +1,208 @@
+package analyzer
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+const (
+	// Commands run in a directory before it's reported as a project
+	minProjectCommands = 5
+	maxProjects        = 10
+	MaxProjectTools    = 3
+)
+
+// Commands that identify the language a project is written in
+var projectLanguageCommands = map[string]string{
+	"go": "Go", "gofmt": "Go",
+	"python": "Python", "python3": "Python", "pip": "Python", "pip3": "Python",
+	"pytest": "Python", "poetry": "Python", "uv": "Python",
+	"node": "JavaScript", "npm": "JavaScript", "npx": "JavaScript",
+	"yarn": "JavaScript", "pnpm": "JavaScript", "deno": "JavaScript", "bun": "JavaScript",
+	"tsc": "TypeScript", "ts-node": "TypeScript",
+	"cargo": "Rust", "rustc": "Rust",
+	"java": "Java", "javac": "Java", "mvn": "Java", "gradle": "Java", "gradlew": "Java",
+	"ruby": "Ruby", "bundle": "Ruby", "rails": "Ruby", "rake": "Ruby",
+	"php": "PHP", "composer": "PHP",
+	"dotnet": ".NET", "swift": "Swift",
+	"mix": "Elixir", "iex": "Elixir",
+}
+
+// PrivateProjects counts the projects in directories matching
+// private_paths, which are left out of the Projects view and exports
+type PrivateProjects struct {
+	Projects int
+	Commands int
+}
+
+func validatePrivatePaths(patterns []string) error {
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("private_paths: %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// Whether dir or a directory above it matches one of the patterns. Both
+// sides have ~ expanded to home, or the user's home directory when it's
+// empty, so "~/private" also hides "/home/me/private".
+func isPrivatePath(dir string, patterns []string, home string) bool {
+	if len(patterns) == 0 {
+		return false
+	}
+	dir = path.Clean(expandHomePath(dir, home))
+	for _, pattern := range patterns {
+		pattern = path.Clean(expandHomePath(pattern, home))
+		for d := dir; ; d = path.Dir(d) {
+			if matched, _ := path.Match(pattern, d); matched {
+				return true
+			}
+			if d == "/" || d == "." {
+				break
+			}
+		}
+	}
+	return false
+}
+
+// Project is a directory the user worked in, inferred from cd commands,
+// with the languages and tools used while there
+type Project struct {
+	Path      string
+	Commands  int
+	Languages map[string]int
+	Tools     map[string]int
+}
+
+// The language with the most commands, or "" when none was seen
+func (p Project) Language() string {
+	best := ""
+	for language, count := range p.Languages {
+		if best == "" || count > p.Languages[best] || count == p.Languages[best] && language < best {
+			best = language
+		}
+	}
+	return best
+}
+
+// Follow cd commands through a history to know which directory each
+// command ran in. Histories don't record the working directory, so this
+// is a guess: descending into subdirectories keeps the current project,
+// while jumping elsewhere starts a new one.
+type directoryTracker struct {
+	cwd     string
+	project string
+
+	// Where "cd -" goes back to
+	previousCwd     string
+	previousProject string
+}
+
+func (t *directoryTracker) cd(target string) {
+	fromCwd, fromProject := t.cwd, t.project
+	switch {
+	case target == "" || target == "~" || target == "$HOME":
+		t.cwd, t.project = "~", ""
+	case target == "-":
+		t.cwd, t.project = t.previousCwd, t.previousProject
+	case strings.HasPrefix(target, "/") || strings.HasPrefix(target, "~/"):
+		t.cwd = path.Clean(target)
+		t.project = t.cwd
+	case t.cwd == "":
+		// Relative to a directory we don't know
+		return
+	default:
+		t.cwd = path.Clean(path.Join(t.cwd, target))
+		// Leaving the project through ".." starts a new one
+		if t.project == "" || t.cwd != t.project && !strings.HasPrefix(t.cwd, t.project+"/") {
+			t.project = t.cwd
+		}
+	}
+	t.previousCwd, t.previousProject = fromCwd, fromProject
+	// Home and the filesystem root aren't projects
+	if t.project == "~" || t.project == "/" {
+		t.project = ""
+	}
+}
+
+// The target of a cd command, or false when cmd isn't one
+func cdTarget(cmd string) (string, bool) {
+	fields := strings.Fields(cmd)
+	if len(fields) == 0 || fields[0] != "cd" {
+		return "", false
+	}
+	for _, arg := range fields[1:] {
+		if arg == "-" || !strings.HasPrefix(arg, "-") {
+			return strings.Trim(arg, `"'`), true
+		}
+	}
+	return "", true
+}
+
+// Projects found by following cd, with those under private paths only
+// counted. ~ in private paths stands for home, as in isPrivatePath.
+func analyzeProjects(sequences [][]CommandEntry, private []string, home string) ([]Project, PrivateProjects) {
+	projects := make(map[string]*Project)
+	for _, sequence := range sequences {
+		var tracker directoryTracker
+		for _, entry := range sequence {
+			for _, cmd := range SplitCommand(entry.Command) {
+				if target, ok := cdTarget(cmd); ok {
+					tracker.cd(target)
+					continue
+				}
+				if tracker.project == "" {
+					continue
+				}
+
+				project, ok := projects[tracker.project]
+				if !ok {
+					project = &Project{
+						Path:      tracker.project,
+						Languages: make(map[string]int),
+						Tools:     make(map[string]int),
+					}
+					projects[tracker.project] = project
+				}
+				project.Commands++
+
+				name := BaseCommand(cmd)
+				if name == "" || ShellBuiltins[name] {
+					continue
+				}
+				if language, ok := projectLanguageCommands[name]; ok {
+					project.Languages[language]++
+				} else {
+					project.Tools[name]++
+				}
+			}
+		}
+	}
+
+	var found []Project
+	var hidden PrivateProjects
+	for _, project := range projects {
+		if project.Commands < minProjectCommands {
+			continue
+		}
+		if isPrivatePath(project.Path, private, home) {
+			hidden.Projects++
+			hidden.Commands += project.Commands
+			continue
+		}
+		found = append(found, *project)
+	}
+	sort.Slice(found, func(i, j int) bool {
+		if found[i].Commands != found[j].Commands {
+			return found[i].Commands > found[j].Commands
+		}
+		return found[i].Path < found[j].Path
+	})
+	if len(found) > maxProjects {
+		found = found[:maxProjects]
+	}
+	return found, hidden
+}
@@ -0,0 +1,40 @@
+package analyzer
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// Lines that themed prompts leak into history files instead of commands:
+// a leading prompt glyph or emoji, a bare git branch like "git:(main)" or
+// "(main) ✗", and timestamps with no command after them
+var defaultPromptNoisePatterns = []string{
+	`^[❯➜➤▶►λ»→✗✔✘⚡]`,
+	`^[\x{1F300}-\x{1FAFF}\x{2600}-\x{27BF}]`,
+	`^(on\s+)?\x{E0A0}`,
+	`^git:\([^)]*\)\s*[✗✔*]?$`,
+	`^\([\w./-]+\)\s*[✗✔±]+$`,
+	`^\[?\d{1,2}:\d{2}(:\d{2})?(\s?[AaPp][Mm])?\]?$`,
+	`^\[?\d{4}-\d{2}-\d{2}[ T]\d{2}:\d{2}(:\d{2})?\]?$`,
+}
+
+func compilePromptNoise(patterns []string) ([]*regexp.Regexp, error) {
+	var compiled []*regexp.Regexp
+	for _, pattern := range patterns {
+		regex, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("prompt_noise_patterns: %w", err)
+		}
+		compiled = append(compiled, regex)
+	}
+	return compiled, nil
+}
+
+func isPromptNoise(line string, rules []*regexp.Regexp) bool {
+	for _, rule := range rules {
+		if rule.MatchString(line) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,132 @@
+package analyzer
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// SavedQuery is a named slice of the history defined in config, like
+// "git commands in the last 30 days". Empty fields don't filter.
+type SavedQuery struct {
+	Name string `json:"name"`
+
+	// Command prefix, so "git" matches "git status" but not "gitk"
+	Command string `json:"command"`
+	Shell   string `json:"shell"`
+
+	// Either a rolling window or fixed YYYY-MM-DD bounds, both inclusive
+	LastDays int    `json:"last_days"`
+	Since    string `json:"since"`
+	Until    string `json:"until"`
+}
+
+// QueryResult is what a saved query matched
+type QueryResult struct {
+	Matches  int
+	Commands map[string]int
+	First    time.Time
+	Last     time.Time
+
+	// Commands left out of a date-filtered query for having no timestamp
+	Undated int
+}
+
+func (q SavedQuery) hasDateRange() bool {
+	return q.LastDays > 0 || q.Since != "" || q.Until != ""
+}
+
+// The time range a query covers; zero times are unbounded
+func (q SavedQuery) bounds(now time.Time) (from, to time.Time, err error) {
+	if q.Since != "" {
+		if from, err = time.ParseInLocation(DayLayout, q.Since, now.Location()); err != nil {
+			return from, to, fmt.Errorf("saved query %q: since: %w", q.Name, err)
+		}
+	}
+	if q.Until != "" {
+		if to, err = time.ParseInLocation(DayLayout, q.Until, now.Location()); err != nil {
+			return from, to, fmt.Errorf("saved query %q: until: %w", q.Name, err)
+		}
+		// Include the whole last day
+		to = to.AddDate(0, 0, 1)
+	}
+	if q.LastDays > 0 {
+		today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+		from = today.AddDate(0, 0, 1-q.LastDays)
+	}
+	return from, to, nil
+}
+
+// Check that every query has a name and valid dates
+func validateSavedQueries(queries []SavedQuery) error {
+	for _, query := range queries {
+		if query.Name == "" {
+			return errors.New("saved query without a name")
+		}
+		if _, _, err := query.bounds(time.Now()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func RunQuery(data ShellData, query SavedQuery, now time.Time) QueryResult {
+	result := QueryResult{Commands: make(map[string]int)}
+	from, to, _ := query.bounds(now)
+	inRange := func(t time.Time) bool {
+		return (from.IsZero() || !t.Before(from)) && (to.IsZero() || t.Before(to))
+	}
+
+	for shell, history := range data.Histories {
+		if query.Shell != "" && shell != query.Shell {
+			continue
+		}
+		for _, entry := range history {
+			var matched []string
+			for _, cmd := range SplitCommand(entry.Command) {
+				if query.Command == "" || MatchesCommandPrefix(cmd, query.Command) {
+					matched = append(matched, cmd)
+				}
+			}
+			if len(matched) == 0 {
+				continue
+			}
+
+			runs := entry.Runs()
+			first, last := entry.Seen()
+			if query.hasDateRange() {
+				if first.IsZero() {
+					result.Undated += runs
+					continue
+				}
+				// Repeats only keep their first and last times, so a command
+				// that also ran outside the range counts the runs known to be
+				// in it
+				switch firstIn, lastIn := inRange(first), inRange(last); {
+				case firstIn && lastIn:
+				case firstIn:
+					runs, last = 1, first
+				case lastIn:
+					runs, first = 1, last
+				default:
+					continue
+				}
+			}
+
+			result.Matches += runs
+			for _, cmd := range matched {
+				result.Commands[cmd] += runs
+			}
+			if !first.IsZero() {
+				if result.First.IsZero() || first.Before(result.First) {
+					result.First = first
+				}
+				if last.After(result.Last) {
+					result.Last = last
+				}
+			}
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,39 @@
+package analyzer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRunQueryMergedRepeats(t *testing.T) {
+	now := time.Date(2024, 4, 30, 12, 0, 0, 0, time.UTC)
+	data := NewShellData()
+	data.Histories["bash"] = collapseRepeats([]CommandEntry{
+		{Command: "git status", Timestamp: now.AddDate(0, 0, -60)},
+		{Command: "git status", Timestamp: now.AddDate(0, 0, -2)},
+		{Command: "git status", Timestamp: now.AddDate(0, 0, -1)},
+		{Command: "git push", Timestamp: now.AddDate(0, 0, -3)},
+		{Command: "git push", Timestamp: now.AddDate(0, 0, -1)},
+		{Command: "git log"},
+	})
+
+	tests := []struct {
+		query   SavedQuery
+		matches int
+		undated int
+	}{
+		// Every run of every merged entry
+		{SavedQuery{Command: "git"}, 6, 0},
+		// Both runs of git push, and git status's latest, the one run
+		// known to be in range
+		{SavedQuery{Command: "git", LastDays: 7}, 3, 1},
+		{SavedQuery{Command: "git push", Since: "2024-04-27", Until: "2024-04-29"}, 2, 0},
+	}
+	for _, tt := range tests {
+		result := RunQuery(data, tt.query, now)
+		if result.Matches != tt.matches || result.Undated != tt.undated {
+			t.Errorf("RunQuery(%+v) = %d matches, %d undated, want %d and %d",
+				tt.query, result.Matches, result.Undated, tt.matches, tt.undated)
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package analyzer
+
+import "time"
+
+// How often serve and watch modes re-run the analysis when no refresh
+// interval is configured
+const defaultServeInterval = 5 * time.Minute
+
+func (c Config) RefreshInterval() time.Duration {
+	if c.RefreshIntervalMinutes <= 0 {
+		return 0
+	}
+	return time.Duration(c.RefreshIntervalMinutes * float64(time.Minute))
+}
+
+// The refresh interval for serve and watch modes, which always refresh
+func (c Config) ServeInterval() time.Duration {
+	if interval := c.RefreshInterval(); interval > 0 {
+		return interval
+	}
+	return defaultServeInterval
+}
@@ -0,0 +1,15 @@
+package analyzer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestServeInterval(t *testing.T) {
+	if got := (Config{}).ServeInterval(); got != defaultServeInterval {
+		t.Errorf("unset interval = %v, want %v", got, defaultServeInterval)
+	}
+	if got := (Config{RefreshIntervalMinutes: 0.5}).ServeInterval(); got != 30*time.Second {
+		t.Errorf("configured interval = %v, want 30s", got)
+	}
+}
@@ -0,0 +1,155 @@
+package analyzer
+
+import (
+	"fmt"
+	"math"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// RoleWeights controls how each signal contributes to role inference
+type RoleWeights struct {
+	History          float64 `json:"history"`
+	Recency          float64 `json:"recency"`
+	Config           float64 `json:"config"`
+	HalfLifeDays     float64 `json:"half_life_days"`
+	ConfigWindowDays float64 `json:"config_window_days"`
+}
+
+func defaultRoleWeights() RoleWeights {
+	return RoleWeights{
+		History:          1,
+		Recency:          1,
+		Config:           0.5,
+		HalfLifeDays:     30,
+		ConfigWindowDays: 30,
+	}
+}
+
+// Whether cmd runs lang or its package manager. Whole words are matched,
+// so goto_dir doesn't count as go and pipenv doesn't count as pip.
+func commandUsesLanguage(cmd, lang string) bool {
+	fields, _ := unwrapCommand(cmd)
+	if len(fields) == 0 {
+		return false
+	}
+	if filepath.Base(fields[0]) == lang {
+		return true
+	}
+	manager := strings.Fields(getPackageManager(lang))
+	return len(manager) > 0 && len(fields) >= len(manager) &&
+		slices.Equal(fields[:len(manager)], manager)
+}
+
+// Infer the primary role from three signals, each normalized to a share
+// across the candidate languages:
+//   - history: how often each language appears in the whole history
+//   - recency: the same counts, decayed by age with a half-life
+//   - config: aliases and plugins for the language in recently changed config
+func inferPrimaryRole(entries []CommandEntry, data *ShellData, weights RoleWeights, now time.Time) {
+	profile := &data.Insights.TechnicalProfile
+	if len(profile.LanguageUsage) == 0 {
+		return
+	}
+
+	history := make(map[string]float64)
+	recency := make(map[string]float64)
+	config := make(map[string]float64)
+
+	for lang, count := range profile.LanguageUsage {
+		history[lang] = float64(count)
+	}
+
+	if weights.Recency > 0 && weights.HalfLifeDays > 0 {
+		for _, entry := range entries {
+			if entry.Timestamp.IsZero() {
+				continue
+			}
+			age := now.Sub(entry.Timestamp).Hours() / 24
+			decay := math.Pow(0.5, math.Max(age, 0)/weights.HalfLifeDays)
+			for lang := range profile.LanguageUsage {
+				if commandUsesLanguage(entry.Command, lang) {
+					recency[lang] += decay
+				}
+			}
+		}
+	}
+
+	if weights.Config > 0 {
+		window := time.Duration(weights.ConfigWindowDays*24) * time.Hour
+		for shell, shellConfig := range data.ShellConfigs {
+			for _, file := range shellConfig.ConfigFiles {
+				if now.Sub(file.Modified) > window {
+					continue
+				}
+				recent := ShellConfig{
+					Aliases:     make(map[string]string),
+					Environment: make(map[string]string),
+					Secrets:     NewSecretHygiene(),
+
+					AliasDefinitions: make(map[string][]AliasDefinition),
+					HistoryOptions:   make(map[string]bool),
+				}
+				parseShellConfig(shell, file.Path, file.Content, &recent)
+				for _, value := range recent.Aliases {
+					for lang := range profile.LanguageUsage {
+						if commandUsesLanguage(value, lang) {
+							config[lang]++
+						}
+					}
+				}
+			}
+			for _, plugin := range shellConfig.Plugins {
+				if now.Sub(plugin.LastUpdated) > window {
+					continue
+				}
+				for lang := range profile.LanguageUsage {
+					if strings.Contains(strings.ToLower(plugin.Name), lang) {
+						config[lang]++
+					}
+				}
+			}
+		}
+	}
+
+	historyShare := normalizeShares(history)
+	recencyShare := normalizeShares(recency)
+	configShare := normalizeShares(config)
+
+	profile.RoleScores = make(map[string]float64)
+	for lang := range profile.LanguageUsage {
+		profile.RoleScores[lang] = weights.History*historyShare[lang] +
+			weights.Recency*recencyShare[lang] +
+			weights.Config*configShare[lang]
+	}
+
+	var best string
+	for lang, score := range profile.RoleScores {
+		// Break ties by name so the result is stable
+		if best == "" || score > profile.RoleScores[best] ||
+			(score == profile.RoleScores[best] && lang < best) {
+			best = lang
+		}
+	}
+	if profile.RoleScores[best] > 0 {
+		profile.PrimaryRole = fmt.Sprintf("%s Developer", strings.Title(best))
+	}
+}
+
+func normalizeShares(values map[string]float64) map[string]float64 {
+	var total float64
+	for _, value := range values {
+		total += value
+	}
+
+	shares := make(map[string]float64)
+	if total == 0 {
+		return shares
+	}
+	for key, value := range values {
+		shares[key] = value / total
+	}
+	return shares
+}
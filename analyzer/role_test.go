@@ -0,0 +1,45 @@
+package analyzer
+
+import "testing"
+
+func TestCommandUsesLanguage(t *testing.T) {
+	tests := []struct {
+		cmd  string
+		lang string
+		want bool
+	}{
+		{"go build ./...", "go", true},
+		{"go get example.com/mod", "go", true},
+		{"/usr/local/go/bin/go test", "go", true},
+		{"python3 app.py", "python3", true},
+		{"pip install requests", "python", true},
+		{"cargo build", "rust", true},
+
+		// Whole words only
+		{"goto_dir src", "go", false},
+		{"goto src", "go", false},
+		{"gofmt -l .", "go", false},
+		{"echo go", "go", false},
+		{"pipenv install", "python", false},
+		{"pip3 install requests", "python", false},
+		{"cd go/src", "go", false},
+		{"", "go", false},
+	}
+	for _, test := range tests {
+		if got := commandUsesLanguage(test.cmd, test.lang); got != test.want {
+			t.Errorf("commandUsesLanguage(%q, %q) = %v, want %v", test.cmd, test.lang, got, test.want)
+		}
+	}
+}
+
+func TestGotoDoesNotCountAsGo(t *testing.T) {
+	var entries []CommandEntry
+	for _, cmd := range []string{"goto_dir src", "goto work", "go test ./...", "gopls version"} {
+		entries = append(entries, CommandEntry{Command: cmd})
+	}
+	data := NewShellData()
+	analyzeCommands(entries, &data, DefaultConfig(), map[string]string{"go": "go1.22"})
+	if got := data.Insights.TechnicalProfile.LanguageUsage["go"]; got != 1 {
+		t.Errorf("go usage = %d, want 1 from go test only", got)
+	}
+}
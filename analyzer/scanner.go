@@ -0,0 +1,50 @@
+package analyzer
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+)
+
+// Default longest history line read, in bytes
+const defaultMaxLineBytes = 1 << 20
+
+// Scan lines like bufio.ScanLines, but skip lines that don't fit in limit
+// bytes instead of failing with bufio.ErrTooLong and losing the rest of
+// the file. A cut-off command would be analyzed as something it isn't, so
+// nothing of the line is kept. The number of skipped lines is added to
+// skipped.
+func newLineScanner(r io.Reader, limit int, skipped *int) *bufio.Scanner {
+	scanner := bufio.NewScanner(r)
+	// The buffer's starting size also caps tokens, so keep it within limit
+	scanner.Buffer(make([]byte, 0, min(bufio.MaxScanTokenSize, limit)), limit)
+
+	// Set while skipping the rest of a line that's too long
+	discarding := false
+	scanner.Split(func(data []byte, atEOF bool) (int, []byte, error) {
+		if i := bytes.IndexByte(data, '\n'); i >= 0 {
+			if discarding {
+				discarding = false
+				return i + 1, nil, nil
+			}
+			return i + 1, bytes.TrimSuffix(data[:i], []byte("\r")), nil
+		}
+		if atEOF {
+			if discarding || len(data) == 0 {
+				return len(data), nil, nil
+			}
+			return len(data), bytes.TrimSuffix(data, []byte("\r")), nil
+		}
+		if len(data) >= limit {
+			if !discarding {
+				discarding = true
+				*skipped++
+			}
+			return len(data), nil, nil
+		}
+		// Need more data for a full line
+		return 0, nil, nil
+	})
+
+	return scanner
+}
@@ -0,0 +1,89 @@
+package analyzer
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestNewLineScanner(t *testing.T) {
+	long := strings.Repeat("x", 100)
+	tests := []struct {
+		content string
+		want    []string
+		skipped int
+	}{
+		{"ls\r\n" + long + "\npwd\n", []string{"ls", "pwd"}, 1},
+		{long + "\n" + long + "\r\nls", []string{"ls"}, 2},
+		{"ls\n" + long, []string{"ls"}, 1},
+		{"ls\n" + long[:49] + "\n", []string{"ls", long[:49]}, 0},
+		{"\n\nls", []string{"", "", "ls"}, 0},
+	}
+	for _, test := range tests {
+		skipped := 0
+		scanner := newLineScanner(strings.NewReader(test.content), 50, &skipped)
+		var got []string
+		for scanner.Scan() {
+			got = append(got, scanner.Text())
+		}
+		if err := scanner.Err(); err != nil {
+			t.Errorf("%.20q...: %v", test.content, err)
+		}
+		if !slices.Equal(got, test.want) || skipped != test.skipped {
+			t.Errorf("%.20q...: lines = %.20q, skipped %d; want %.20q, %d", test.content, got, skipped, test.want, test.skipped)
+		}
+	}
+}
+
+func TestReadHistoryLongLines(t *testing.T) {
+	// Past bufio.Scanner's default 64KB token limit
+	curl := "curl -d " + strings.Repeat("QUJD", 20_000) + " https://example.com"
+	blob := "echo " + strings.Repeat("A", 300_000)
+	content := "#1712912400\ngit status\n" + curl + "\n" + blob + "\nls -la\n"
+
+	// Read whole within max_line_bytes
+	entries, _, err := readHistory(writeTestHistory(t, ".bash_history", content), DefaultConfig(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := commandsOf(entries); !slices.Equal(got, []string{"git status", curl, blob, "ls -la"}) {
+		t.Errorf("read %d commands, want all 4 whole", len(got))
+	}
+
+	// Skipped past it, with a warning and the rest of the file read
+	cfg := DefaultConfig()
+	cfg.MaxLineBytes = 200_000
+	entries, _, err = readHistory(writeTestHistory(t, ".bash_history", content), cfg, nil)
+	if !errors.Is(err, ErrLineTooLong) || !strings.Contains(err.Error(), "skipped 1 line(s)") {
+		t.Errorf("err = %v, want a warning about the one skipped line", err)
+	}
+	if got := commandsOf(entries); !slices.Equal(got, []string{"git status", curl, "ls -la"}) {
+		t.Errorf("read %.40q, want the blob skipped", got)
+	}
+
+	// The skipped command's timestamp isn't given to another
+	timed := "#1712912400\ngit status\n#1712912500\n" + blob + "\nls -la\n"
+	entries, _, _ = readHistory(writeTestHistory(t, ".bash_history", timed), cfg, nil)
+	if len(entries) != 2 || !entries[1].Timestamp.IsZero() {
+		t.Errorf("entries = %.40v, want ls -la undated", entries)
+	}
+
+	fish := "- cmd: git status\n  when: 1712912400\n- cmd: " + blob + "\n  when: 1712912500\n- cmd: ls -la\n  when: 1712912600\n"
+	entries, _, err = readFishHistory(writeTestHistory(t, "fish_history", fish), cfg, nil)
+	if !errors.Is(err, ErrLineTooLong) || !slices.Equal(commandsOf(entries), []string{"git status", "ls -la"}) {
+		t.Fatalf("fish: entries = %.40q, err = %v; want the blob skipped with a warning", commandsOf(entries), err)
+	}
+	if entries[0].Timestamp.Unix() != 1712912400 {
+		t.Errorf("fish: git status dated %v, want its own when", entries[0].Timestamp)
+	}
+}
+
+// The commands of entries, in order
+func commandsOf(entries []CommandEntry) []string {
+	var commands []string
+	for _, entry := range entries {
+		commands = append(commands, entry.Command)
+	}
+	return commands
+}
@@ -0,0 +1,116 @@
+package analyzer
+
+import (
+	"regexp"
+	"strings"
+)
+
+type SecurityInsights struct {
+	Secrets SecretHygiene
+
+	// Aliases that hide a dangerous command behind another name
+	DangerousAliases []DangerousAlias
+
+	// History entries removed because they look like passwords
+	OrphanPasswords []OrphanPassword
+
+	// Commands run through sudo or doas
+	PrivilegedCommands int
+}
+
+// SecretHygiene tracks how secrets and credentials are loaded into the shell
+type SecretHygiene struct {
+	EnvFileSources   int
+	DirenvUses       int
+	DirenvHooked     bool
+	CredentialTools  map[string]int
+	PlaintextSecrets []string
+
+	// Commands that look like they contain a pasted secret, with the
+	// secret redacted in the samples
+	LeakedSecrets int
+	LeakedSamples []string
+}
+
+var (
+	envFileSourceRegex = regexp.MustCompile(`(^|[;&|]\s*)(source|\.)\s+\S*\.env\S*`)
+	envFileExportRegex = regexp.MustCompile(`(export|env)\s+\$\(\s*(cat|grep|xargs)[^)]*\.env`)
+	direnvHookRegex    = regexp.MustCompile(`direnv\s+hook`)
+	secretNameRegex    = regexp.MustCompile(`(?i)(SECRET|TOKEN|PASSWORD|PASSWD|API_?KEY|ACCESS_KEY|PRIVATE_KEY|CREDENTIAL)`)
+)
+
+var credentialTools = []string{"aws-vault", "pass", "gopass", "op", "vault", "sops"}
+
+func NewSecretHygiene() SecretHygiene {
+	return SecretHygiene{
+		CredentialTools: make(map[string]int),
+	}
+}
+
+func sourcesEnvFile(line string) bool {
+	return envFileSourceRegex.MatchString(line) || envFileExportRegex.MatchString(line)
+}
+
+// Detect secret-management habits in a single history command
+func detectHistorySecrets(cmd string, hygiene *SecretHygiene, entropyThreshold float64) {
+	if sourcesEnvFile(cmd) {
+		hygiene.EnvFileSources++
+	}
+
+	fields := strings.Fields(cmd)
+	if len(fields) == 0 {
+		return
+	}
+
+	// Secrets pasted from the clipboard or typed inline
+	sample := ""
+	if clipboardSecretRegex.MatchString(cmd) {
+		sample = cmd
+	} else if token := findHighEntropyToken(cmd, entropyThreshold); token != "" {
+		sample = redactSecret(cmd, token)
+	}
+	if sample != "" {
+		hygiene.LeakedSecrets++
+		if len(hygiene.LeakedSamples) < MaxSecretSamples {
+			hygiene.LeakedSamples = append(hygiene.LeakedSamples, sample)
+		}
+	}
+
+	base := BaseCommand(cmd)
+	if base == "direnv" {
+		hygiene.DirenvUses++
+	}
+	for _, tool := range credentialTools {
+		if base == tool {
+			hygiene.CredentialTools[tool]++
+		}
+	}
+}
+
+// Detect secret-management setup in a single config line
+func detectConfigSecrets(line string, hygiene *SecretHygiene) {
+	line = strings.TrimSpace(line)
+	if strings.HasPrefix(line, "#") {
+		return
+	}
+
+	if direnvHookRegex.MatchString(line) {
+		hygiene.DirenvHooked = true
+	}
+	if sourcesEnvFile(line) {
+		hygiene.EnvFileSources++
+	}
+
+	// Secrets exported with a literal value rather than fetched from a manager
+	if strings.HasPrefix(line, "export ") {
+		parts := strings.SplitN(strings.TrimPrefix(line, "export "), "=", 2)
+		if len(parts) == 2 {
+			name := strings.TrimSpace(parts[0])
+			value := strings.Trim(strings.TrimSpace(parts[1]), "'\"")
+			if secretNameRegex.MatchString(name) && value != "" &&
+				!strings.HasPrefix(value, "$") && !strings.HasPrefix(value, "`") {
+				hygiene.PlaintextSecrets = append(hygiene.PlaintextSecrets, name)
+			}
+		}
+	}
+}
@@ -0,0 +1,235 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	SnapshotDir     = "~/.local/share/shell-analyser/snapshots"
+	manifestName    = "manifest.json"
+	snapshotPrefix  = "snapshot-"
+	snapshotLayout  = "20060102T150405"
+	tempFilePattern = ".tmp-*"
+)
+
+// Snapshot is the aggregate state of one analysis run, kept for trends
+type Snapshot struct {
+	Taken         time.Time        `json:"taken"`
+	CommandCounts map[string]int   `json:"command_counts"`
+	CommonCmds    map[string]int   `json:"common_cmds"`
+	TimePatterns  map[string]int   `json:"time_patterns"`
+	Insights      DetailedInsights `json:"insights"`
+
+	// Each shell's aliases, plugins and environment, for Config Drift.
+	// Snapshots from before this was recorded don't have it.
+	Config map[string]ConfigSummary `json:"config,omitempty"`
+}
+
+type Manifest struct {
+	Snapshots []ManifestEntry `json:"snapshots"`
+}
+
+type ManifestEntry struct {
+	File  string    `json:"file"`
+	Taken time.Time `json:"taken"`
+}
+
+func NewSnapshot(data ShellData, taken time.Time) Snapshot {
+	snapshot := Snapshot{
+		Taken:         taken,
+		CommandCounts: make(map[string]int),
+		CommonCmds:    data.CommonCmds,
+		TimePatterns:  data.TimePatterns,
+		Insights:      data.Insights,
+		Config:        summarizeConfigs(data.ShellConfigs),
+	}
+	for shell, history := range data.Histories {
+		for _, entry := range history {
+			snapshot.CommandCounts[shell] += entry.Runs()
+		}
+	}
+	return snapshot
+}
+
+// Write data to a temp file in the same directory and rename it into place,
+// so an interrupted write never leaves a truncated file at path
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), tempFilePattern)
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	return nil
+}
+
+func WriteSnapshot(dir string, snapshot Snapshot, keep int) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	content, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	name := snapshotPrefix + snapshot.Taken.UTC().Format(snapshotLayout) + ".json"
+	if err := writeFileAtomic(filepath.Join(dir, name), content); err != nil {
+		return fmt.Errorf("writing snapshot: %w", err)
+	}
+
+	manifest, err := loadManifest(dir)
+	if err != nil {
+		return err
+	}
+	manifest.add(ManifestEntry{File: name, Taken: snapshot.Taken})
+
+	return applyRetention(dir, manifest, keep)
+}
+
+// Load the manifest and reconcile it with the snapshot files on disk, so a
+// run interrupted between writing a snapshot and updating the manifest heals
+func loadManifest(dir string) (Manifest, error) {
+	var manifest Manifest
+
+	content, err := os.ReadFile(filepath.Join(dir, manifestName))
+	if err == nil {
+		if err := json.Unmarshal(content, &manifest); err != nil {
+			// A corrupt manifest is rebuilt from the directory listing
+			manifest = Manifest{}
+		}
+	} else if !os.IsNotExist(err) {
+		return manifest, err
+	}
+
+	files, err := os.ReadDir(dir)
+	if err != nil && !os.IsNotExist(err) {
+		return manifest, err
+	}
+
+	onDisk := make(map[string]bool)
+	for _, file := range files {
+		name := file.Name()
+		if !strings.HasPrefix(name, snapshotPrefix) || !strings.HasSuffix(name, ".json") {
+			continue
+		}
+		onDisk[name] = true
+
+		taken, err := time.Parse(snapshotLayout,
+			strings.TrimSuffix(strings.TrimPrefix(name, snapshotPrefix), ".json"))
+		if err == nil {
+			manifest.add(ManifestEntry{File: name, Taken: taken})
+		}
+	}
+
+	// Drop entries whose snapshot file has gone missing
+	var entries []ManifestEntry
+	for _, entry := range manifest.Snapshots {
+		if onDisk[entry.File] {
+			entries = append(entries, entry)
+		}
+	}
+	manifest.Snapshots = entries
+
+	return manifest, nil
+}
+
+func (m *Manifest) add(entry ManifestEntry) {
+	for _, existing := range m.Snapshots {
+		if existing.File == entry.File {
+			return
+		}
+	}
+	m.Snapshots = append(m.Snapshots, entry)
+	sort.Slice(m.Snapshots, func(i, j int) bool {
+		return m.Snapshots[i].Taken.Before(m.Snapshots[j].Taken)
+	})
+}
+
+// Keep only the newest snapshots and persist the manifest
+func applyRetention(dir string, manifest Manifest, keep int) error {
+	if keep > 0 && len(manifest.Snapshots) > keep {
+		expired := manifest.Snapshots[:len(manifest.Snapshots)-keep]
+		manifest.Snapshots = manifest.Snapshots[len(manifest.Snapshots)-keep:]
+
+		// Persist the shorter manifest before deleting anything it no longer lists
+		if err := writeManifest(dir, manifest); err != nil {
+			return err
+		}
+		for _, entry := range expired {
+			if err := os.Remove(filepath.Join(dir, entry.File)); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return writeManifest(dir, manifest)
+}
+
+func writeManifest(dir string, manifest Manifest) error {
+	content, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(filepath.Join(dir, manifestName), content); err != nil {
+		return fmt.Errorf("writing manifest: %w", err)
+	}
+	return nil
+}
+
+// Apply the retention policy and clean up temp files left by interrupted writes
+func PruneSnapshots(dir string, keep int) (int, error) {
+	leftovers, err := filepath.Glob(filepath.Join(dir, tempFilePattern))
+	if err != nil {
+		return 0, err
+	}
+	for _, path := range leftovers {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return 0, err
+		}
+	}
+
+	manifest, err := loadManifest(dir)
+	if err != nil {
+		return 0, err
+	}
+	before := len(manifest.Snapshots)
+	if before == 0 {
+		return 0, nil
+	}
+
+	if err := applyRetention(dir, manifest, keep); err != nil {
+		return 0, err
+	}
+
+	removed := before - keep
+	if keep <= 0 || removed < 0 {
+		removed = 0
+	}
+	return removed, nil
+}
@@ -0,0 +1,151 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func testSnapshot(taken time.Time) Snapshot {
+	return Snapshot{Taken: taken, CommandCounts: map[string]int{"bash": 1}}
+}
+
+func snapshotFiles(t *testing.T, dir string) []string {
+	t.Helper()
+	names, err := filepath.Glob(filepath.Join(dir, snapshotPrefix+"*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, name := range names {
+		names[i] = filepath.Base(name)
+	}
+	return names
+}
+
+func TestWriteFileAtomicKeepsOldContentOnFailure(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "target")
+	if err := os.WriteFile(path, []byte("old"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// A write into a missing directory fails before anything is replaced
+	if err := writeFileAtomic(filepath.Join(dir, "missing", "target"), []byte("new")); err == nil {
+		t.Fatal("expected an error writing into a missing directory")
+	}
+
+	// A rename onto a non-empty directory fails after the temp file is
+	// written, like a write interrupted at the last step
+	blocked := filepath.Join(dir, "blocked")
+	if err := os.MkdirAll(filepath.Join(blocked, "child"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeFileAtomic(blocked, []byte("new")); err == nil {
+		t.Fatal("expected an error renaming onto a directory")
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil || string(content) != "old" {
+		t.Errorf("target = %q, %v; want the old content", content, err)
+	}
+	if leftovers, _ := filepath.Glob(filepath.Join(dir, tempFilePattern)); len(leftovers) > 0 {
+		t.Errorf("temp files left behind: %v", leftovers)
+	}
+}
+
+func TestWriteSnapshotRetention(t *testing.T) {
+	dir := t.TempDir()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 5; i++ {
+		if err := WriteSnapshot(dir, testSnapshot(start.Add(time.Duration(i)*time.Hour)), 3); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	files := snapshotFiles(t, dir)
+	if len(files) != 3 {
+		t.Fatalf("kept %v, want the newest 3", files)
+	}
+	manifest, err := loadManifest(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(manifest.Snapshots) != 3 || !manifest.Snapshots[0].Taken.Equal(start.Add(2*time.Hour)) {
+		t.Errorf("manifest = %+v, want the newest 3 oldest first", manifest.Snapshots)
+	}
+}
+
+func TestLoadManifestHealsInterruptedRuns(t *testing.T) {
+	dir := t.TempDir()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	if err := WriteSnapshot(dir, testSnapshot(start), 0); err != nil {
+		t.Fatal(err)
+	}
+
+	// A run that wrote its snapshot but died before updating the manifest
+	orphan := snapshotPrefix + start.Add(time.Hour).Format(snapshotLayout) + ".json"
+	if err := os.WriteFile(filepath.Join(dir, orphan), []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// A run that died while writing its temp file
+	if err := os.WriteFile(filepath.Join(dir, ".tmp-123"), []byte(`{"taken":`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	manifest, err := loadManifest(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(manifest.Snapshots) != 2 || manifest.Snapshots[1].File != orphan {
+		t.Errorf("manifest = %+v, want the orphaned snapshot listed last", manifest.Snapshots)
+	}
+
+	// A corrupt manifest is rebuilt from the snapshot files
+	if err := os.WriteFile(filepath.Join(dir, manifestName), []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if manifest, err = loadManifest(dir); err != nil || len(manifest.Snapshots) != 2 {
+		t.Errorf("after corruption manifest = %+v, %v; want 2 entries", manifest.Snapshots, err)
+	}
+
+	// A snapshot deleted by hand drops out of the manifest
+	if err := os.Remove(filepath.Join(dir, orphan)); err != nil {
+		t.Fatal(err)
+	}
+	if manifest, err = loadManifest(dir); err != nil || len(manifest.Snapshots) != 1 {
+		t.Errorf("after removal manifest = %+v, %v; want 1 entry", manifest.Snapshots, err)
+	}
+}
+
+func TestPruneSnapshots(t *testing.T) {
+	dir := t.TempDir()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 4; i++ {
+		if err := WriteSnapshot(dir, testSnapshot(start.Add(time.Duration(i)*time.Hour)), 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".tmp-456"), []byte("partial"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	removed, err := PruneSnapshots(dir, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if removed != 3 {
+		t.Errorf("removed %d snapshots, want 3", removed)
+	}
+	if files := snapshotFiles(t, dir); len(files) != 1 {
+		t.Errorf("kept %v, want 1 snapshot", files)
+	}
+	if leftovers, _ := filepath.Glob(filepath.Join(dir, tempFilePattern)); len(leftovers) > 0 {
+		t.Errorf("temp files left behind: %v", leftovers)
+	}
+
+	// Pruning an empty or missing directory is not an error
+	if removed, err := PruneSnapshots(filepath.Join(dir, "missing"), 1); err != nil || removed != 0 {
+		t.Errorf("missing dir: removed %d, %v", removed, err)
+	}
+}
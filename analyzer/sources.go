@@ -0,0 +1,103 @@
+package analyzer
+
+import (
+	"os"
+	"sort"
+)
+
+// HistorySource is where one shell's history comes from. New shells are
+// added by implementing it and registering a constructor with
+// RegisterHistorySource.
+type HistorySource interface {
+	// The shell the history belongs to, like "zsh"
+	Name() string
+
+	// Where the history is read from, for messages
+	Location() string
+
+	// Whether there's any history to read
+	Detect() bool
+
+	Read() ([]CommandEntry, error)
+}
+
+// Constructors for each shell's history source, keyed by shell. The
+// built-in history files for this OS are registered in init.
+var historySourceRegistry = make(map[string]func(Config, *CommandTotals) HistorySource)
+
+// RegisterHistorySource adds the history source for a shell, replacing
+// any registered before, built-in ones included. newSource is called for
+// every analysis; its source counts each command it reads with
+// totals.Add, which is nil safe, and keeps only those Add accepts.
+func RegisterHistorySource(shell string, newSource func(cfg Config, totals *CommandTotals) HistorySource) {
+	historySourceRegistry[shell] = newSource
+}
+
+func init() {
+	for shell := range defaultHistoryPaths() {
+		RegisterHistorySource(shell, func(cfg Config, totals *CommandTotals) HistorySource {
+			path := resolveHistoryPaths(cfg.HistoryPaths, cfg.home)[shell]
+			return historyFile{shell: shell, path: path, cfg: cfg, totals: totals}
+		})
+	}
+}
+
+// Every history source, sorted by shell. Shells that only $HISTFILE or
+// -history name, without a registered source, are read like bash's.
+// Sources count what they read in totals, which may be nil.
+func historySources(cfg Config, totals *CommandTotals) []HistorySource {
+	sources := make(map[string]HistorySource)
+	for shell, newSource := range historySourceRegistry {
+		sources[shell] = newSource(cfg, totals)
+	}
+	for shell, path := range resolveHistoryPaths(cfg.HistoryPaths, cfg.home) {
+		if _, ok := sources[shell]; !ok {
+			sources[shell] = historyFile{shell: shell, path: path, cfg: cfg, totals: totals}
+		}
+	}
+
+	var sorted []HistorySource
+	for _, source := range sources {
+		sorted = append(sorted, source)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Name() < sorted[j].Name()
+	})
+	return sorted
+}
+
+// historyFile is a shell's plain-text history file, like ~/.zsh_history,
+// or its encrypted copy. bash and zsh files share one line reader that
+// understands both their timestamp formats; fish has its own.
+type historyFile struct {
+	shell  string
+	path   string
+	cfg    Config
+	totals *CommandTotals
+}
+
+func (f historyFile) Name() string {
+	return f.shell
+}
+
+func (f historyFile) Location() string {
+	return resolveHistoryPath(f.cfg.ExpandPath(f.path), f.cfg.DecryptCommands)
+}
+
+func (f historyFile) Detect() bool {
+	_, err := os.Stat(f.Location())
+	return err == nil
+}
+
+func (f historyFile) Read() ([]CommandEntry, error) {
+	read := readHistory
+	if f.shell == "fish" {
+		read = readFishHistory
+	}
+	entries, stats, err := read(f.Location(), f.cfg, f.totals)
+	if f.totals != nil {
+		f.totals.Dropped[f.shell] += stats.Dropped
+		f.totals.PromptNoise[f.shell] += stats.PromptNoise
+	}
+	return entries, err
+}
@@ -0,0 +1,76 @@
+package analyzer
+
+import (
+	"maps"
+	"slices"
+	"testing"
+	"time"
+)
+
+// A history source that isn't a file, counting what it reads like the
+// built-in ones
+type fakeSource struct {
+	commands []string
+	totals   *CommandTotals
+}
+
+func (s fakeSource) Name() string     { return "nu" }
+func (s fakeSource) Location() string { return "fake nu history" }
+func (s fakeSource) Detect() bool     { return true }
+
+func (s fakeSource) Read() ([]CommandEntry, error) {
+	var entries []CommandEntry
+	for i, cmd := range s.commands {
+		entry := CommandEntry{Command: cmd, Timestamp: time.Unix(1712912400+int64(i)*60, 0)}
+		if s.totals.Add(entry) {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+func TestRegisterHistorySource(t *testing.T) {
+	RegisterHistorySource("nu", func(cfg Config, totals *CommandTotals) HistorySource {
+		return fakeSource{commands: []string{"git status", "git push", "docker ps"}, totals: totals}
+	})
+	t.Cleanup(func() { delete(historySourceRegistry, "nu") })
+
+	data, err := Analyze(Options{HomeDir: t.TempDir(), Shells: []string{"nu"}, NoProbe: true, KeepEntries: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, entry := range data.Histories["nu"] {
+		got = append(got, entry.Command)
+	}
+	if want := []string{"git status", "git push", "docker ps"}; !slices.Equal(got, want) {
+		t.Errorf("Histories[nu] = %q, want %q", got, want)
+	}
+	if want := map[string]int{"git": 2, "docker": 1}; !maps.Equal(data.CommonCmds, want) {
+		t.Errorf("CommonCmds = %v, want %v counted from the registered source", data.CommonCmds, want)
+	}
+	if data.CategoryTotals["version-control"] == 0 || data.CategoryTotals["containers"] == 0 {
+		t.Errorf("CategoryTotals = %v, want the source's commands categorized", data.CategoryTotals)
+	}
+	if data.PrimaryShell != "nu" {
+		t.Errorf("PrimaryShell = %q, want nu", data.PrimaryShell)
+	}
+}
+
+func TestBuiltInHistorySourcesRegistered(t *testing.T) {
+	for shell := range defaultHistoryPaths() {
+		if historySourceRegistry[shell] == nil {
+			t.Errorf("no registered history source for %s", shell)
+		}
+	}
+
+	// A shell only -history names is still read
+	home := writeTestHome(t, map[string]string{"ksh_history": "ls\n"})
+	data, err := Analyze(Options{HomeDir: home, HistoryPaths: map[string]string{"ksh": "~/ksh_history"}, Shells: []string{"ksh"}, NoProbe: true, KeepEntries: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data.Histories["ksh"]) != 1 {
+		t.Errorf("Histories[ksh] = %v, want the -history file read", data.Histories["ksh"])
+	}
+}
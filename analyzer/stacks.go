@@ -0,0 +1,32 @@
+package analyzer
+
+// Tools that make up each stack. Stacks in the config file replace the
+// default of the same name and add any new ones.
+func defaultStacks() map[string][]string {
+	return map[string][]string{
+		"frontend": {"npm", "npx", "yarn", "pnpm", "bun", "node", "deno", "webpack", "vite", "next", "ng", "tsc", "eslint", "prettier"},
+		"backend":  {"go", "python", "python3", "pip", "java", "mvn", "gradle", "cargo", "ruby", "rails", "php", "composer", "dotnet", "psql", "mysql", "redis-cli", "mongosh", "sqlite3"},
+		"devops":   {"docker", "docker-compose", "podman", "kubectl", "helm", "k9s", "terraform", "ansible", "ansible-playbook", "aws", "gcloud", "az", "vagrant", "packer"},
+	}
+}
+
+// Count commands per stack. A command counts once for each stack that
+// lists its tool.
+func analyzeStacks(entries []CommandEntry, stacks map[string][]string) map[string]int {
+	toolStacks := make(map[string][]string)
+	for stack, tools := range stacks {
+		for _, tool := range tools {
+			toolStacks[tool] = append(toolStacks[tool], stack)
+		}
+	}
+
+	usage := make(map[string]int)
+	for _, entry := range entries {
+		for _, cmd := range SplitCommand(entry.Command) {
+			for _, stack := range toolStacks[BaseCommand(cmd)] {
+				usage[stack]++
+			}
+		}
+	}
+	return usage
+}
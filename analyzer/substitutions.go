@@ -0,0 +1,170 @@
+package analyzer
+
+import "strings"
+
+type substitutionKind int
+
+const (
+	dollarSubstitution substitutionKind = iota
+	backtickSubstitution
+	subshell
+)
+
+// A $(...), `...` or ( ... ) in a command line, with the command inside
+// and how many others it's nested in
+type substitution struct {
+	Kind  substitutionKind
+	Inner string
+	Depth int
+}
+
+// SubstitutionStats counts command substitutions and subshells, a sign of
+// shell fluency that pipes and redirects alone don't show
+type SubstitutionStats struct {
+	// History entries scanned, and those using at least one
+	Scanned int
+	Using   int
+	Total   int
+
+	Dollar    int
+	Backticks int
+	Subshells int
+	Nested    int
+
+	// The tool run inside each substitution or subshell
+	Tools map[string]int
+}
+
+// The index of the ) closing the ( at open, or -1 when it's never closed.
+// Parens inside quotes or after a backslash don't count.
+func matchParen(cmd string, open int) int {
+	depth := 0
+	inSingle, inDouble := false, false
+	for i := open; i < len(cmd); i++ {
+		c := cmd[i]
+		switch {
+		case c == '\\' && !inSingle:
+			i++
+		case c == '\'' && !inDouble:
+			inSingle = !inSingle
+		case c == '"' && !inSingle:
+			inDouble = !inDouble
+		case inSingle || inDouble:
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// Whether a ( at i starts a command rather than, say, an array or a
+// function definition: nothing but whitespace since the last separator
+func atCommandStart(cmd string, i int) bool {
+	before := strings.TrimRight(cmd[:i], " \t")
+	return before == "" || strings.ContainsAny(before[len(before)-1:], ";&|({\n")
+}
+
+// Every substitution and subshell in cmd, outermost first, including
+// those nested inside others. $((...)) arithmetic isn't a substitution
+// but is searched for nested ones.
+func findSubstitutions(cmd string, depth int) []substitution {
+	var found []substitution
+	add := func(kind substitutionKind, inner string) {
+		found = append(found, substitution{kind, inner, depth})
+		found = append(found, findSubstitutions(inner, depth+1)...)
+	}
+	// Parens in arithmetic group numbers, they aren't subshells
+	arithmetic := func(expression string) {
+		for _, sub := range findSubstitutions(expression, depth) {
+			if sub.Kind != subshell || sub.Depth != depth {
+				found = append(found, sub)
+			}
+		}
+	}
+
+	inSingle, inDouble := false, false
+	for i := 0; i < len(cmd); i++ {
+		c := cmd[i]
+		switch {
+		case c == '\\' && !inSingle:
+			i++
+		case c == '\'' && !inDouble:
+			inSingle = !inSingle
+		case inSingle:
+		case c == '"':
+			inDouble = !inDouble
+		case c == '`':
+			end := strings.IndexByte(cmd[i+1:], '`')
+			if end < 0 {
+				return found
+			}
+			add(backtickSubstitution, cmd[i+1:i+1+end])
+			i += end + 1
+		case c == '$' && strings.HasPrefix(cmd[i+1:], "(("), c == '(' && !inDouble && strings.HasPrefix(cmd[i+1:], "("):
+			// $((...)) and ((...)) arithmetic
+			open := i
+			if c == '$' {
+				open++
+			}
+			end := matchParen(cmd, open)
+			if end < 0 {
+				return found
+			}
+			arithmetic(cmd[open+2 : max(end-1, open+2)])
+			i = end
+		case c == '$' && strings.HasPrefix(cmd[i+1:], "("):
+			end := matchParen(cmd, i+1)
+			if end < 0 {
+				return found
+			}
+			add(dollarSubstitution, cmd[i+2:end])
+			i = end
+		case c == '(' && !inDouble && atCommandStart(cmd, i):
+			end := matchParen(cmd, i)
+			if end < 0 {
+				return found
+			}
+			add(subshell, cmd[i+1:end])
+			i = end
+		}
+	}
+	return found
+}
+
+func analyzeSubstitutions(entries []CommandEntry) SubstitutionStats {
+	stats := SubstitutionStats{Tools: make(map[string]int)}
+
+	for _, entry := range entries {
+		stats.Scanned++
+		found := findSubstitutions(entry.Command, 0)
+		if len(found) == 0 {
+			continue
+		}
+		stats.Using++
+		stats.Total += len(found)
+
+		for _, sub := range found {
+			switch sub.Kind {
+			case dollarSubstitution:
+				stats.Dollar++
+			case backtickSubstitution:
+				stats.Backticks++
+			case subshell:
+				stats.Subshells++
+			}
+			if sub.Depth > 0 {
+				stats.Nested++
+			}
+			if tool := pipelineTool(sub.Inner); tool != "" {
+				stats.Tools[tool]++
+			}
+		}
+	}
+
+	return stats
+}
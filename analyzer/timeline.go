@@ -0,0 +1,195 @@
+package analyzer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// A pause longer than this between commands starts a new session
+const sessionGap = 30 * time.Minute
+
+const (
+	minWorkflowRuns = 3
+	maxWorkflows    = 5
+)
+
+// TimelineEntry is a command in the merged history of all shells
+type TimelineEntry struct {
+	Shell string
+	CommandEntry
+}
+
+// SessionStats summarizes work sessions found in the merged timeline
+type SessionStats struct {
+	Sessions int
+	Commands int
+	Duration time.Duration
+
+	// Sessions that used more than one shell, and how often consecutive
+	// commands within a session came from different shells
+	CrossShell int
+	Switches   int
+}
+
+func (s SessionStats) AverageLength() time.Duration {
+	if s.Sessions == 0 {
+		return 0
+	}
+	return s.Duration / time.Duration(s.Sessions)
+}
+
+// Merge every shell's history into one stream ordered by timestamp.
+// Commands without a timestamp can't be placed and are left out; shells
+// with no timestamps at all are returned in untimed.
+func mergeTimeline(histories map[string][]CommandEntry) (timeline []TimelineEntry, untimed []string) {
+	for shell, history := range histories {
+		timed := 0
+		for _, entry := range history {
+			if entry.Timestamp.IsZero() {
+				continue
+			}
+			timeline = append(timeline, TimelineEntry{Shell: shell, CommandEntry: entry})
+			timed++
+		}
+		if timed == 0 && len(history) > 0 {
+			untimed = append(untimed, shell)
+		}
+	}
+	sort.Strings(untimed)
+
+	// Stable so commands in the same second keep their file order
+	sort.SliceStable(timeline, func(i, j int) bool {
+		return timeline[i].Timestamp.Before(timeline[j].Timestamp)
+	})
+	return timeline, untimed
+}
+
+// Split a timeline into sessions at pauses longer than sessionGap
+func splitSessions(timeline []TimelineEntry) [][]TimelineEntry {
+	var sessions [][]TimelineEntry
+	start := 0
+	for i := 1; i <= len(timeline); i++ {
+		if i == len(timeline) || timeline[i].Timestamp.Sub(timeline[i-1].Timestamp) > sessionGap {
+			sessions = append(sessions, timeline[start:i])
+			start = i
+		}
+	}
+	return sessions
+}
+
+func analyzeSessions(sessions [][]TimelineEntry) SessionStats {
+	var stats SessionStats
+	for _, session := range sessions {
+		if len(session) == 0 {
+			continue
+		}
+		stats.Sessions++
+		stats.Commands += len(session)
+		stats.Duration += session[len(session)-1].Timestamp.Sub(session[0].Timestamp)
+
+		shells := map[string]bool{session[0].Shell: true}
+		for i := 1; i < len(session); i++ {
+			shells[session[i].Shell] = true
+			if session[i].Shell != session[i-1].Shell {
+				stats.Switches++
+			}
+		}
+		if len(shells) > 1 {
+			stats.CrossShell++
+		}
+	}
+	return stats
+}
+
+// Find pairs of commands that often run back to back, like
+// "git add → git commit". Each sequence is one run of commands in order;
+// pairs never span two sequences.
+func findWorkflows(sequences [][]CommandEntry) []string {
+	counts := make(map[string]int)
+	for _, sequence := range sequences {
+		previous := ""
+		for _, entry := range sequence {
+			key := timeSinkKey(entry.Command)
+			if key == "" {
+				continue
+			}
+			if previous != "" && key != previous {
+				counts[previous+" → "+key]++
+			}
+			previous = key
+		}
+	}
+
+	var workflows []string
+	for workflow, count := range counts {
+		if count >= minWorkflowRuns {
+			workflows = append(workflows, workflow)
+		}
+	}
+	sort.Slice(workflows, func(i, j int) bool {
+		if counts[workflows[i]] != counts[workflows[j]] {
+			return counts[workflows[i]] > counts[workflows[j]]
+		}
+		return workflows[i] < workflows[j]
+	})
+	if len(workflows) > maxWorkflows {
+		workflows = workflows[:maxWorkflows]
+	}
+
+	for i, workflow := range workflows {
+		workflows[i] = fmt.Sprintf("%s (%d×)", workflow, counts[workflow])
+	}
+	return workflows
+}
+
+// Session and workflow analysis over all shells at once. Falls back to
+// analyzing each shell's history on its own, with a warning, when a shell
+// has no timestamps to place its commands by.
+func analyzeTimeline(histories map[string][]CommandEntry, data *ShellData) {
+	patterns := &data.Insights.WorkPatterns
+
+	timeline, untimed := mergeTimeline(histories)
+	if len(untimed) > 0 {
+		data.Errors = append(data.Errors, fmt.Errorf("%w for %s, so -timeline can't merge shells; analyzing each shell separately",
+			ErrNoTimestamps, strings.Join(untimed, ", ")))
+		patterns.CommonWorkflows = findWorkflows(shellSequences(histories))
+		return
+	}
+
+	sessions := splitSessions(timeline)
+	patterns.Sessions = analyzeSessions(sessions)
+
+	var sequences [][]CommandEntry
+	for _, session := range sessions {
+		var sequence []CommandEntry
+		for _, entry := range session {
+			sequence = append(sequence, entry.CommandEntry)
+		}
+		sequences = append(sequences, sequence)
+	}
+	patterns.CommonWorkflows = findWorkflows(sequences)
+}
+
+// Each shell's history as its own sequence, in a fixed order
+func shellSequences(histories map[string][]CommandEntry) [][]CommandEntry {
+	// Nothing to order with a single shell
+	if len(histories) == 1 {
+		for _, history := range histories {
+			return [][]CommandEntry{history}
+		}
+	}
+
+	var shells []string
+	for shell := range histories {
+		shells = append(shells, shell)
+	}
+	sort.Strings(shells)
+
+	var sequences [][]CommandEntry
+	for _, shell := range shells {
+		sequences = append(sequences, histories[shell])
+	}
+	return sequences
+}
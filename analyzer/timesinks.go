@@ -0,0 +1,122 @@
+package analyzer
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	maxTimeSinks      = 10
+	minTimeSinkRuns   = 2
+	minTimeSinkAvg    = 5 * time.Second
+	longRunningAvg    = time.Minute
+	maxTimeSinkKeyLen = 2
+)
+
+// TimeSink is a command that adds up to a lot of waiting
+type TimeSink struct {
+	Command string
+	Count   int
+	Total   time.Duration
+	Average time.Duration
+	Tip     string
+}
+
+// Tips for well-known slow commands, matched by prefix on the command key
+var timeSinkTips = []struct {
+	prefix string
+	tip    string
+}{
+	{"npm install", "consider pnpm, whose shared store makes reinstalls much faster"},
+	{"npm ci", "consider pnpm, whose shared store makes reinstalls much faster"},
+	{"yarn install", "consider pnpm, whose shared store makes reinstalls much faster"},
+	{"pip install", "consider uv, a much faster drop-in for pip"},
+	{"docker build", "order Dockerfile layers so dependencies cache, and use BuildKit cache mounts"},
+	{"cargo build", "try sccache for cached builds or the mold linker"},
+	{"go test", "narrow runs with -run, and avoid -count=1 so results are cached"},
+	{"make", "parallelize with make -j$(nproc)"},
+	{"gradle", "enable the Gradle build cache and configuration cache"},
+	{"./gradlew", "enable the Gradle build cache and configuration cache"},
+	{"mvn", "try mvnd, which keeps a warm build daemon"},
+	{"git clone", "use --depth 1 when you don't need the full history"},
+	{"terraform plan", "use -target for focused changes or split large state"},
+	{"terraform apply", "use -target for focused changes or split large state"},
+}
+
+// Key a command by its tool and subcommand, e.g. "npm install"
+func timeSinkKey(cmd string) string {
+	fields := strings.Fields(cmd)
+	var key []string
+	for _, field := range fields {
+		if len(key) == maxTimeSinkKeyLen || strings.HasPrefix(field, "-") ||
+			strings.ContainsAny(field, "/.=$\"'") && len(key) > 0 {
+			break
+		}
+		key = append(key, field)
+	}
+	return strings.Join(key, " ")
+}
+
+func timeSinkTip(key string, average time.Duration) string {
+	for _, tip := range timeSinkTips {
+		if strings.HasPrefix(key, tip.prefix) {
+			return tip.tip
+		}
+	}
+	if average >= longRunningAvg {
+		return "runs for over a minute on average; consider backgrounding it or running it in tmux"
+	}
+	return ""
+}
+
+// Rank commands by total time spent in them. Only entries with a recorded
+// duration count, so this is empty unless the history stores durations
+// (zsh with EXTENDED_HISTORY).
+func analyzeTimeSinks(entries []CommandEntry) []TimeSink {
+	sinks := make(map[string]*TimeSink)
+
+	for _, entry := range entries {
+		if entry.Duration <= 0 {
+			continue
+		}
+		// A compound line's duration is charged to its first command
+		subs := SplitCommand(entry.Command)
+		if len(subs) == 0 {
+			continue
+		}
+		key := timeSinkKey(subs[0])
+		if key == "" {
+			continue
+		}
+
+		sink, ok := sinks[key]
+		if !ok {
+			sink = &TimeSink{Command: key}
+			sinks[key] = sink
+		}
+		sink.Count++
+		sink.Total += entry.Duration
+	}
+
+	var ranked []TimeSink
+	for _, sink := range sinks {
+		sink.Average = sink.Total / time.Duration(sink.Count)
+		if sink.Count < minTimeSinkRuns || sink.Average < minTimeSinkAvg {
+			continue
+		}
+		sink.Tip = timeSinkTip(sink.Command, sink.Average)
+		ranked = append(ranked, *sink)
+	}
+
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].Total != ranked[j].Total {
+			return ranked[i].Total > ranked[j].Total
+		}
+		return ranked[i].Command < ranked[j].Command
+	})
+	if len(ranked) > maxTimeSinks {
+		ranked = ranked[:maxTimeSinks]
+	}
+	return ranked
+}
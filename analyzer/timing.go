@@ -0,0 +1,92 @@
+package analyzer
+
+import (
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// Commands starting within this gap after the previous one finished are
+// treated as pasted or scripted rather than typed
+const BurstThreshold = time.Second
+
+var (
+	// zsh EXTENDED_HISTORY: ": <start>:<elapsed seconds>;<command>"
+	zshExtendedRegex = regexp.MustCompile(`^: (\d+):(\d+);(.*)$`)
+	// bash with HISTTIMEFORMAT set: "#<epoch>" on the line before the
+	// command. Also accepts a space after the "#", trailing text after the
+	// epoch, and epochs in milliseconds.
+	bashTimestampRegex = regexp.MustCompile(`^#\s?(\d{9,13})(\s.*)?$`)
+)
+
+// InteractivityStats estimates how many commands were typed by hand
+type InteractivityStats struct {
+	Interactive int
+	Scripted    int
+}
+
+func (s InteractivityStats) Timed() int {
+	return s.Interactive + s.Scripted
+}
+
+func parseZshExtended(line string) (time.Time, time.Duration, string, bool) {
+	match := zshExtendedRegex.FindStringSubmatch(line)
+	if match == nil {
+		return time.Time{}, 0, line, false
+	}
+
+	start, err := strconv.ParseInt(match[1], 10, 64)
+	if err != nil {
+		return time.Time{}, 0, line, false
+	}
+	elapsed, _ := strconv.ParseInt(match[2], 10, 64)
+
+	return time.Unix(start, 0), time.Duration(elapsed) * time.Second, match[3], true
+}
+
+func parseBashTimestamp(line string) (time.Time, bool) {
+	match := bashTimestampRegex.FindStringSubmatch(line)
+	if match == nil {
+		return time.Time{}, false
+	}
+
+	epoch, err := strconv.ParseInt(match[1], 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	switch len(match[1]) {
+	case 9, 10:
+		return time.Unix(epoch, 0), true
+	case 13:
+		return time.UnixMilli(epoch), true
+	}
+	return time.Time{}, false
+}
+
+// Classify commands as interactive or scripted from inter-command timing.
+//
+// A command counts as scripted when it starts within BurstThreshold of the
+// previous command finishing (its start plus its duration, when known).
+// Bursts like this come from pasting several lines or running a script,
+// since nobody types a new command that fast. The first command of a burst
+// is still counted as interactive. Entries without timestamps are skipped.
+func analyzeInteractivity(entries []CommandEntry) InteractivityStats {
+	var stats InteractivityStats
+	var previousEnd time.Time
+
+	for _, entry := range entries {
+		if entry.Timestamp.IsZero() {
+			previousEnd = time.Time{}
+			continue
+		}
+
+		if !previousEnd.IsZero() && entry.Timestamp.Sub(previousEnd) < BurstThreshold {
+			stats.Scripted++
+		} else {
+			stats.Interactive++
+		}
+		previousEnd = entry.Timestamp.Add(entry.Duration)
+	}
+
+	return stats
+}
@@ -0,0 +1,83 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestParseBashTimestamp(t *testing.T) {
+	tests := []struct {
+		line string
+		want time.Time
+		ok   bool
+	}{
+		{"#1712912400", time.Unix(1712912400, 0), true},
+		{"# 1712912400", time.Unix(1712912400, 0), true},
+		{"#1712912400 host1", time.Unix(1712912400, 0), true},
+		{"#1712912400000", time.UnixMilli(1712912400000), true},
+		{"#999999999", time.Unix(999999999, 0), true},
+
+		// Fall back to not a timestamp
+		{"#12345", time.Time{}, false},
+		{"#17129124001", time.Time{}, false},
+		{"#1712912400x", time.Time{}, false},
+		{"# TODO: 1712912400", time.Time{}, false},
+		{"1712912400", time.Time{}, false},
+		{"#", time.Time{}, false},
+	}
+	for _, test := range tests {
+		got, ok := parseBashTimestamp(test.line)
+		if ok != test.ok || !got.Equal(test.want) {
+			t.Errorf("parseBashTimestamp(%q) = %v, %v; want %v, %v", test.line, got, ok, test.want, test.ok)
+		}
+	}
+}
+
+func TestParseZshExtended(t *testing.T) {
+	ts, elapsed, cmd, ok := parseZshExtended(": 1712912400:3;git status")
+	if !ok || !ts.Equal(time.Unix(1712912400, 0)) || elapsed != 3*time.Second || cmd != "git status" {
+		t.Errorf("parseZshExtended = %v, %v, %q, %v", ts, elapsed, cmd, ok)
+	}
+	if _, _, cmd, ok := parseZshExtended("git status"); ok || cmd != "git status" {
+		t.Errorf("plain line: %q, %v; want it unchanged and not ok", cmd, ok)
+	}
+}
+
+func TestReadHistoryMixedTimestamps(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".bash_history")
+	content := "#1712912400\n" +
+		"git status\n" +
+		"ls\n" +
+		"# 1712912460 laptop\n" +
+		"make test\n" +
+		"# a comment that isn't a timestamp\n" +
+		"#1712912520000\n" +
+		"git push\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, _, err := readHistory(path, DefaultConfig(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []struct {
+		cmd string
+		ts  time.Time
+	}{
+		{"git status", time.Unix(1712912400, 0)},
+		{"ls", time.Time{}},
+		{"make test", time.Unix(1712912460, 0)},
+		{"git push", time.UnixMilli(1712912520000)},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("read %d entries, want %d: %+v", len(entries), len(want), entries)
+	}
+	for i, w := range want {
+		if entries[i].Command != w.cmd || !entries[i].Timestamp.Equal(w.ts) {
+			t.Errorf("entry %d = %q at %v, want %q at %v", i, entries[i].Command, entries[i].Timestamp, w.cmd, w.ts)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package analyzer
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Tools recognized by the dedicated tool analysis
+var devTools = []string{"git", "docker", "kubectl", "terraform", "ansible", "make"}
+
+// Build tools, by command
+var buildTools = map[string]bool{
+	"make": true, "cmake": true, "ninja": true, "meson": true, "bazel": true,
+	"gradle": true, "gradlew": true, "mvn": true, "ant": true, "sbt": true,
+	"cargo": true, "msbuild": true, "webpack": true, "vite": true,
+}
+
+// Toolchains whose build subcommand counts as a build tool, like "go build"
+var buildSubcommands = map[string]string{
+	"go":     "build",
+	"dotnet": "build",
+	"swift":  "build",
+	"docker": "build",
+}
+
+// Name the build tool a command runs, or "" when it isn't a build
+func detectBuildTool(cmd string) string {
+	fields, _ := unwrapCommand(cmd)
+	if len(fields) == 0 {
+		return ""
+	}
+	// ./gradlew and friends count under their own name
+	name := filepath.Base(fields[0])
+	if buildTools[name] {
+		return name
+	}
+	if sub, ok := buildSubcommands[name]; ok && len(fields) > 1 && fields[1] == sub {
+		return name + " " + sub
+	}
+	return ""
+}
+
+// Minimum uses before an unrecognized command is looked up on PATH
+const minOtherToolUses = 3
+
+// Builtins that may also exist as binaries but aren't interesting as tools
+var ShellBuiltins = map[string]bool{
+	"cd": true, "echo": true, "export": true, "source": true, ".": true,
+	"alias": true, "unalias": true, "history": true, "exit": true, "pwd": true,
+	"set": true, "unset": true, "type": true, "true": true, "false": true,
+	"test": true, "[": true, "exec": true, "eval": true, "clear": true,
+}
+
+var lookPathCache = struct {
+	sync.Mutex
+	found map[string]bool
+}{found: make(map[string]bool)}
+
+// Only searches PATH and never runs the tool, so it can't hang
+func CheckToolInstalled(tool string) bool {
+	lookPathCache.Lock()
+	defer lookPathCache.Unlock()
+
+	if found, ok := lookPathCache.found[tool]; ok {
+		return found
+	}
+	_, err := exec.LookPath(tool)
+	lookPathCache.found[tool] = err == nil
+	return err == nil
+}
+
+// Find frequently used commands that the fixed tool lists don't know about
+// but that are real binaries on PATH, from how often each base command ran
+func detectOtherTools(commands map[string]int, known map[string]bool) map[string]int {
+	others := make(map[string]int)
+	for name, count := range commands {
+		// sudo and env alone, like sudo -i, aren't tools either
+		if name == "" || known[name] || ShellBuiltins[name] || privilegeWrappers[name] || name == "env" ||
+			strings.ContainsAny(name, "/=$") {
+			continue
+		}
+		if count >= minOtherToolUses && CheckToolInstalled(name) {
+			others[name] = count
+		}
+	}
+	return others
+}
@@ -0,0 +1,78 @@
+package analyzer
+
+import (
+	"maps"
+	"testing"
+)
+
+func TestDetectBuildTool(t *testing.T) {
+	tests := map[string]string{
+		"make install":               "make",
+		"sudo make install":          "make",
+		"./gradlew build":            "gradlew",
+		"env CGO_ENABLED=0 go build": "go build",
+		"sudo docker build .":        "docker build",
+		"go test ./...":              "",
+		"sudo -i":                    "",
+	}
+	for cmd, want := range tests {
+		if got := detectBuildTool(cmd); got != want {
+			t.Errorf("detectBuildTool(%q) = %q, want %q", cmd, got, want)
+		}
+	}
+}
+
+func TestWrappedCommandsCountAsTheirTool(t *testing.T) {
+	fakeTools(t, "docker", "htop", "sudo", "env")
+
+	var entries []CommandEntry
+	for _, cmd := range []string{
+		"sudo docker ps",
+		"sudo docker ps -a",
+		"docker images",
+		"sudo htop",
+		"sudo htop",
+		"env TERM=xterm htop",
+		"sudo vim /etc/hosts",
+		"sudo python3 setup.py install",
+		"sudo make install",
+		"sudo ssh host",
+		"sudo -i",
+		"sudo -i",
+		"sudo -i",
+		"env",
+		"env",
+		"env",
+	} {
+		entries = append(entries, CommandEntry{Command: cmd})
+	}
+
+	cfg := DefaultConfig()
+	cfg.TrackedTools = []string{"docker"}
+	data := NewShellData()
+	data.Histories["bash"] = entries
+	analyzeCommands(entries, &data, cfg, map[string]string{"python3": "3.12"})
+	usage := data.Insights.ToolUsage
+
+	if got := data.Insights.TechnicalProfile.Proficiency["docker"]; got != 3/float64(len(entries)) {
+		t.Errorf("docker proficiency = %v, want 3 of %d commands", got, len(entries))
+	}
+	if len(usage.Tracked) != 1 || usage.Tracked[0].Count != 3 {
+		t.Errorf("Tracked = %+v, want docker 3 times", usage.Tracked)
+	}
+	if want := map[string]int{"htop": 3}; !maps.Equal(usage.Other, want) {
+		t.Errorf("Other = %v, want %v without sudo or env", usage.Other, want)
+	}
+	if usage.Editors["vim"] != 1 || usage.EditorFileTypes["vim"]["no extension"] != 1 {
+		t.Errorf("Editors = %v, file types = %v, want sudo vim counted", usage.Editors, usage.EditorFileTypes)
+	}
+	if usage.Languages["python3"] != 1 {
+		t.Errorf("Languages = %v, want sudo python3 counted", usage.Languages)
+	}
+	if usage.BuildTools["make"] != 1 {
+		t.Errorf("BuildTools = %v, want sudo make counted", usage.BuildTools)
+	}
+	if got := data.Insights.Security.PrivilegedCommands; got != 11 {
+		t.Errorf("PrivilegedCommands = %d, want 11", got)
+	}
+}
@@ -0,0 +1,150 @@
+package analyzer
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+// CommandTotals counts every command read as the history streams past,
+// including the older ones past max_entries and all of them when entries
+// aren't kept at all
+type CommandTotals struct {
+	// Commands dropped and prompt noise lines skipped, per shell
+	Dropped     map[string]int
+	PromptNoise map[string]int
+
+	Tools      map[string]int
+	Hours      map[int]int
+	Categories map[string]float64
+
+	// Screens each command before it's counted, like the passwords of a
+	// passwordScreen. nil counts every command.
+	screen func(CommandEntry) bool
+
+	// Expands a shell's aliases before each command is counted, so the
+	// totals count what ran rather than what was typed. nil counts
+	// commands as they are.
+	expand func(CommandEntry) CommandEntry
+
+	// Works out the insights drawn from each command counted, like those
+	// of commandStats. nil only counts.
+	observe func(CommandEntry)
+
+	// Categorizes commands that come without categories, from sources
+	// that don't categorize them
+	categories Categorizer
+}
+
+func newCommandTotals() *CommandTotals {
+	return &CommandTotals{
+		Dropped:     make(map[string]int),
+		PromptNoise: make(map[string]int),
+		Tools:       make(map[string]int),
+		Hours:       make(map[int]int),
+		Categories:  make(map[string]float64),
+	}
+}
+
+// Add counts an entry, unless the screen turns it away. History sources
+// only keep the entries that were counted. A nil CommandTotals counts
+// nothing and keeps everything.
+func (t *CommandTotals) Add(entry CommandEntry) bool {
+	if t == nil {
+		return true
+	}
+	if t.screen != nil && !t.screen(entry) {
+		return false
+	}
+	if t.expand != nil {
+		entry = t.expand(entry)
+	}
+	if t.observe != nil {
+		t.observe(entry)
+	}
+	if base := BaseCommand(entry.Command); base != "" {
+		t.Tools[filepath.Base(base)]++
+	}
+	if !entry.Timestamp.IsZero() {
+		t.Hours[entry.Timestamp.Hour()]++
+	}
+	for _, match := range t.categoriesOf(entry) {
+		t.Categories[match.Name] += match.Weight
+	}
+	return true
+}
+
+// An entry's categories, worked out when its source left them out
+func (t *CommandTotals) categoriesOf(entry CommandEntry) []CategoryMatch {
+	if entry.Categories == nil && t.categories != nil {
+		return t.categories.commandCategories(entry.Command)
+	}
+	return entry.Categories
+}
+
+// Uncount an entry that turned out not to be a command
+func (t *CommandTotals) remove(entry CommandEntry) {
+	if t == nil {
+		return
+	}
+	if t.expand != nil {
+		entry = t.expand(entry)
+	}
+	if base := BaseCommand(entry.Command); base != "" {
+		tool := filepath.Base(base)
+		if t.Tools[tool]--; t.Tools[tool] <= 0 {
+			delete(t.Tools, tool)
+		}
+	}
+	if !entry.Timestamp.IsZero() {
+		hour := entry.Timestamp.Hour()
+		if t.Hours[hour]--; t.Hours[hour] <= 0 {
+			delete(t.Hours, hour)
+		}
+	}
+	for _, match := range t.categoriesOf(entry) {
+		if t.Categories[match.Name] -= match.Weight; t.Categories[match.Name] <= 0 {
+			delete(t.Categories, match.Name)
+		}
+	}
+}
+
+// Hours keyed like "09" for ShellData.TimePatterns
+func (t *CommandTotals) timePatterns() map[string]int {
+	patterns := make(map[string]int)
+	for hour, count := range t.Hours {
+		patterns[fmt.Sprintf("%02d", hour)] = count
+	}
+	return patterns
+}
+
+// A window over the newest limit entries of a history. Older entries are
+// overwritten in place, so memory stays bounded however long the file.
+// With countOnly set nothing is kept, for when only the totals are needed.
+type entryWindow struct {
+	limit     int
+	countOnly bool
+	entries   []CommandEntry
+	next      int
+	dropped   int
+}
+
+func (w *entryWindow) add(entry CommandEntry) {
+	if w.countOnly {
+		return
+	}
+	if w.limit <= 0 || len(w.entries) < w.limit {
+		w.entries = append(w.entries, entry)
+		return
+	}
+	w.entries[w.next] = entry
+	w.next = (w.next + 1) % w.limit
+	w.dropped++
+}
+
+// The kept entries, oldest first
+func (w *entryWindow) ordered() []CommandEntry {
+	if w.next == 0 {
+		return w.entries
+	}
+	return append(w.entries[w.next:len(w.entries):len(w.entries)], w.entries[:w.next]...)
+}
@@ -0,0 +1,145 @@
+package analyzer
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCommandTotalsCountsTools(t *testing.T) {
+	content := "git status\n" +
+		"git commit -m \"fix: a; b\"\n" +
+		"/usr/bin/git log\n" +
+		"ls -la\n" +
+		"  make test  \n" +
+		"ls\n" +
+		"git push\n"
+	path := writeTestHistory(t, ".bash_history", content)
+
+	totals := newCommandTotals()
+	if _, _, err := readHistory(path, DefaultConfig(), totals); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]int{"git": 4, "ls": 2, "make": 1}
+	if !maps.Equal(totals.Tools, want) {
+		t.Errorf("Tools = %v, want %v", totals.Tools, want)
+	}
+}
+
+func TestCommandTotalsRemove(t *testing.T) {
+	totals := newCommandTotals()
+	at := time.Date(2024, 4, 12, 9, 30, 0, 0, time.Local)
+	entry := CommandEntry{Command: "git status", Timestamp: at,
+		Categories: []CategoryMatch{{Name: "development", Weight: 0.5}}}
+	totals.Add(entry)
+	totals.Add(entry)
+	totals.remove(entry)
+	if totals.Tools["git"] != 1 || totals.Hours[9] != 1 || totals.Categories["development"] != 0.5 {
+		t.Errorf("after removing one of two: Tools = %v, Hours = %v, Categories = %v", totals.Tools, totals.Hours, totals.Categories)
+	}
+	totals.remove(entry)
+	if len(totals.Tools) != 0 || len(totals.Hours) != 0 || len(totals.Categories) != 0 {
+		t.Errorf("after removing both: Tools = %v, Hours = %v, Categories = %v, want all empty", totals.Tools, totals.Hours, totals.Categories)
+	}
+
+	// A nil CommandTotals counts nothing, for callers that don't need totals
+	var none *CommandTotals
+	none.Add(entry)
+	none.remove(entry)
+}
+
+func TestReadHistoryCountOnly(t *testing.T) {
+	tests := []struct {
+		name    string
+		read    func(string, Config, *CommandTotals) ([]CommandEntry, historyReadStats, error)
+		content string
+	}{
+		{".zsh_history", readHistory, ": 1712912400:0;git status\n: 1712912460:0;ssh host\n: 1712916000:0;git push\n"},
+		{"fish_history", readFishHistory, "- cmd: git status\n  when: 1712912400\n- cmd: ssh host\n  when: 1712912460\n" +
+			"- cmd: git push\n  when: 1712916000\n"},
+	}
+	cfg := DefaultConfig()
+	cfg.countOnly = true
+	for _, test := range tests {
+		totals := newCommandTotals()
+		entries, stats, err := test.read(writeTestHistory(t, test.name, test.content), cfg, totals)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if len(entries) != 0 || stats.Dropped != 0 {
+			t.Errorf("%s: kept %d entries and dropped %d, want none of either", test.name, len(entries), stats.Dropped)
+		}
+		if want := map[string]int{"git": 2, "ssh": 1}; !maps.Equal(totals.Tools, want) {
+			t.Errorf("%s: Tools = %v, want %v", test.name, totals.Tools, want)
+		}
+		hours := 0
+		for _, count := range totals.Hours {
+			hours += count
+		}
+		if hours != 3 {
+			t.Errorf("%s: Hours = %v, want 3 commands", test.name, totals.Hours)
+		}
+		if totals.Categories["version-control"] == 0 || totals.Categories["network"] == 0 {
+			t.Errorf("%s: Categories = %v, want version-control and network", test.name, totals.Categories)
+		}
+	}
+}
+
+func TestCommandTotalsScreensPasswords(t *testing.T) {
+	content := "sudo apt update\nHunter2!pass\nls -la\n"
+	screen := newPasswordScreen("bash", nil)
+	totals := newCommandTotals()
+	totals.screen = screen.keep
+
+	entries, _, err := readHistory(writeTestHistory(t, ".bash_history", content), DefaultConfig(), totals)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || len(screen.orphans) != 1 || screen.orphans[0].After != "sudo apt update" {
+		t.Errorf("entries = %v, orphans = %+v; want the password after sudo taken out", entries, screen.orphans)
+	}
+	if want := map[string]int{"apt": 1, "ls": 1}; !maps.Equal(totals.Tools, want) {
+		t.Errorf("Tools = %v, want %v without the password", totals.Tools, want)
+	}
+}
+
+// A zsh history of n commands, a minute apart
+func generateHistory(n int) string {
+	commands := []string{"git status", "ls -la", "cd ~/src/api && make test", "docker ps",
+		"kubectl get pods -n kube-system", "vim main.go", "go test ./...", "ssh deploy@prod"}
+	var content strings.Builder
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).Unix()
+	for i := range n {
+		fmt.Fprintf(&content, ": %d:0;%s %d\n", start+int64(i)*60, commands[i%len(commands)], i%97)
+	}
+	return content.String()
+}
+
+// Reads a 500k-line history, streamed into totals alone and with every
+// entry kept
+func BenchmarkReadHistory(b *testing.B) {
+	path := filepath.Join(b.TempDir(), ".zsh_history")
+	if err := os.WriteFile(path, []byte(generateHistory(500_000)), 0644); err != nil {
+		b.Fatal(err)
+	}
+	for _, countOnly := range []bool{true, false} {
+		name := "keep entries"
+		if countOnly {
+			name = "count only"
+		}
+		b.Run(name, func(b *testing.B) {
+			cfg := DefaultConfig()
+			cfg.countOnly = countOnly
+			b.ReportAllocs()
+			for range b.N {
+				if _, _, err := readHistory(path, cfg, newCommandTotals()); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
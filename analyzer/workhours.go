@@ -0,0 +1,124 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// WorkHours is when the user means to be working. End may be earlier
+// than Start for overnight shifts like 22:00-06:00, which then belong to
+// the day they start on.
+type WorkHours struct {
+	Start string   `json:"start"`
+	End   string   `json:"end"`
+	Days  []string `json:"days"`
+
+	// IANA name like "Europe/Berlin"; the local timezone when empty
+	Timezone string `json:"timezone"`
+
+	start, end int
+	days       map[time.Weekday]bool
+	location   *time.Location
+}
+
+// WorkHoursStats counts timestamped commands inside and outside work hours
+type WorkHoursStats struct {
+	Definition string
+	InHours    int
+	OffHours   int
+}
+
+func (s *WorkHoursStats) add(t time.Time, hours WorkHours) {
+	if hours.contains(t) {
+		s.InHours++
+	} else {
+		s.OffHours++
+	}
+}
+
+func (s WorkHoursStats) Total() int {
+	return s.InHours + s.OffHours
+}
+
+var weekdayNames = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// 09:00-18:00 on weekdays in the local timezone
+func defaultWorkHours() WorkHours {
+	hours := WorkHours{
+		Start: "09:00",
+		End:   "18:00",
+		Days:  []string{"mon", "tue", "wed", "thu", "fri"},
+	}
+	hours.compile()
+	return hours
+}
+
+// Minutes since midnight for a time like "09:30"
+func parseClock(clock string) (int, error) {
+	t, err := time.Parse("15:04", clock)
+	if err != nil {
+		return 0, fmt.Errorf("work_hours: %q is not a time like 09:00", clock)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// Parse the settings, which must happen before contains is used
+func (w *WorkHours) compile() error {
+	var err error
+	if w.start, err = parseClock(w.Start); err != nil {
+		return err
+	}
+	if w.end, err = parseClock(w.End); err != nil {
+		return err
+	}
+
+	w.days = make(map[time.Weekday]bool)
+	for _, name := range w.Days {
+		day, ok := weekdayNames[strings.ToLower(name[:min(3, len(name))])]
+		if !ok {
+			return fmt.Errorf("work_hours: unknown day %q", name)
+		}
+		w.days[day] = true
+	}
+
+	w.location = time.Local
+	if w.Timezone != "" {
+		if w.location, err = time.LoadLocation(w.Timezone); err != nil {
+			return fmt.Errorf("work_hours: %w", err)
+		}
+	}
+	return nil
+}
+
+func (w WorkHours) contains(t time.Time) bool {
+	t = t.In(w.location)
+	minute := t.Hour()*60 + t.Minute()
+	day := t.Weekday()
+
+	if w.start <= w.end {
+		return w.days[day] && minute >= w.start && minute < w.end
+	}
+	// Overnight: the evening part is on a work day, the morning part on
+	// the day after one
+	if minute >= w.start {
+		return w.days[day]
+	}
+	return minute < w.end && w.days[(day+6)%7]
+}
+
+// Like "09:00–18:00 Mon Tue Wed Thu Fri (Europe/Berlin)"
+func (w WorkHours) String() string {
+	description := fmt.Sprintf("%s–%s", w.Start, w.End)
+	for _, name := range w.Days {
+		name = strings.ToLower(name)
+		description += " " + strings.ToUpper(name[:1]) + name[1:min(3, len(name))]
+	}
+	if w.Timezone != "" {
+		description += fmt.Sprintf(" (%s)", w.Timezone)
+	}
+	return description
+}
@@ -0,0 +1,180 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Where a command does its work
+const (
+	workLocal     = "local"
+	workContainer = "container"
+	workRemote    = "remote"
+)
+
+// WorkplaceStats counts commands by whether they work on this machine,
+// inside a container, or on a remote host. Commands typed inside an
+// interactive container or ssh session aren't in the local history, so
+// this counts the ways in rather than the time spent there.
+type WorkplaceStats struct {
+	Local     int
+	Container int
+	Remote    int
+
+	// Containers, pods and hosts most often worked in
+	Containers map[string]int
+	Hosts      map[string]int
+}
+
+func (s WorkplaceStats) Total() int {
+	return s.Local + s.Container + s.Remote
+}
+
+// Flags that take a value, so the value isn't mistaken for the target
+var (
+	sshValueFlags       = "bcDEeFIiJLlmOopQRSWw"
+	containerValueFlags = map[string]bool{
+		"-e": true, "--env": true, "--env-file": true, "-u": true, "--user": true,
+		"-w": true, "--workdir": true, "--name": true, "-v": true, "--volume": true,
+		"-p": true, "--publish": true, "--network": true, "--entrypoint": true,
+		"-n": true, "--namespace": true, "-c": true, "--container": true, "--context": true,
+		"--image": true, "--target": true,
+	}
+)
+
+var (
+	// vim's netrw URLs like scp://host//path
+	remoteURLRegex = regexp.MustCompile(`^(scp|sftp|rsync|ftp|dav|fetch)://([^/]+)`)
+	// TRAMP paths like /ssh:host:/path or /docker:container:/path
+	trampRegex = regexp.MustCompile(`^/(ssh|scp|rsync|sshx|plink|docker|podman|kubernetes):([^:|]+)`)
+)
+
+// The first argument that isn't a flag or a flag's value
+func firstArgument(args []string, takesValue func(string) bool) string {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "-") {
+			return arg
+		}
+		if takesValue(arg) && !strings.Contains(arg, "=") {
+			i++
+		}
+	}
+	return ""
+}
+
+func containerFlagTakesValue(flag string) bool {
+	return containerValueFlags[flag]
+}
+
+func sshFlagTakesValue(flag string) bool {
+	// A value can follow in the same argument, like -p2222
+	return len(flag) == 2 && strings.ContainsRune(sshValueFlags, rune(flag[1]))
+}
+
+// Strip user@ from an ssh target
+func sshHost(target string) string {
+	if i := strings.LastIndex(target, "@"); i >= 0 {
+		return target[i+1:]
+	}
+	return target
+}
+
+// Where a simple command works, and the container or host it works in
+func classifyWorkplace(cmd string) (string, string) {
+	fields, _ := unwrapCommand(cmd)
+	if len(fields) == 0 {
+		return workLocal, ""
+	}
+	tool, args := filepath.Base(fields[0]), fields[1:]
+
+	switch tool {
+	case "ssh", "mosh", "autossh", "et":
+		if target := firstArgument(args, sshFlagTakesValue); target != "" {
+			return workRemote, sshHost(target)
+		}
+	case "docker", "podman", "nerdctl":
+		// docker container exec and docker compose exec work the same way
+		if len(args) > 0 && (args[0] == "container" || args[0] == "compose") {
+			args = args[1:]
+		}
+		if len(args) == 0 {
+			break
+		}
+		switch args[0] {
+		case "exec", "attach":
+			return workContainer, firstArgument(args[1:], containerFlagTakesValue)
+		case "run":
+			// Only interactive runs are work inside the container
+			for _, arg := range args[1:] {
+				if arg == "-it" || arg == "-ti" || arg == "--interactive" || arg == "-i" {
+					return workContainer, firstArgument(args[1:], containerFlagTakesValue)
+				}
+			}
+		}
+	case "kubectl", "oc":
+		if len(args) > 0 && (args[0] == "exec" || args[0] == "attach" || args[0] == "debug") {
+			return workContainer, strings.TrimPrefix(firstArgument(args[1:], containerFlagTakesValue), "pod/")
+		}
+	}
+
+	// Editors opened on remote files, through vim's netrw or Emacs TRAMP
+	if _, ok := editorCommands[tool]; ok {
+		for i, arg := range args {
+			if match := remoteURLRegex.FindStringSubmatch(arg); match != nil {
+				return workRemote, sshHost(match[2])
+			}
+			if match := trampRegex.FindStringSubmatch(arg); match != nil {
+				switch match[1] {
+				case "docker", "podman", "kubernetes":
+					return workContainer, match[2]
+				}
+				return workRemote, sshHost(match[2])
+			}
+			// VS Code remote windows: code --remote ssh-remote+host
+			if arg == "--remote" && i+1 < len(args) {
+				if host, ok := strings.CutPrefix(args[i+1], "ssh-remote+"); ok {
+					return workRemote, host
+				}
+			}
+		}
+	}
+
+	return workLocal, ""
+}
+
+// Classify every history entry by the first of its chained commands that
+// leaves this machine
+func analyzeWorkplaces(entries []CommandEntry) WorkplaceStats {
+	stats := WorkplaceStats{
+		Containers: make(map[string]int),
+		Hosts:      make(map[string]int),
+	}
+
+	for _, entry := range entries {
+		where, target := workLocal, ""
+		for _, cmd := range SplitCommand(entry.Command) {
+			if where, target = classifyWorkplace(cmd); where != workLocal {
+				break
+			}
+		}
+
+		switch where {
+		case workContainer:
+			stats.Container++
+			if target != "" {
+				stats.Containers[target]++
+			}
+		case workRemote:
+			stats.Remote++
+			if target != "" {
+				stats.Hosts[target]++
+			}
+		default:
+			stats.Local++
+		}
+	}
+
+	return stats
+}
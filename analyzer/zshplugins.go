@@ -0,0 +1,134 @@
+package analyzer
+
+import (
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Start of oh-my-zsh's plugins array, set with plugins=( or added to
+// with plugins+=(
+var ohMyZshPluginsRegex = regexp.MustCompile(`(?m)^\s*plugins(\+?)=\(`)
+
+// Where oh-my-zsh keeps custom and bundled plugins
+var ohMyZshPluginDirs = []string{"~/.oh-my-zsh/custom/plugins", "~/.oh-my-zsh/plugins"}
+
+// The plugins a zsh config enables through oh-my-zsh's plugins array,
+// which may span several lines and hold comments. A later plugins=(
+// replaces the list, like it does in zsh, and plugins+=( adds to it.
+func ohMyZshPlugins(config ShellConfig) []PluginInfo {
+	var paths []string
+	for path := range config.ConfigFiles {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var names []string
+	// The config each plugin was enabled in
+	enabledIn := make(map[string]ConfigInfo)
+	for _, path := range paths {
+		file := config.ConfigFiles[path]
+		for _, match := range ohMyZshPluginsRegex.FindAllStringSubmatchIndex(file.Content, -1) {
+			body := file.Content[match[1]:]
+			if end := strings.IndexByte(body, ')'); end >= 0 {
+				body = body[:end]
+			}
+			// No + before the =
+			if match[2] == match[3] {
+				names = nil
+				clear(enabledIn)
+			}
+			for _, line := range strings.Split(body, "\n") {
+				line, _, _ = strings.Cut(line, "#")
+				for _, name := range strings.Fields(line) {
+					name = strings.Trim(name, `'"`)
+					if _, seen := enabledIn[name]; name != "" && !seen {
+						names = append(names, name)
+						enabledIn[name] = file
+					}
+				}
+			}
+		}
+	}
+
+	plugins := make([]PluginInfo, 0, len(names))
+	for _, name := range names {
+		// When the plugin was last updated, or else when the config
+		// enabling it was
+		updated := enabledIn[name].Modified
+		for _, dir := range ohMyZshPluginDirs {
+			if info, err := os.Stat(config.ExpandPath(dir + "/" + name)); err == nil {
+				updated = info.ModTime()
+				break
+			}
+		}
+		plugins = append(plugins, PluginInfo{Name: name, Source: "oh-my-zsh", LastUpdated: updated})
+	}
+	return plugins
+}
+
+// Lines that load a plugin through a zsh plugin manager, capturing the
+// plugin's name: a repository like zsh-users/zsh-autosuggestions, an
+// oh-my-zsh plugin, or a zinit snippet like OMZP::git
+var zshPluginLoadRegexes = []struct {
+	Manager string
+	Regex   *regexp.Regexp
+}{
+	{"zinit", regexp.MustCompile(`(?:^|[;&|]\s*)(?:zinit|zi|zplugin)\s+(?:load|light|snippet)\s+(?:-\S+\s+)*["']?([^"'\s;&|]+)`)},
+	{"antigen", regexp.MustCompile(`(?:^|[;&|]\s*)antigen\s+bundle\s+["']?([^"'\s;&|]+)`)},
+	{"zplug", regexp.MustCompile(`(?:^|[;&|]\s*)zplug\s+["']?([^"'\s,;&|]+/[^"'\s,;&|]*)`)},
+}
+
+// Start of antigen's heredoc of bundles, one per line
+var antigenBundlesRegex = regexp.MustCompile(`^\s*antigen\s+bundles\s+<<-?\s*['"]?(\w+)`)
+
+// Plugins a zsh config loads with zinit, antigen or zplug
+func managedZshPlugins(config ShellConfig) []PluginInfo {
+	var paths []string
+	for path := range config.ConfigFiles {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var plugins []PluginInfo
+	seen := make(map[string]bool)
+	add := func(manager, name string, file ConfigInfo) {
+		// antigen takes a branch after @, like user/repo@develop
+		name, _, _ = strings.Cut(name, "@")
+		if name == "" || seen[manager+" "+name] {
+			return
+		}
+		seen[manager+" "+name] = true
+		plugins = append(plugins, PluginInfo{Name: name, Source: manager, LastUpdated: file.Modified})
+	}
+
+	for _, path := range paths {
+		file := config.ConfigFiles[path]
+		delimiter := ""
+		for _, line := range strings.Split(file.Content, "\n") {
+			line = strings.TrimSpace(strings.TrimSuffix(line, "\r"))
+			if delimiter != "" {
+				if line == delimiter {
+					delimiter = ""
+				} else if fields := strings.Fields(line); len(fields) > 0 && !strings.HasPrefix(fields[0], "#") {
+					add("antigen", fields[0], file)
+				}
+				continue
+			}
+			if strings.HasPrefix(line, "#") {
+				continue
+			}
+			if match := antigenBundlesRegex.FindStringSubmatch(line); match != nil {
+				delimiter = match[1]
+				continue
+			}
+			for _, loader := range zshPluginLoadRegexes {
+				for _, match := range loader.Regex.FindAllStringSubmatch(line, -1) {
+					add(loader.Manager, match[1], file)
+				}
+			}
+		}
+	}
+	return plugins
+}
@@ -0,0 +1,129 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+// Plugin names and their sources, in order
+func pluginNames(plugins []PluginInfo) []string {
+	var names []string
+	for _, plugin := range plugins {
+		names = append(names, plugin.Source+" "+plugin.Name)
+	}
+	return names
+}
+
+func TestOhMyZshPlugins(t *testing.T) {
+	modified := time.Date(2024, 4, 12, 10, 0, 0, 0, time.UTC)
+	config := newTestShellConfig()
+	config.home = t.TempDir()
+	config.ConfigFiles = map[string]ConfigInfo{
+		"~/.zshrc": {Path: "~/.zshrc", Modified: modified, Content: `export ZSH="$HOME/.oh-my-zsh"
+# plugins=(commented out)
+plugins=(
+  git      # version control
+  docker
+  # kubectl
+  "zsh-autosuggestions"
+  git
+)
+source $ZSH/oh-my-zsh.sh
+`},
+		"~/.zshrc.local": {Path: "~/.zshrc.local", Modified: modified, Content: "plugins+=(fzf)\r\n"},
+	}
+
+	// A custom plugin's own directory dates it
+	pluginDir := filepath.Join(config.home, ".oh-my-zsh/custom/plugins/zsh-autosuggestions")
+	if err := os.MkdirAll(pluginDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	updated := modified.Add(time.Hour)
+	if err := os.Chtimes(pluginDir, updated, updated); err != nil {
+		t.Fatal(err)
+	}
+
+	plugins := ohMyZshPlugins(config)
+	want := []string{"oh-my-zsh git", "oh-my-zsh docker", "oh-my-zsh zsh-autosuggestions", "oh-my-zsh fzf"}
+	if got := pluginNames(plugins); !slices.Equal(got, want) {
+		t.Fatalf("plugins = %q, want %q", got, want)
+	}
+	if !plugins[0].LastUpdated.Equal(modified) {
+		t.Errorf("git updated %v, want the config's time %v", plugins[0].LastUpdated, modified)
+	}
+	if !plugins[2].LastUpdated.Equal(updated) {
+		t.Errorf("zsh-autosuggestions updated %v, want its directory's time %v", plugins[2].LastUpdated, updated)
+	}
+}
+
+func TestOhMyZshPluginsReassigned(t *testing.T) {
+	config := newTestShellConfig()
+	config.ConfigFiles = map[string]ConfigInfo{
+		"~/.zshrc": {Content: "plugins=(git docker)\nplugins=(kubectl)\nplugins+=(\n\thelm\n)\n"},
+	}
+	want := []string{"oh-my-zsh kubectl", "oh-my-zsh helm"}
+	if got := pluginNames(ohMyZshPlugins(config)); !slices.Equal(got, want) {
+		t.Errorf("plugins = %q, want %q", got, want)
+	}
+}
+
+func TestManagedZshPlugins(t *testing.T) {
+	config := newTestShellConfig()
+	config.ConfigFiles = map[string]ConfigInfo{
+		"~/.zshrc": {Content: `source ~/.zinit/bin/zinit.zsh
+zinit light zsh-users/zsh-autosuggestions
+zinit load "zdharma-continuum/fast-syntax-highlighting"
+zinit ice wait lucid; zinit light -b romkatv/powerlevel10k
+zi snippet OMZP::git
+# zinit light commented/out
+source ~/antigen.zsh
+antigen bundle git
+antigen bundle zsh-users/zsh-completions@develop
+antigen bundle 'zsh-users/zsh-syntax-highlighting'
+antigen bundles <<EOBUNDLES
+    docker
+    # not-a-bundle
+    lukechilds/zsh-nvm@main
+EOBUNDLES
+antigen apply
+zplug "zsh-users/zsh-history-substring-search"
+zplug "plugins/git", from:oh-my-zsh
+zplug load
+zinit light zsh-users/zsh-autosuggestions
+`},
+	}
+
+	want := []string{
+		"zinit zsh-users/zsh-autosuggestions",
+		"zinit zdharma-continuum/fast-syntax-highlighting",
+		"zinit romkatv/powerlevel10k",
+		"zinit OMZP::git",
+		"antigen git",
+		"antigen zsh-users/zsh-completions",
+		"antigen zsh-users/zsh-syntax-highlighting",
+		"antigen docker",
+		"antigen lukechilds/zsh-nvm",
+		"zplug zsh-users/zsh-history-substring-search",
+		"zplug plugins/git",
+	}
+	if got := pluginNames(managedZshPlugins(config)); !slices.Equal(got, want) {
+		t.Errorf("plugins = %q\nwant %q", got, want)
+	}
+}
+
+func TestDetectZshPlugins(t *testing.T) {
+	config := newTestShellConfig()
+	config.home = t.TempDir()
+	config.ConfigFiles = map[string]ConfigInfo{
+		"~/.zshrc": {Content: "plugins=(git)\nantigen bundle zsh-users/zsh-completions\n"},
+	}
+	detectZshPlugins(&config)
+
+	want := []string{"oh-my-zsh git", "antigen zsh-users/zsh-completions"}
+	if got := pluginNames(config.Plugins); !slices.Equal(got, want) {
+		t.Errorf("plugins = %q, want %q", got, want)
+	}
+}
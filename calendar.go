@@ -0,0 +1,170 @@
+package main
+
+import (
+	"fmt"
+	"html/template"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"shell-analyzer/analyzer"
+)
+
+// calendarGrid is a week-per-column view of daily activity, like GitHub's
+// contribution graph. Cells outside the covered range hold -1.
+type calendarGrid struct {
+	start  time.Time
+	weeks  int
+	counts [7][]int
+	max    int
+}
+
+func buildCalendar(daily map[string]int, now time.Time) (calendarGrid, bool) {
+	var grid calendarGrid
+	if len(daily) == 0 {
+		return grid, false
+	}
+
+	end := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	start := end.AddDate(0, 0, -364)
+
+	// Partial years start at the week of the first recorded day
+	var first time.Time
+	for day := range daily {
+		date, err := time.ParseInLocation(analyzer.DayLayout, day, now.Location())
+		if err != nil {
+			continue
+		}
+		if first.IsZero() || date.Before(first) {
+			first = date
+		}
+	}
+	if first.IsZero() || first.After(end) {
+		return grid, false
+	}
+	if first.After(start) {
+		start = first
+	}
+	start = start.AddDate(0, 0, -int(start.Weekday()))
+
+	grid.start = start
+	grid.weeks = int(end.Sub(start).Hours()/24)/7 + 1
+	for weekday := range grid.counts {
+		grid.counts[weekday] = make([]int, grid.weeks)
+	}
+
+	for week := 0; week < grid.weeks; week++ {
+		for weekday := 0; weekday < 7; weekday++ {
+			date := start.AddDate(0, 0, week*7+weekday)
+			if date.After(end) {
+				grid.counts[weekday][week] = -1
+				continue
+			}
+			count := daily[date.Format(analyzer.DayLayout)]
+			grid.counts[weekday][week] = count
+			if count > grid.max {
+				grid.max = count
+			}
+		}
+	}
+
+	return grid, grid.max > 0
+}
+
+// Intensity level for a count, from 0 (no activity) to levels
+func (g calendarGrid) level(count, levels int) int {
+	if count <= 0 || g.max == 0 {
+		return 0
+	}
+	return 1 + (count-1)*levels/g.max
+}
+
+// Month labels positioned over the week columns, each cellWidth wide
+func (g calendarGrid) monthLabels(cellWidth int) string {
+	labels := []byte(strings.Repeat(" ", g.weeks*cellWidth+3))
+	lastMonth := time.Month(0)
+	nextFree := 0
+	for week := 0; week < g.weeks; week++ {
+		month := g.start.AddDate(0, 0, week*7).Month()
+		position := week * cellWidth
+		if month != lastMonth && position >= nextFree {
+			copy(labels[position:], month.String()[:3])
+			nextFree = position + 4
+		}
+		lastMonth = month
+	}
+	return strings.TrimRight(string(labels), " ")
+}
+
+func renderCalendar(daily map[string]int, theme analyzer.Theme, now time.Time) string {
+	grid, ok := buildCalendar(daily, now)
+	if !ok {
+		return ""
+	}
+
+	colors := theme.CalendarPalette()
+	levels := len(colors) - 1
+	weekdayLabels := [7]string{"", "Mon", "", "Wed", "", "Fri", ""}
+
+	style := lipgloss.NewStyle().
+		BorderStyle(lipgloss.RoundedBorder()).
+		Padding(1)
+
+	var content strings.Builder
+	content.WriteString("🗓️  Activity Calendar:\n")
+	content.WriteString("    " + grid.monthLabels(2) + "\n")
+	for weekday := 0; weekday < 7; weekday++ {
+		content.WriteString(fmt.Sprintf("%-4s", weekdayLabels[weekday]))
+		for week := 0; week < grid.weeks; week++ {
+			count := grid.counts[weekday][week]
+			if count < 0 {
+				continue
+			}
+			cell := lipgloss.NewStyle().
+				Foreground(lipgloss.Color(colors[grid.level(count, levels)])).
+				Render("■")
+			content.WriteString(cell + " ")
+		}
+		content.WriteString("\n")
+	}
+
+	// Legend
+	content.WriteString("    Less ")
+	for _, c := range colors {
+		content.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color(c)).Render("■") + " ")
+	}
+	content.WriteString("More")
+
+	return style.Render(content.String())
+}
+
+func renderCalendarHTML(daily map[string]int, theme analyzer.Theme, now time.Time) template.HTML {
+	grid, ok := buildCalendar(daily, now)
+	if !ok {
+		return ""
+	}
+
+	colors := theme.CalendarPalette()
+	levels := len(colors) - 1
+
+	var content strings.Builder
+	content.WriteString(`<table class="calendar">`)
+	for weekday := 0; weekday < 7; weekday++ {
+		content.WriteString("<tr>")
+		for week := 0; week < grid.weeks; week++ {
+			count := grid.counts[weekday][week]
+			if count < 0 {
+				content.WriteString("<td></td>")
+				continue
+			}
+			date := grid.start.AddDate(0, 0, week*7+weekday).Format(analyzer.DayLayout)
+			content.WriteString(fmt.Sprintf(`<td style="background:%s" title="%s: %d commands"></td>`,
+				template.HTMLEscapeString(colors[grid.level(count, levels)]), date, count))
+		}
+		content.WriteString("</tr>")
+	}
+	content.WriteString("</table>")
+
+	return template.HTML(content.String())
+}
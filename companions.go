@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"shell-analyzer/analyzer"
+)
+
+// Uses of a tool before its missing companions are suggested
+const minCompanionUses = 5
+
+// Suggest companions for tools that are used but whose companions never are
+func generateCompanionTips(data analyzer.ShellData, rules []analyzer.CompanionRule) []string {
+	uses := make([]int, len(rules))
+	seen := make([]bool, len(rules))
+
+	for _, history := range data.Histories {
+		for _, entry := range history {
+			for _, cmd := range analyzer.SplitCommand(entry.Command) {
+				for i, rule := range rules {
+					if analyzer.MatchesCommandPrefix(cmd, rule.Uses) {
+						uses[i] += entry.Runs()
+					}
+					for _, suggestion := range rule.Suggest {
+						if analyzer.MatchesCommandPrefix(cmd, suggestion) {
+							seen[i] = true
+						}
+					}
+				}
+			}
+		}
+	}
+
+	tips := []string{}
+	for i, rule := range rules {
+		if uses[i] < minCompanionUses || seen[i] {
+			continue
+		}
+		tip := rule.Tip
+		if tip == "" {
+			tip = fmt.Sprintf("You use %s but never %s; give it a try", rule.Uses, strings.Join(rule.Suggest, " or "))
+		}
+		tips = append(tips, tip)
+	}
+	return tips
+}
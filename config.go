@@ -0,0 +1,3 @@
+package main
+
+const defaultConfigPath = "~/.config/shell-analyser/config.json"
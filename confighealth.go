@@ -0,0 +1,184 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/gookit/color"
+
+	"shell-analyzer/analyzer"
+)
+
+// AliasConflict is an alias defined with different values. The shell
+// silently keeps the last definition it reads.
+type AliasConflict struct {
+	Name        string
+	Definitions []analyzer.AliasDefinition
+}
+
+func (c AliasConflict) Winner() analyzer.AliasDefinition {
+	return c.Definitions[len(c.Definitions)-1]
+}
+
+// Past these a config is probably due for splitting up
+const (
+	largeConfigLines      = 500
+	complexConfigElements = 100
+)
+
+var (
+	configFunctionRegex    = regexp.MustCompile(`^\s*(function\s+[\w.:-]+|[\w.:-]+\s*\(\)\s*\{?\s*$)`)
+	configConditionalRegex = regexp.MustCompile(`^\s*(if|elif|case|switch|while|until)\b|(\]\]|\])\s*(&&|\|\|)`)
+)
+
+// ConfigStats summarizes how large and elaborate a shell's config is
+type ConfigStats struct {
+	Files        int
+	Bytes        int
+	Lines        int
+	Aliases      int
+	Functions    int
+	Conditionals int
+}
+
+// Complexity is a rough count of the definitions and branches in a config
+func (s ConfigStats) Complexity() int {
+	return s.Aliases + s.Functions + s.Conditionals
+}
+
+func measureConfig(config analyzer.ShellConfig) ConfigStats {
+	var stats ConfigStats
+	for _, file := range config.ConfigFiles {
+		if file.Content == "" {
+			continue
+		}
+		stats.Files++
+		stats.Bytes += len(file.Content)
+		content := strings.ReplaceAll(file.Content, "\r\n", "\n")
+		for _, line := range strings.Split(strings.TrimRight(content, "\n"), "\n") {
+			stats.Lines++
+			trimmed := strings.TrimSpace(line)
+			if strings.HasPrefix(trimmed, "#") {
+				continue
+			}
+			switch {
+			case strings.HasPrefix(trimmed, "alias ") || strings.HasPrefix(trimmed, "abbr "):
+				stats.Aliases++
+			case configFunctionRegex.MatchString(trimmed):
+				stats.Functions++
+			case configConditionalRegex.MatchString(trimmed):
+				stats.Conditionals++
+			}
+		}
+	}
+	return stats
+}
+
+func formatBytes(bytes int) string {
+	switch {
+	case bytes >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(bytes)/(1<<20))
+	case bytes >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(bytes)/(1<<10))
+	}
+	return fmt.Sprintf("%d B", bytes)
+}
+
+func findAliasConflicts(config analyzer.ShellConfig) []AliasConflict {
+	var conflicts []AliasConflict
+	for name, definitions := range config.AliasDefinitions {
+		for _, definition := range definitions[1:] {
+			if definition.Value != definitions[0].Value {
+				conflicts = append(conflicts, AliasConflict{Name: name, Definitions: definitions})
+				break
+			}
+		}
+	}
+
+	sort.Slice(conflicts, func(i, j int) bool {
+		return conflicts[i].Name < conflicts[j].Name
+	})
+	return conflicts
+}
+
+func renderConfigHealth(data analyzer.ShellData) string {
+	style := lipgloss.NewStyle().
+		BorderStyle(lipgloss.RoundedBorder()).
+		Padding(1)
+
+	var content strings.Builder
+	content.WriteString(color.Green.Sprintf("🩹 Config Health\n\n"))
+	content.WriteString(renderSkipped(data.Skipped, analyzer.SkippedConfig))
+
+	var shells []string
+	for shell := range data.ShellConfigs {
+		shells = append(shells, shell)
+	}
+	sort.Strings(shells)
+
+	if len(shells) == 0 {
+		content.WriteString("No shell configs found\n")
+		return style.Render(content.String())
+	}
+
+	for i, shell := range shells {
+		if i > 0 {
+			content.WriteString("\n")
+		}
+		content.WriteString(color.Cyan.Sprintf("%s\n", shell))
+		config := data.ShellConfigs[shell]
+
+		// Size and Complexity
+		content.WriteString("📏 Size and Complexity:\n")
+		if stats := measureConfig(config); stats.Files > 0 {
+			content.WriteString(fmt.Sprintf("%d file(s), %s, %d lines\n", stats.Files, formatBytes(stats.Bytes), stats.Lines))
+			content.WriteString(fmt.Sprintf("Complexity %d: %d aliases, %d functions, %d conditionals\n",
+				stats.Complexity(), stats.Aliases, stats.Functions, stats.Conditionals))
+			if stats.Lines > largeConfigLines || stats.Complexity() > complexConfigElements {
+				content.WriteString(fmt.Sprintf("💡 %s\n", color.Yellow.Sprint(
+					"This config is getting large; consider splitting aliases and functions into separate sourced files")))
+			}
+		} else {
+			content.WriteString("No config files found\n")
+		}
+		content.WriteString("\n")
+
+		// History Options
+		if shell == "bash" || shell == "zsh" {
+			content.WriteString("🕰️  History Options:\n")
+			if enabled := enabledHistoryOptions(config.HistoryOptions); len(enabled) > 0 {
+				content.WriteString(fmt.Sprintf("Enabled: %s\n", strings.Join(enabled, ", ")))
+			} else {
+				content.WriteString("No history options set\n")
+			}
+			for _, tip := range generateHistoryOptionTips(shell, config.HistoryOptions) {
+				content.WriteString(fmt.Sprintf("💡 %s\n", tip))
+			}
+			content.WriteString("\n")
+		}
+
+		// Alias Conflicts
+		content.WriteString("⚔️  Alias Conflicts:\n")
+		conflicts := findAliasConflicts(config)
+		if len(conflicts) == 0 {
+			content.WriteString("No conflicting aliases\n")
+		}
+		for _, conflict := range conflicts {
+			winner := conflict.Winner()
+			content.WriteString(fmt.Sprintf("• %s\n", color.Yellow.Sprint(conflict.Name)))
+			for _, definition := range conflict.Definitions {
+				marker := " "
+				if definition == winner {
+					marker = color.Green.Sprint("✓")
+				}
+				content.WriteString(fmt.Sprintf("  %s %s:%d  %s\n", marker, definition.File, definition.Line, definition.Value))
+			}
+		}
+	}
+
+	content.WriteString("\n✓ marks the definition that wins, since the last one read takes effect")
+	return style.Render(content.String())
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/gookit/color"
+)
+
+// Note which shells had a part of the analysis turned off, so empty
+// sections aren't mistaken for missing data
+func renderSkipped(skipped map[string][]string, part string) string {
+	var shells []string
+	for shell, parts := range skipped {
+		for _, p := range parts {
+			if p == part {
+				shells = append(shells, shell)
+			}
+		}
+	}
+	if len(shells) == 0 {
+		return ""
+	}
+	sort.Strings(shells)
+	return color.Gray.Sprintf("⏭️  %s analysis turned off for %s (analyze_%s in config)\n\n",
+		strings.Title(part), strings.Join(shells, ", "), part)
+}
@@ -0,0 +1,42 @@
+package main
+
+import "github.com/charmbracelet/lipgloss"
+
+const (
+	// Narrower terminals fall back to tabs, since each section needs
+	// about half this width
+	dashboardMinWidth = 160
+
+	// Lines taken by the header, footer and status line
+	dashboardChromeHeight = 12
+
+	layoutDashboard = "dashboard"
+)
+
+// Whether the dashboard is on and the terminal is wide enough for it
+func (m Model) showDashboard() bool {
+	return m.dashboard && m.width >= dashboardMinWidth
+}
+
+// Render the main sections in a 2x2 grid sized to the terminal
+func renderDashboard(m Model) string {
+	// Sections wider or taller than their cell are cut rather than
+	// wrapped, which would break their borders
+	clip := lipgloss.NewStyle().MaxWidth(m.width / 2)
+	if rowHeight := (m.height - dashboardChromeHeight) / 2; rowHeight > 0 {
+		clip = clip.MaxHeight(rowHeight)
+	}
+	pad := lipgloss.NewStyle().Width(m.width / 2)
+	cell := func(section string) string {
+		return pad.Render(clip.Render(section))
+	}
+
+	top := lipgloss.JoinHorizontal(lipgloss.Top,
+		cell(renderOverview(m.shellData, m.categoryFilter)),
+		cell(m.techProfileView))
+	bottom := lipgloss.JoinHorizontal(lipgloss.Top,
+		cell(renderWorkPatterns(m.shellData.Insights.WorkPatterns, m.shellData.TimePatterns)),
+		cell(renderToolUsage(m.shellData.Insights.ToolUsage)))
+
+	return lipgloss.JoinVertical(lipgloss.Left, top, bottom)
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/gookit/color"
+
+	"shell-analyzer/analyzer"
+)
+
+func renderDiagnostics(data analyzer.ShellData) string {
+	style := lipgloss.NewStyle().
+		BorderStyle(lipgloss.RoundedBorder()).
+		Padding(1)
+
+	var content strings.Builder
+	content.WriteString(color.Blue.Sprintf("🩺 Diagnostics\n\n"))
+
+	content.WriteString(renderErrors(data.Errors))
+	content.WriteString("\n")
+
+	content.WriteString(renderPromptNoise(data.PromptNoise))
+	content.WriteString("\n")
+
+	content.WriteString(renderAliasExpansions(data.AliasExpansions))
+
+	return style.Render(content.String())
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/gookit/color"
+
+	"shell-analyzer/analyzer"
+)
+
+// Outcome of a -doctor check. Failures make -doctor exit non-zero.
+type checkStatus int
+
+const (
+	checkPass checkStatus = iota
+	checkWarn
+	checkFail
+)
+
+type doctorCheck struct {
+	Name   string
+	Status checkStatus
+	Detail string
+}
+
+// How to get timestamps recorded, per shell
+var timestampHints = map[string]string{
+	"bash": "set HISTTIMEFORMAT",
+	"zsh":  "setopt EXTENDED_HISTORY",
+}
+
+// Check the environment the analysis depends on: the config, running
+// commands, and each shell's history
+func runDoctor(configPath string, cfg analyzer.Config, configErr error) []doctorCheck {
+	var checks []doctorCheck
+
+	switch _, err := os.Stat(analyzer.ExpandPath(configPath)); {
+	case configErr != nil:
+		checks = append(checks, doctorCheck{"Config", checkFail, fmt.Sprintf("%v; using defaults", configErr)})
+	case errors.Is(err, os.ErrNotExist):
+		checks = append(checks, doctorCheck{"Config", checkPass, fmt.Sprintf("no file at %s, using defaults", configPath)})
+	default:
+		checks = append(checks, doctorCheck{"Config", checkPass, fmt.Sprintf("%s parsed", configPath)})
+	}
+
+	if out, err := analyzer.RunWithTimeout("echo ok", cfg.CommandTimeout()); err != nil || strings.TrimSpace(out) != "ok" {
+		checks = append(checks, doctorCheck{"Running commands", checkFail,
+			fmt.Sprintf("could not run the system shell (%v); installed languages can't be detected", err)})
+	} else {
+		checks = append(checks, doctorCheck{"Running commands", checkPass, "the system shell works"})
+	}
+
+	readable := 0
+	for _, source := range analyzer.HistorySources(analyzer.Options{Config: &cfg}) {
+		shellChecks, ok := checkShell(source, cfg)
+		checks = append(checks, shellChecks...)
+		if ok {
+			readable++
+		}
+	}
+	if readable == 0 {
+		checks = append(checks, doctorCheck{"History", checkFail, "no readable history for any shell, so there's nothing to analyze"})
+	}
+
+	return checks
+}
+
+// Check one shell's binary, history file and timestamps. ok reports
+// whether any history was read.
+func checkShell(source analyzer.HistorySource, cfg analyzer.Config) (checks []doctorCheck, ok bool) {
+	shell := source.Name()
+	if _, err := exec.LookPath(shell); err != nil {
+		checks = append(checks, doctorCheck{shell, checkWarn, "not installed"})
+	} else {
+		checks = append(checks, doctorCheck{shell, checkPass, "installed"})
+	}
+
+	name := shell + " history"
+	if !cfg.HistoryEnabled(shell) {
+		return append(checks, doctorCheck{name, checkWarn, "turned off with analyze_history"}), false
+	}
+
+	location := source.Location()
+	if !source.Detect() {
+		return append(checks, doctorCheck{name, checkWarn, fmt.Sprintf("no history at %s", location)}), false
+	}
+	entries, err := source.Read()
+	switch {
+	case errors.Is(err, analyzer.ErrLineTooLong):
+		checks = append(checks, doctorCheck{name, checkWarn, err.Error()})
+	case err != nil:
+		return append(checks, doctorCheck{name, checkFail, err.Error()}), false
+	case len(entries) == 0:
+		return append(checks, doctorCheck{name, checkWarn, fmt.Sprintf("%s is empty", location)}), false
+	default:
+		checks = append(checks, doctorCheck{name, checkPass, fmt.Sprintf("%d commands in %s", len(entries), location)})
+	}
+
+	timestamped := 0
+	for _, entry := range entries {
+		if !entry.Timestamp.IsZero() {
+			timestamped++
+		}
+	}
+	name = shell + " timestamps"
+	switch {
+	case timestamped == 0:
+		detail := "none recorded, so time-based views stay empty"
+		if hint, ok := timestampHints[shell]; ok {
+			detail += "; " + hint
+		}
+		checks = append(checks, doctorCheck{name, checkWarn, detail})
+	default:
+		checks = append(checks, doctorCheck{name, checkPass,
+			fmt.Sprintf("%.0f%% of commands", float64(timestamped)/float64(len(entries))*100)})
+	}
+
+	return checks, true
+}
+
+// Print the checklist and report whether every critical check passed
+func printDoctor(w io.Writer, checks []doctorCheck) bool {
+	passed := true
+	for _, check := range checks {
+		var mark string
+		switch check.Status {
+		case checkPass:
+			mark = color.Green.Sprint("✔ pass")
+		case checkWarn:
+			mark = color.Yellow.Sprint("⚠ warn")
+		case checkFail:
+			mark = color.Red.Sprint("✘ fail")
+			passed = false
+		}
+		fmt.Fprintf(w, "%s  %-18s %s\n", mark, check.Name, check.Detail)
+	}
+	return passed
+}
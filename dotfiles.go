@@ -0,0 +1,83 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"shell-analyzer/analyzer"
+)
+
+// Quote s as a single word in a shell's syntax
+func shellQuote(shell, s string) string {
+	switch shell {
+	case "fish":
+		s = strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s)
+		return "'" + s + "'"
+	case "powershell":
+		return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+	default:
+		return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+	}
+}
+
+// The line defining a suggested alias in a shell's syntax. fish and
+// PowerShell get a function, since their aliases can't pass arguments
+// through the way bash and zsh aliases do.
+func aliasDefinition(shell string, suggestion AliasSuggestion) string {
+	switch shell {
+	case "fish":
+		return fmt.Sprintf("function %s --wraps %s; %s $argv; end",
+			suggestion.Name, shellQuote(shell, suggestion.Command), suggestion.Command)
+	case "powershell":
+		return fmt.Sprintf("function %s { %s @args }", suggestion.Name, suggestion.Command)
+	default:
+		return fmt.Sprintf("alias %s=%s", suggestion.Name, shellQuote(shell, suggestion.Command))
+	}
+}
+
+// Write the primary shell's suggested aliases and history settings as a
+// snippet ready to source from, or paste into, its config. Anything the
+// config already has is left out.
+func exportDotfiles(data analyzer.ShellData, w io.Writer) error {
+	shell := data.PrimaryShell
+	if shell == "" {
+		return errors.New("no shell history or config found to write suggestions for")
+	}
+	config := data.ShellConfigs[shell]
+
+	var content strings.Builder
+	content.WriteString(fmt.Sprintf("# Suggestions from shell-analyser for %s, generated %s\n",
+		shell, time.Now().Format(analyzer.DayLayout)))
+	content.WriteString("# Review them, then source this file from your config or copy what you want into it\n")
+	if slices.Contains(data.Skipped[shell], analyzer.SkippedConfig) {
+		content.WriteString("# Your config wasn't analyzed, so some of these may already be in it\n")
+	}
+
+	empty := true
+	if suggestions := suggestAliases(shell, data.Histories[shell], config); len(suggestions) > 0 {
+		empty = false
+		content.WriteString("\n# Commands you run often\n")
+		for _, suggestion := range suggestions {
+			content.WriteString(fmt.Sprintf("%s  # %d runs\n", aliasDefinition(shell, suggestion), suggestion.Runs))
+		}
+	}
+
+	if settings := missingHistorySettings(shell, config.HistoryOptions); len(settings) > 0 {
+		empty = false
+		content.WriteString("\n# History settings\n")
+		for _, setting := range settings {
+			content.WriteString(setting.Line + "\n")
+		}
+	}
+
+	if empty {
+		content.WriteString("\n# Nothing to add, your config already has every suggestion\n")
+	}
+
+	_, err := io.WriteString(w, content.String())
+	return err
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"shell-analyzer/analyzer"
+)
+
+func TestAliasDefinitionRoundTrips(t *testing.T) {
+	for _, shell := range []string{"bash", "zsh"} {
+		for _, cmd := range []string{"git status", "cd 'My Documents'", `grep 'it'\''s'`} {
+			line := aliasDefinition(shell, AliasSuggestion{Name: "x", Command: cmd})
+			home := writeTestHome(t, map[string]string{
+				"." + shell + "rc":       line + "\n",
+				"." + shell + "_history": "x\n",
+			})
+			data, err := analyzer.Analyze(analyzer.Options{HomeDir: home, Shells: []string{shell}, NoProbe: true})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if aliases := data.ShellConfigs[shell].Aliases; len(aliases) != 1 || aliases["x"] != cmd {
+				t.Errorf("%s: %s defines %q, want x=%q", shell, line, aliases, cmd)
+			}
+		}
+	}
+
+	line := aliasDefinition("fish", AliasSuggestion{Name: "cm", Command: "cd 'My Documents'"})
+	if want := `function cm --wraps 'cd \'My Documents\''; cd 'My Documents' $argv; end`; line != want {
+		t.Errorf("fish: %s, want %s", line, want)
+	}
+}
+
+func TestExportDotfilesSkipsExistingConfig(t *testing.T) {
+	data := analyzer.NewShellData()
+	data.PrimaryShell = "zsh"
+	for range minAliasSuggestionUses + 1 {
+		data.Histories["zsh"] = append(data.Histories["zsh"],
+			analyzer.CommandEntry{Command: "make test"}, analyzer.CommandEntry{Command: "qzx build"})
+	}
+	data.ShellConfigs["zsh"] = analyzer.ShellConfig{
+		Aliases:        map[string]string{"mt": "make test"},
+		HistoryOptions: map[string]bool{"EXTENDED_HISTORY": true},
+	}
+
+	var out strings.Builder
+	if err := exportDotfiles(data, &out); err != nil {
+		t.Fatal(err)
+	}
+	snippet := out.String()
+	if !strings.Contains(snippet, "alias qb='qzx build'") {
+		t.Errorf("snippet is missing the qzx build alias:\n%s", snippet)
+	}
+	if strings.Contains(snippet, "'make test'") || strings.Contains(snippet, "setopt EXTENDED_HISTORY") {
+		t.Errorf("snippet repeats what the config has:\n%s", snippet)
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/gookit/color"
+
+	"shell-analyzer/analyzer"
+)
+
+// Config changes listed in the Config Drift view, newest first
+const maxDriftChanges = 40
+
+func renderConfigDrift(drift analyzer.ConfigDrift) string {
+	style := lipgloss.NewStyle().
+		BorderStyle(lipgloss.RoundedBorder()).
+		Padding(1)
+
+	var content strings.Builder
+	content.WriteString(color.Cyan.Sprintf("🧭 Config Drift\n\n"))
+
+	if drift.Since.IsZero() {
+		content.WriteString("No snapshots with your config yet\n")
+		content.WriteString("Run with -snapshot to start tracking how your aliases, plugins and environment change\n")
+		if drift.Unrecorded > 0 {
+			content.WriteString(color.Gray.Sprintf("%d older snapshot(s) were taken before snapshots recorded configs", drift.Unrecorded) + "\n")
+		}
+		return style.Render(content.String())
+	}
+
+	content.WriteString(fmt.Sprintf("Tracking since %s across %d snapshot(s)\n", drift.Since.Format(analyzer.DayLayout), drift.Recorded))
+	if drift.Unrecorded > 0 {
+		content.WriteString(color.Gray.Sprintf("%d older snapshot(s) were taken before snapshots recorded configs", drift.Unrecorded) + "\n")
+	}
+	content.WriteString("\n")
+
+	if len(drift.Changes) == 0 {
+		content.WriteString("No aliases, plugins or environment variables changed\n")
+		return style.Render(content.String())
+	}
+
+	signs := map[string]string{
+		analyzer.DriftAdded:   color.Green.Sprint("+"),
+		analyzer.DriftRemoved: color.Red.Sprint("-"),
+		analyzer.DriftChanged: color.Yellow.Sprint("~"),
+	}
+	day := ""
+	for i, change := range drift.Changes {
+		if i == maxDriftChanges {
+			content.WriteString(color.Gray.Sprintf("…and %d older change(s)", len(drift.Changes)-i) + "\n")
+			break
+		}
+		if d := change.Time.Format(analyzer.DayLayout); d != day {
+			if day != "" {
+				content.WriteString("\n")
+			}
+			day = d
+			content.WriteString(color.Bold.Sprint(day) + "\n")
+		}
+		content.WriteString(fmt.Sprintf("  %s %-7s %s (%s)\n", signs[change.Change], change.Kind, change.Name, change.Shell))
+	}
+
+	return style.Render(content.String())
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// The most edited file types for an editor, like ".go 12, .md 4"
+func topFileTypes(fileTypes map[string]int, limit int) string {
+	var types []string
+	for fileType := range fileTypes {
+		types = append(types, fileType)
+	}
+	sort.Slice(types, func(i, j int) bool {
+		if fileTypes[types[i]] != fileTypes[types[j]] {
+			return fileTypes[types[i]] > fileTypes[types[j]]
+		}
+		return types[i] < types[j]
+	})
+	if len(types) > limit {
+		types = types[:limit]
+	}
+
+	var parts []string
+	for _, fileType := range types {
+		parts = append(parts, fmt.Sprintf("%s %d", fileType, fileTypes[fileType]))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,10 @@
+package main
+
+import "testing"
+
+func TestTopFileTypes(t *testing.T) {
+	types := map[string]int{".go": 12, ".md": 4, ".txt": 4, ".sh": 1}
+	if got, want := topFileTypes(types, 3), ".go 12, .md 4, .txt 4"; got != want {
+		t.Errorf("topFileTypes = %q, want %q", got, want)
+	}
+}
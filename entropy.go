@@ -0,0 +1,43 @@
+package main
+
+import (
+	"math"
+
+	"shell-analyzer/analyzer"
+)
+
+// How varied a history is, as the Shannon entropy of its command
+// frequencies normalized to 0 (one command over and over) to 1 (every
+// command used equally). ok is false with fewer than two distinct commands.
+func commandVariety(entries []analyzer.CommandEntry) (variety float64, ok bool) {
+	counts := make(map[string]int)
+	total := 0
+	for _, entry := range entries {
+		for _, cmd := range analyzer.SplitCommand(entry.Command) {
+			if base := analyzer.BaseCommand(cmd); base != "" {
+				counts[base] += entry.Runs()
+				total += entry.Runs()
+			}
+		}
+	}
+	if len(counts) < 2 {
+		return 0, false
+	}
+
+	var entropy float64
+	for _, count := range counts {
+		p := float64(count) / float64(total)
+		entropy -= p * math.Log2(p)
+	}
+	return entropy / math.Log2(float64(len(counts))), true
+}
+
+func describeVariety(variety float64) string {
+	switch {
+	case variety < 0.5:
+		return "repetitive, ripe for automation"
+	case variety < 0.75:
+		return "balanced"
+	}
+	return "varied"
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/gookit/color"
+
+	"shell-analyzer/analyzer"
+)
+
+// Missing history files are expected for shells the user doesn't use, so
+// they only count as problems when nothing at all could be read
+func visibleErrors(errs []error, historiesFound int) []error {
+	var visible []error
+	for _, err := range errs {
+		if errors.Is(err, analyzer.ErrHistoryNotFound) && historiesFound > 0 {
+			continue
+		}
+		visible = append(visible, err)
+	}
+	return visible
+}
+
+func renderStatusLine(errs []error, historiesFound int) string {
+	visible := visibleErrors(errs, historiesFound)
+	if len(visible) == 0 {
+		return ""
+	}
+
+	summary := visible[0].Error()
+	if len(visible) > 1 {
+		summary = fmt.Sprintf("%s (+%d more, see Diagnostics)", summary, len(visible)-1)
+	}
+
+	return "\n" + lipgloss.NewStyle().
+		Foreground(lipgloss.Color("203")).
+		Render("⚠ "+summary)
+}
+
+func renderErrors(errs []error) string {
+	var content strings.Builder
+	content.WriteString("⚠️  Errors:\n")
+	if len(errs) == 0 {
+		content.WriteString("No errors during analysis\n")
+		return content.String()
+	}
+	for _, err := range errs {
+		content.WriteString(fmt.Sprintf("• %s\n", color.Red.Sprint(err.Error())))
+	}
+	return content.String()
+}
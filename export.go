@@ -0,0 +1,297 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gookit/color"
+
+	"shell-analyzer/analyzer"
+)
+
+// Writers for each export format. Adding a format only takes a new entry.
+var exporters = map[string]func(analyzer.ShellData, analyzer.Config, io.Writer) error{
+	"json": func(data analyzer.ShellData, cfg analyzer.Config, w io.Writer) error {
+		if cfg.OmitConfigContents {
+			data = analyzer.WithoutConfigContents(data)
+		}
+		return analyzer.ExportJSON(data, w)
+	},
+	"csv":  func(data analyzer.ShellData, _ analyzer.Config, w io.Writer) error { return exportCSV(data, w) },
+	"html": exportHTML,
+	"md": func(data analyzer.ShellData, cfg analyzer.Config, w io.Writer) error {
+		return exportMarkdown(data, cfg.Section, w)
+	},
+	"text": func(data analyzer.ShellData, cfg analyzer.Config, w io.Writer) error {
+		return exportText(data, cfg.Section, w)
+	},
+
+	"summary":  func(data analyzer.ShellData, _ analyzer.Config, w io.Writer) error { return exportSummary(data, w) },
+	"dotfiles": func(data analyzer.ShellData, _ analyzer.Config, w io.Writer) error { return exportDotfiles(data, w) },
+}
+
+func exportFormats() []string {
+	var formats []string
+	for format := range exporters {
+		formats = append(formats, format)
+	}
+	sort.Strings(formats)
+	return formats
+}
+
+func checkExportFormat(format string) error {
+	if _, ok := exporters[format]; !ok {
+		return fmt.Errorf("%w %q, use one of %s", analyzer.ErrUnknownFormat, format, strings.Join(exportFormats(), ", "))
+	}
+	return nil
+}
+
+// Write the analysis in one of the export formats
+func Export(data analyzer.ShellData, format string, cfg analyzer.Config, w io.Writer) error {
+	if err := checkExportFormat(format); err != nil {
+		return err
+	}
+	return exporters[format](data, cfg, w)
+}
+
+type reportSection struct {
+	Title   string
+	Content string
+}
+
+// The views shown in text-based reports, without terminal colors
+func reportSections(data analyzer.ShellData) []reportSection {
+	sections := []reportSection{
+		{"Overview", renderOverview(data, "")},
+		{"Tech Profile", renderTechProfile(data.Insights.TechnicalProfile, false)},
+		{"Work Patterns", renderWorkPatterns(data.Insights.WorkPatterns, data.TimePatterns)},
+		{"Tool Usage", renderToolUsage(data.Insights.ToolUsage)},
+		{"Stacks", renderStacks(data.Insights.TechnicalProfile.StackUsage)},
+		{"Security", renderSecurity(data)},
+		{"Config Health", renderConfigHealth(data)},
+	}
+	if sinks := renderTimeSinks(data.Insights.WorkPatterns.TimeSinks); sinks != "" {
+		sections = append(sections, reportSection{"Time Sinks", sinks})
+	}
+
+	for i := range sections {
+		sections[i].Content = color.ClearCode(sections[i].Content)
+	}
+	return sections
+}
+
+// Views printed by -no-tui
+var plainReportTitles = []string{"Overview", "Tech Profile", "Work Patterns", "Tool Usage"}
+
+// Remove the rounded border and padding a view is rendered in, keeping
+// its lines
+func stripBorder(box string) string {
+	lines := strings.Split(box, "\n")
+	var kept []string
+	for _, line := range lines {
+		if strings.HasPrefix(line, "╭") || strings.HasPrefix(line, "╰") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "│")
+		line = strings.TrimSuffix(strings.TrimRight(line, " "), "│")
+		kept = append(kept, strings.TrimPrefix(strings.TrimRight(line, " "), " "))
+	}
+	return strings.Trim(strings.Join(kept, "\n"), "\n")
+}
+
+// Write the main views as plain text without borders or colors, for
+// scripts and CI
+func writePlainReport(data analyzer.ShellData, w io.Writer) error {
+	var report strings.Builder
+	for _, section := range reportSections(data) {
+		if !slices.Contains(plainReportTitles, section.Title) {
+			continue
+		}
+		report.WriteString(stripBorder(section.Content) + "\n\n")
+	}
+	_, err := io.WriteString(w, report.String())
+	return err
+}
+
+// Formats whose reports can be narrowed to one section with -section
+var sectionFormats = []string{"html", "md", "text"}
+
+// Views that can be picked with -section, named like their tabs
+var reportSectionTitles = []string{
+	"Overview", "Tech Profile", "Work Patterns", "Tool Usage", "Stacks",
+	"Security", "Config Health", "Time Sinks",
+}
+
+// A section title as it's given to -section, like "tech-profile"
+func sectionName(title string) string {
+	return strings.ToLower(strings.ReplaceAll(title, " ", "-"))
+}
+
+func checkSection(name string) error {
+	var names []string
+	for _, title := range reportSectionTitles {
+		if sectionName(title) == name {
+			return nil
+		}
+		names = append(names, sectionName(title))
+	}
+	return fmt.Errorf("%w %q, use one of %s", analyzer.ErrUnknownSection, name, strings.Join(names, ", "))
+}
+
+// The report sections, or only the one named, which may be empty when it
+// has nothing to show
+func selectSections(data analyzer.ShellData, name string) []reportSection {
+	sections := reportSections(data)
+	if name == "" {
+		return sections
+	}
+	for _, section := range sections {
+		if sectionName(section.Title) == name {
+			return []reportSection{section}
+		}
+	}
+	return nil
+}
+
+var htmlReportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta name="shell-analyser-schema-version" content="{{.SchemaVersion}}">
+<title>K8AU Shell Analyser Report</title>
+<style>
+body { font-family: sans-serif; background: #0d1117; color: #c9d1d9; margin: 2em; }
+pre { font-family: monospace; }
+table.calendar { border-spacing: 3px; }
+table.calendar td { width: 11px; height: 11px; border-radius: 2px; padding: 0; }
+</style>
+</head>
+<body>
+<h1>🚀 K8AU Shell Analyser</h1>
+<p>Generated {{.Generated}} · schema version {{.SchemaVersion}}</p>
+{{if .Calendar}}<h2>Activity Calendar</h2>
+{{.Calendar}}
+{{end}}{{range .Sections}}<h2>{{.Title}}</h2>
+<pre>{{.Content}}</pre>
+{{end}}</body>
+</html>
+`))
+
+// Write the report as a standalone HTML page
+func exportHTML(data analyzer.ShellData, cfg analyzer.Config, w io.Writer) error {
+	now := time.Now()
+
+	// The calendar belongs with Work Patterns
+	calendar := template.HTML("")
+	if cfg.Section == "" || cfg.Section == sectionName("Work Patterns") {
+		calendar = renderCalendarHTML(data.Insights.WorkPatterns.DailyActivity, cfg.Theme, now)
+	}
+	return htmlReportTemplate.Execute(w, struct {
+		SchemaVersion int
+		Generated     string
+		Calendar      template.HTML
+		Sections      []reportSection
+	}{
+		SchemaVersion: analyzer.SchemaVersion,
+		Generated:     now.Format(time.RFC1123),
+		Calendar:      calendar,
+		Sections:      selectSections(data, cfg.Section),
+	})
+}
+
+// Write the views as plain text, as they appear in the terminal, or only
+// the named one
+func exportText(data analyzer.ShellData, section string, w io.Writer) error {
+	var report strings.Builder
+	report.WriteString(fmt.Sprintf("K8AU Shell Analyser Report\nGenerated %s · schema version %d\n",
+		time.Now().Format(time.RFC1123), analyzer.SchemaVersion))
+	for _, section := range selectSections(data, section) {
+		report.WriteString("\n" + section.Content + "\n")
+	}
+	_, err := io.WriteString(w, report.String())
+	return err
+}
+
+// Write the views as a Markdown document, one heading per view, or only
+// the named one
+func exportMarkdown(data analyzer.ShellData, section string, w io.Writer) error {
+	var report strings.Builder
+	report.WriteString("# K8AU Shell Analyser Report\n\n")
+	report.WriteString(fmt.Sprintf("Generated %s · schema version %d\n",
+		time.Now().Format(time.RFC1123), analyzer.SchemaVersion))
+	for _, section := range selectSections(data, section) {
+		report.WriteString(fmt.Sprintf("\n## %s\n\n```\n%s\n```\n", section.Title, section.Content))
+	}
+	_, err := io.WriteString(w, report.String())
+	return err
+}
+
+// Write one row per distinct command, for spreadsheets and scripts
+func exportCSV(data analyzer.ShellData, w io.Writer) error {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"schema_version", "shell", "command", "timestamp", "duration_seconds", "categories", "count", "first_seen", "last_seen"})
+
+	var shells []string
+	for shell := range data.Histories {
+		shells = append(shells, shell)
+	}
+	sort.Strings(shells)
+
+	version := strconv.Itoa(analyzer.SchemaVersion)
+	for _, shell := range shells {
+		for _, entry := range data.Histories[shell] {
+			first, last := entry.Seen()
+			var categories []string
+			for _, match := range entry.Categories {
+				categories = append(categories, match.Name)
+			}
+			writer.Write([]string{
+				version,
+				shell,
+				entry.Command,
+				csvTime(entry.Timestamp),
+				strconv.FormatFloat(entry.Duration.Seconds(), 'f', -1, 64),
+				strings.Join(categories, ";"),
+				strconv.Itoa(entry.Runs()),
+				csvTime(first),
+				csvTime(last),
+			})
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// A time as RFC 3339, empty when unknown
+func csvTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// Write an export to a file, or to stdout when no path is given
+func writeExportFile(path string, export func(io.Writer) error) error {
+	if path == "" {
+		return export(os.Stdout)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := export(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
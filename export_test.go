@@ -0,0 +1,30 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"shell-analyzer/analyzer"
+)
+
+func TestExportJSONOmitConfigContents(t *testing.T) {
+	data := analyzer.NewShellData()
+	data.ShellConfigs["zsh"] = analyzer.ShellConfig{
+		ConfigFiles: map[string]analyzer.ConfigInfo{
+			".zshrc": {Path: "~/.zshrc", Content: "alias gs='git status'\n"},
+		},
+	}
+
+	for _, omit := range []bool{false, true} {
+		cfg := analyzer.DefaultConfig()
+		cfg.OmitConfigContents = omit
+		var export bytes.Buffer
+		if err := Export(data, "json", cfg, &export); err != nil {
+			t.Fatal(err)
+		}
+		if kept := strings.Contains(export.String(), "alias gs="); kept == omit {
+			t.Errorf("-omit-config-contents %v: raw config file contents kept = %v", omit, kept)
+		}
+	}
+}
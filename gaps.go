@@ -0,0 +1,24 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/gookit/color"
+
+	"shell-analyzer/analyzer"
+)
+
+// A quiet note for each gap, since they mean the analysis doesn't cover
+// those periods
+func renderHistoryGaps(gaps []analyzer.HistoryGap) string {
+	var content strings.Builder
+	for i, gap := range gaps {
+		if i == analyzer.MaxHistoryGaps {
+			content.WriteString(color.Gray.Sprintf("…and %d more gap(s)", len(gaps)-i) + "\n")
+			break
+		}
+		content.WriteString(color.Gray.Sprintf("History may be incomplete from %s to %s (%d days without commands)",
+			gap.From.Format(analyzer.DayLayout), gap.To.Format(analyzer.DayLayout), gap.Days()) + "\n")
+	}
+	return content.String()
+}
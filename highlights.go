@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Days this many standard deviations above the mean count as spikes
+const spikeDeviations = 2
+
+// Spikes listed in the Highlights section
+const maxSpikes = 5
+
+// DayHighlights are notable days picked out of the daily activity
+type DayHighlights struct {
+	Busiest       string
+	BusiestCount  int
+	Quietest      string
+	QuietestCount int
+	Spikes        []string
+}
+
+// Find the busiest and quietest active days and any unusual spikes. ok is
+// false when fewer than two days have activity.
+func findHighlights(daily map[string]int) (highlights DayHighlights, ok bool) {
+	var days []string
+	for day, count := range daily {
+		if count > 0 {
+			days = append(days, day)
+		}
+	}
+	if len(days) < 2 {
+		return highlights, false
+	}
+	// Dates sort chronologically, so ties go to the earliest day
+	sort.Strings(days)
+
+	var sum float64
+	for _, day := range days {
+		count := daily[day]
+		sum += float64(count)
+		if count > highlights.BusiestCount {
+			highlights.Busiest, highlights.BusiestCount = day, count
+		}
+		if highlights.Quietest == "" || count < highlights.QuietestCount {
+			highlights.Quietest, highlights.QuietestCount = day, count
+		}
+	}
+
+	mean := sum / float64(len(days))
+	var variance float64
+	for _, day := range days {
+		variance += math.Pow(float64(daily[day])-mean, 2)
+	}
+	stddev := math.Sqrt(variance / float64(len(days)))
+
+	for _, day := range days {
+		if stddev > 0 && float64(daily[day]) > mean+spikeDeviations*stddev {
+			highlights.Spikes = append(highlights.Spikes, day)
+		}
+	}
+
+	return highlights, true
+}
+
+func renderHighlights(daily map[string]int) string {
+	highlights, ok := findHighlights(daily)
+	if !ok {
+		return ""
+	}
+
+	var content strings.Builder
+	content.WriteString("🌟 Highlights:\n")
+	content.WriteString(fmt.Sprintf("Busiest day ever: %s (%d commands)\n", highlights.Busiest, highlights.BusiestCount))
+	content.WriteString(fmt.Sprintf("Quietest active day: %s (%d commands)\n", highlights.Quietest, highlights.QuietestCount))
+	if len(highlights.Spikes) > 0 {
+		spikes := highlights.Spikes
+		// Show only the most recent spikes
+		if len(spikes) > maxSpikes {
+			spikes = spikes[len(spikes)-maxSpikes:]
+		}
+		var parts []string
+		for _, day := range spikes {
+			parts = append(parts, fmt.Sprintf("%s (%d)", day, daily[day]))
+		}
+		content.WriteString(fmt.Sprintf("Unusual spikes: %s\n", strings.Join(parts, ", ")))
+	}
+	content.WriteString("\n")
+	return content.String()
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/gookit/color"
+
+	"shell-analyzer/analyzer"
+)
+
+func renderHints(hints []analyzer.Hint) string {
+	style := lipgloss.NewStyle().
+		BorderStyle(lipgloss.RoundedBorder()).
+		Padding(1)
+
+	var content strings.Builder
+	content.WriteString(color.Yellow.Sprintf("📝 Hints\n\n"))
+
+	if len(hints) == 0 {
+		content.WriteString("No hints for your most common commands\n")
+		return style.Render(content.String())
+	}
+
+	for _, hint := range hints {
+		content.WriteString(fmt.Sprintf("%s %s (%d run(s))\n", color.Cyan.Sprint(hint.Code), hint.Message, hint.Runs))
+		for _, example := range hint.Examples {
+			content.WriteString(color.Gray.Sprintf("  $ %s", example) + "\n")
+		}
+		content.WriteString("\n")
+	}
+	content.WriteString("Codes match shellcheck's; see https://www.shellcheck.net/wiki/ for details")
+
+	return style.Render(content.String())
+}
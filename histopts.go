@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Options that are on, sorted for display
+func enabledHistoryOptions(options map[string]bool) []string {
+	var enabled []string
+	for option, on := range options {
+		if on {
+			enabled = append(enabled, option)
+		}
+	}
+	sort.Strings(enabled)
+	return enabled
+}
+
+// historySetting is a history option worth turning on, with the config
+// line that does it
+type historySetting struct {
+	Line    string
+	Tip     string
+	Missing func(options map[string]bool) bool
+}
+
+var historySettings = map[string][]historySetting{
+	"zsh": {
+		{
+			Line: "setopt EXTENDED_HISTORY",
+			Tip:  "Enable EXTENDED_HISTORY (`setopt EXTENDED_HISTORY`) to record timestamps and durations and unlock time-based insights",
+			Missing: func(options map[string]bool) bool {
+				return !options["EXTENDED_HISTORY"]
+			},
+		},
+		{
+			Line: "setopt INC_APPEND_HISTORY",
+			Tip:  "Enable INC_APPEND_HISTORY so commands are saved as you run them instead of only when the shell exits",
+			Missing: func(options map[string]bool) bool {
+				return !options["INC_APPEND_HISTORY"] && !options["INC_APPEND_HISTORY_TIME"] && !options["SHARE_HISTORY"]
+			},
+		},
+	},
+	"bash": {
+		{
+			Line: "export HISTTIMEFORMAT='%F %T '",
+			Tip:  "Set HISTTIMEFORMAT (e.g. `export HISTTIMEFORMAT='%F %T '`) to record timestamps and unlock time-based insights",
+			Missing: func(options map[string]bool) bool {
+				return !options["HISTTIMEFORMAT"]
+			},
+		},
+		{
+			Line: "shopt -s histappend",
+			Tip:  "Enable histappend (`shopt -s histappend`) so shells append to the history file instead of overwriting each other's history",
+			Missing: func(options map[string]bool) bool {
+				return !options["histappend"]
+			},
+		},
+	},
+}
+
+// History settings a shell's config doesn't have yet
+func missingHistorySettings(shell string, options map[string]bool) []historySetting {
+	var missing []historySetting
+	for _, setting := range historySettings[shell] {
+		if setting.Missing(options) {
+			missing = append(missing, setting)
+		}
+	}
+	return missing
+}
+
+// Recommendations for history options that would improve what gets
+// recorded, and notes on options that skew the analysis
+func generateHistoryOptionTips(shell string, options map[string]bool) []string {
+	tips := []string{}
+	for _, setting := range missingHistorySettings(shell, options) {
+		tips = append(tips, setting.Tip)
+	}
+
+	switch shell {
+	case "zsh":
+		for _, option := range []string{"HIST_IGNORE_ALL_DUPS", "HIST_SAVE_NO_DUPS"} {
+			if options[option] {
+				tips = append(tips, fmt.Sprintf(
+					"%s drops repeated commands, so usage counts for your most common commands are undercounted", option))
+			}
+		}
+	case "bash":
+		if options["HISTCONTROL=erasedups"] {
+			tips = append(tips,
+				"HISTCONTROL=erasedups drops repeated commands, so usage counts for your most common commands are undercounted")
+		}
+	}
+
+	return tips
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Bar heights, from a single command up to the busiest hour
+var histogramLevels = []rune("▁▂▃▄▅▆▇█")
+
+// A 24-bar histogram of commands per hour of the day, from hours keyed
+// like "09" as in ShellData.TimePatterns. Hours without commands are left
+// blank, so a quiet hour looks different from a light one.
+func renderHourlyActivity(hours map[string]int) string {
+	var counts [24]int
+	busiest := 0
+	for hour := range counts {
+		counts[hour] = hours[fmt.Sprintf("%02d", hour)]
+		busiest = max(busiest, counts[hour])
+	}
+	if busiest == 0 {
+		return ""
+	}
+
+	var bars, axis strings.Builder
+	for hour, count := range counts {
+		bar := " "
+		if count > 0 {
+			level := (count*len(histogramLevels) - 1) / busiest
+			bar = string(histogramLevels[level])
+		}
+		// Two columns per hour so the bars line up with the labels
+		bars.WriteString(strings.Repeat(bar, 2))
+		if hour%3 == 0 {
+			axis.WriteString(fmt.Sprintf("%-6s", fmt.Sprintf("%02d", hour)))
+		}
+	}
+
+	return fmt.Sprintf("🕒 Commands by Hour:\n%s\n%s\n\n", bars.String(), strings.TrimRight(axis.String(), " "))
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderHourlyActivity(t *testing.T) {
+	if got := renderHourlyActivity(map[string]int{}); got != "" {
+		t.Errorf("no timestamps rendered %q, want nothing", got)
+	}
+
+	lines := strings.Split(renderHourlyActivity(map[string]int{"00": 1, "09": 8, "14": 4}), "\n")
+	bars := []rune(lines[1])
+	if len(bars) != 48 {
+		t.Fatalf("bars = %q, want two columns for each of 24 hours", lines[1])
+	}
+	for hour, want := range map[int]rune{0: '▁', 9: '█', 14: '▄', 3: ' ', 23: ' '} {
+		if got := bars[hour*2]; got != want || bars[hour*2+1] != want {
+			t.Errorf("hour %02d bar = %q, want %q", hour, got, want)
+		}
+	}
+	if !strings.HasPrefix(lines[2], "00    03    06") || !strings.HasSuffix(lines[2], "21") {
+		t.Errorf("axis = %q", lines[2])
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"shell-analyzer/analyzer"
+)
+
+// Make a file unreadable. Root reads files whatever their mode, so there
+// it's replaced with a symlink to itself, which nobody can open.
+func makeUnreadable(t *testing.T, path string) {
+	t.Helper()
+	if err := os.Chmod(path, 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.ReadFile(path); err == nil {
+		if err := os.Remove(path); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Symlink(filepath.Base(path), path); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestAnalyzeReportsUnreadableConfigs(t *testing.T) {
+	home := writeTestHome(t, map[string]string{
+		".zsh_history": ": 1712912400:0;git status\n",
+		".zshrc":       "alias gs='git status'\n",
+	})
+	makeUnreadable(t, filepath.Join(home, ".zshrc"))
+
+	data, err := analyzer.Analyze(analyzer.Options{HomeDir: home, Shells: []string{"zsh"}, NoProbe: true, KeepEntries: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data.Histories["zsh"]) != 1 {
+		t.Errorf("history = %v, want it analyzed despite the config", data.Histories["zsh"])
+	}
+	if !strings.Contains(renderDiagnostics(data), ".zshrc") {
+		t.Error("Diagnostics doesn't name the unreadable .zshrc")
+	}
+	if !strings.Contains(renderOverview(data, ""), "1 config file(s) couldn't be read") {
+		t.Error("Overview doesn't note the unreadable config")
+	}
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gookit/color"
+
+	"shell-analyzer/analyzer"
+)
+
+func renderToolLifecycle(lifecycle []analyzer.ToolLifecycle) string {
+	var content strings.Builder
+	content.WriteString("♻️  Tool Lifecycle:\n")
+	if len(lifecycle) == 0 {
+		content.WriteString("No timestamped history available\n")
+		return content.String()
+	}
+
+	statuses := []struct {
+		status string
+		label  string
+		paint  color.Color
+	}{
+		{analyzer.StatusNew, "New", color.Green},
+		{analyzer.StatusActive, "Active", color.Cyan},
+		{analyzer.StatusDeclining, "Declining", color.Yellow},
+		{analyzer.StatusAbandoned, "Abandoned", color.Gray},
+	}
+
+	for _, s := range statuses {
+		var names []string
+		for _, tool := range lifecycle {
+			if tool.Status != s.status {
+				continue
+			}
+			// Show only the top 8 of each status
+			if len(names) == 8 {
+				break
+			}
+			switch s.status {
+			case analyzer.StatusNew:
+				names = append(names, fmt.Sprintf("%s (since %s)", tool.Name, tool.First.Format(analyzer.DayLayout)))
+			case analyzer.StatusAbandoned:
+				names = append(names, fmt.Sprintf("%s (last %s)", tool.Name, tool.Last.Format(analyzer.DayLayout)))
+			default:
+				names = append(names, tool.Name)
+			}
+		}
+		if len(names) > 0 {
+			content.WriteString(fmt.Sprintf("%s %s\n", s.paint.Sprintf("%-10s", s.label+":"), strings.Join(names, ", ")))
+		}
+	}
+
+	return content.String()
+}
+
+// New tools split into those being learned, ordered by how quickly their
+// use ramped up, and those only tried a few times
+func adoptionVelocity(lifecycle []analyzer.ToolLifecycle) (learning, tried []analyzer.ToolLifecycle) {
+	for _, tool := range lifecycle {
+		if tool.Status != analyzer.StatusNew {
+			continue
+		}
+		if tool.Count >= analyzer.MinLearningUses {
+			learning = append(learning, tool)
+		} else {
+			tried = append(tried, tool)
+		}
+	}
+	sort.SliceStable(learning, func(i, j int) bool {
+		return learning[i].PerWeek > learning[j].PerWeek
+	})
+	return learning, tried
+}
+
+func renderCurrentlyLearning(lifecycle []analyzer.ToolLifecycle) string {
+	learning, tried := adoptionVelocity(lifecycle)
+	if len(learning) == 0 && len(tried) == 0 {
+		return ""
+	}
+
+	var content strings.Builder
+	content.WriteString("📈 Currently Learning:\n")
+	if len(learning) == 0 {
+		content.WriteString(fmt.Sprintf("No new tool used %d or more times yet\n", analyzer.MinLearningUses))
+	}
+	maxRate := 0.0
+	if len(learning) > 0 {
+		maxRate = learning[0].PerWeek
+	}
+	for i, tool := range learning {
+		// Show only the top 8
+		if i == 8 {
+			break
+		}
+		bars := int(tool.PerWeek / maxRate * 20)
+		barStr := strings.Repeat("█", bars) + strings.Repeat("░", 20-bars)
+		content.WriteString(fmt.Sprintf("%-15s: %s %.1f/week since %s\n", tool.Name, barStr, tool.PerWeek, tool.First.Format(analyzer.DayLayout)))
+	}
+
+	var names []string
+	for _, tool := range tried {
+		if len(names) == 8 {
+			break
+		}
+		names = append(names, fmt.Sprintf("%s (%d uses)", tool.Name, tool.Count))
+	}
+	if len(names) > 0 {
+		content.WriteString(color.Gray.Sprint("Just tried: "+strings.Join(names, ", ")) + "\n")
+	}
+	return content.String()
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	// How often the loading screen checks how far the analysis got
+	loadingTickInterval = 100 * time.Millisecond
+
+	// The progress bar's width on wide terminals, and the columns left
+	// beside it on narrow ones
+	progressMaxWidth = 40
+	progressMargin   = 4
+)
+
+// Sent while loading to move the progress bar along
+type loadingTickMsg struct{}
+
+func loadingTick() tea.Cmd {
+	return tea.Tick(loadingTickInterval, func(time.Time) tea.Msg {
+		return loadingTickMsg{}
+	})
+}
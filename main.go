@@ -1,173 +1,351 @@
 package main
 
 import (
-	"bufio"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"log"
 	"os"
-	"os/exec"
-	"path/filepath"
-	"regexp"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/charmbracelet/bubbles/paginator"
 	"github.com/charmbracelet/bubbles/progress"
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/gookit/color"
-)
-
-// Basic data structures
-type ShellData struct {
-	Histories    map[string][]CommandEntry
-	CommonCmds   map[string]int
-	TimePatterns map[string]int
-	Insights     DetailedInsights
-	ShellConfigs map[string]ShellConfig
-}
-
-type CommandEntry struct {
-	Command    string
-	Timestamp  time.Time
-	Count      int
-	Categories []string
-}
-
-type DetailedInsights struct {
-	TechnicalProfile TechProfile
-	WorkPatterns     WorkPatterns
-	ToolUsage        ToolUsage
-}
-
-type TechProfile struct {
-	PrimaryRole     string
-	SecondarySkills []string
-	TechStack       []string
-	Proficiency     map[string]float64
-}
 
-type WorkPatterns struct {
-	PeakHours       []int
-	CommonWorkflows []string
-	Productivity    map[string]float64
-}
-
-type ToolUsage struct {
-	Editors    map[string]int
-	Languages  map[string]int
-	BuildTools map[string]int
-}
+	"shell-analyzer/analyzer"
+)
 
 type Logger struct {
 	Info  *log.Logger
 	Error *log.Logger
 }
 
-type ShellConfig struct {
-	ConfigFiles map[string]ConfigInfo
-	Plugins     []PluginInfo
-	Aliases     map[string]string
-	Environment map[string]string
-}
-
-type ConfigInfo struct {
-	Path     string
-	Modified time.Time
-	Content  string
-}
-
-type PluginInfo struct {
-	Name        string
-	Source      string
-	LastUpdated time.Time
-}
-
 // Model implementation
 type Model struct {
 	viewport    viewport.Model
 	progress    progress.Model
+	spinner     spinner.Model
 	loading     bool
+	analysis    *analyzer.AnalysisProgress
+	started     time.Time
 	err         error
-	shellData   ShellData
+	shellData   analyzer.ShellData
 	currentView string
 	tabs        []string
 	activeTab   int
 	logger      Logger
+	config      analyzer.Config
+	errors      []error
+
+	// Snapshot options
+	takeSnapshot  bool
+	keepSnapshots int
+
+	// Data loaded with -import, shown instead of analyzing this machine
+	imported *analyzer.ShellData
+
+	// Rendered once per analysis, since View runs on every event
+	// including mouse motion
+	techProfileView string
+
+	// Show proficiency relative to the top language instead of as a
+	// share of all commands, toggled with r
+	relativeProficiency bool
+
+	// Terminal size, from the last WindowSizeMsg
+	width  int
+	height int
+
+	// Show all main sections at once instead of tabs
+	dashboard bool
+
+	// Category the Overview is narrowed to, empty for all
+	categoryFilter string
+
+	// Tips and the one selected in the Tips view
+	tips        []Tip
+	selectedTip int
+
+	// Saved query selected in the Queries view
+	selectedQuery int
+
+	// Every distinct command and the live filter over it in the Commands
+	// view. searchSeq numbers keystrokes so only the last one filters,
+	// and commandPages is the page of results shown.
+	commandList   []commandCount
+	searchInput   textinput.Model
+	searchSeq     int
+	searchQuery   string
+	searchResults []commandCount
+	commandPages  paginator.Model
 }
 
-func initShellData() ShellData {
-	return ShellData{
-		Histories:    make(map[string][]CommandEntry),
-		CommonCmds:   make(map[string]int),
-		TimePatterns: make(map[string]int),
-		Insights: DetailedInsights{
-			TechnicalProfile: TechProfile{
-				Proficiency: make(map[string]float64),
-			},
-			WorkPatterns: WorkPatterns{
-				Productivity: make(map[string]float64),
-			},
-			ToolUsage: ToolUsage{
-				Editors:    make(map[string]int),
-				Languages:  make(map[string]int),
-				BuildTools: make(map[string]int),
-			},
-		},
-		ShellConfigs: make(map[string]ShellConfig),
-	}
-}
+func initialModel(config analyzer.Config) Model {
+	var errs []error
 
-func initialModel() Model {
-	// Create log file
+	// Create log file, discarding logs if it can't be opened. Everything
+	// logged is redacted first so the log can't leak secrets.
+	var logOutput io.Writer = io.Discard
 	logFile, err := os.OpenFile("shell_analyzer.log", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0666)
 	if err != nil {
-		log.Fatal(err)
+		errs = append(errs, analyzer.FileError("shell_analyzer.log", err))
+	} else {
+		logOutput = analyzer.NewRedactingWriter(logFile, config)
 	}
 
 	logger := Logger{
-		Info:  log.New(logFile, "INFO: ", log.Ldate|log.Ltime|log.Lshortfile),
-		Error: log.New(logFile, "ERROR: ", log.Ldate|log.Ltime|log.Lshortfile),
+		Info:  log.New(logOutput, "INFO: ", log.Ldate|log.Ltime|log.Lshortfile),
+		Error: log.New(logOutput, "ERROR: ", log.Ldate|log.Ltime|log.Lshortfile),
 	}
 
-	tabs := []string{"Overview", "Tech Profile", "Work Patterns", "Tool Usage"}
+	tabs := []string{"Overview", "Commands", "Tech Profile", "Work Patterns", "Tool Usage", "Pipelines", "Complexity", "Stacks", "Projects", "Security", "Hints", "Config Health", "Config Drift", "Tips", "Recommendations", "Queries", "Diagnostics"}
 
 	return Model{
-		viewport:    viewport.New(100, 30),
-		progress:    progress.New(progress.WithDefaultGradient()),
-		loading:     true,
-		currentView: "main",
-		tabs:        tabs,
-		activeTab:   0,
-		shellData:   initShellData(),
-		logger:      logger,
+		viewport:     viewport.New(100, 30),
+		progress:     progress.New(progress.WithDefaultGradient()),
+		spinner:      spinner.New(spinner.WithSpinner(spinner.Dot), spinner.WithStyle(lipgloss.NewStyle().Foreground(lipgloss.Color("86")))),
+		loading:      true,
+		analysis:     &analyzer.AnalysisProgress{},
+		started:      time.Now(),
+		currentView:  "main",
+		tabs:         tabs,
+		activeTab:    0,
+		shellData:    analyzer.NewShellData(),
+		logger:       logger,
+		config:       config,
+		errors:       errs,
+		searchInput:  newSearchInput(),
+		commandPages: newCommandPaginator(),
 	}
 }
 
 // Implement tea.Model interface
 func (m Model) Init() tea.Cmd {
+	analysis := analyzeShells(m.config, m.analysis)
+	if m.imported == nil {
+		for _, source := range analyzer.HistorySources(analyzer.Options{Config: &m.config}) {
+			m.logger.Info.Printf("Reading %s history from %s", source.Name(), source.Location())
+		}
+	} else {
+		data := *m.imported
+		analysis = func() tea.Msg { return data }
+	}
+
 	return tea.Batch(
-		analyzeShells,
+		analysis,
+		m.spinner.Tick,
+		loadingTick(),
 		tea.EnterAltScreen,
 	)
 }
 
+// Scrolling only moves the viewport over the content it already has; any
+// other message may change what the active tab shows, so its content is
+// set again
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if m.isScroll(msg) {
+		return m.scroll(msg)
+	}
+	m, cmd := m.update(msg)
+	switch msg.(type) {
+	case spinner.TickMsg, loadingTickMsg, progress.FrameMsg:
+		// Animation frames leave the tabs as they are
+	default:
+		m.syncViewport()
+	}
+	return m, cmd
+}
+
+func (m Model) update(msg tea.Msg) (Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		// While typing in the search box, keys go to it
+		if m.searchInput.Focused() {
+			switch msg.String() {
+			case "ctrl+c":
+				return m, tea.Quit
+			case "esc":
+				m.searchInput.Blur()
+				return m, nil
+			case "enter":
+				m.searchInput.Blur()
+				m.searchQuery = m.searchInput.Value()
+				m.setSearchResults(filterCommands(m.commandList, m.searchQuery))
+				return m, nil
+			}
+			var cmd tea.Cmd
+			m.searchInput, cmd = m.searchInput.Update(msg)
+			m.searchSeq++
+			return m, tea.Batch(cmd, debounceSearch(m.searchSeq))
+		}
+
 		switch msg.String() {
 		case "q", "ctrl+c":
 			return m, tea.Quit
 		case "tab":
 			m.activeTab = (m.activeTab + 1) % len(m.tabs)
+			m.viewport.GotoTop()
+			return m, nil
+		case "d":
+			m.dashboard = !m.dashboard
+			return m, nil
+		}
+
+		// Number keys narrow the Overview to one category, pressing the
+		// same key again or 0 shows everything
+		if m.tabs[m.activeTab] == "Overview" || m.showDashboard() {
+			key := msg.String()
+			for i, category := range overviewCategories {
+				if key == strconv.Itoa(i+1) {
+					if m.categoryFilter == category {
+						category = ""
+					}
+					m.categoryFilter = category
+					return m, nil
+				}
+			}
+			if key == "0" {
+				m.categoryFilter = ""
+				return m, nil
+			}
+		}
+
+		if (m.tabs[m.activeTab] == "Tech Profile" || m.showDashboard()) && msg.String() == "r" {
+			m.relativeProficiency = !m.relativeProficiency
+			m.techProfileView = renderTechProfile(m.shellData.Insights.TechnicalProfile, m.relativeProficiency)
+			return m, nil
+		}
+
+		if m.tabs[m.activeTab] == "Tips" && len(m.tips) > 0 {
+			switch msg.String() {
+			case "up", "k":
+				if m.selectedTip > 0 {
+					m.selectedTip--
+				}
+				return m, nil
+			case "down", "j":
+				if m.selectedTip < len(m.tips)-1 {
+					m.selectedTip++
+				}
+				return m, nil
+			case "e":
+				if path := m.tips[m.selectedTip].ConfigPath; path != "" {
+					return m, openInEditor(path)
+				}
+				return m, nil
+			}
+		}
+
+		if m.tabs[m.activeTab] == "Commands" {
+			switch msg.String() {
+			case "/":
+				return m, m.searchInput.Focus()
+			case "esc":
+				m.searchInput.SetValue("")
+				m.searchQuery = ""
+				m.setSearchResults(m.commandList)
+				return m, nil
+			}
+			var cmd tea.Cmd
+			m.commandPages, cmd = m.commandPages.Update(msg)
+			return m, cmd
+		}
+
+		if m.tabs[m.activeTab] == "Queries" {
+			switch msg.String() {
+			case "up", "k":
+				if m.selectedQuery > 0 {
+					m.selectedQuery--
+				}
+				return m, nil
+			case "down", "j":
+				if m.selectedQuery < len(m.config.SavedQueries)-1 {
+					m.selectedQuery++
+				}
+				return m, nil
+			}
+		}
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.progress.Width = min(max(msg.Width-progressMargin, 1), progressMaxWidth)
+	case searchDebounceMsg:
+		if msg.seq == m.searchSeq && m.searchInput.Value() != m.searchQuery {
+			m.searchQuery = m.searchInput.Value()
+			m.setSearchResults(filterCommands(m.commandList, m.searchQuery))
+		}
+		return m, nil
+	case editorFinishedMsg:
+		if msg.err != nil {
+			err := editorError(msg.path, msg.err)
+			m.errors = append(m.errors, err)
+			m.logger.Error.Printf("%v", err)
+		}
+		return m, nil
+	case spinner.TickMsg:
+		if !m.loading {
 			return m, nil
 		}
-	case ShellData:
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+	case loadingTickMsg:
+		if !m.loading {
+			return m, nil
+		}
+		return m, tea.Batch(m.progress.SetPercent(m.analysis.Percent()), loadingTick())
+	case progress.FrameMsg:
+		model, cmd := m.progress.Update(msg)
+		m.progress = model.(progress.Model)
+		return m, cmd
+	case refreshMsg:
+		return m, analyzeShells(m.config, nil)
+	case analyzer.ShellData:
 		m.loading = false
+		m.progress.SetPercent(1)
 		m.shellData = msg
+		m.techProfileView = renderTechProfile(msg.Insights.TechnicalProfile, m.relativeProficiency)
+		m.tips = generateTips(msg, m.config.CompanionRules)
+		m.selectedTip = min(m.selectedTip, max(len(m.tips)-1, 0))
+		m.commandList = buildCommandList(msg)
+
+		// Stay on the same page when a refresh brings in new commands
+		page := m.commandPages.Page
+		m.setSearchResults(filterCommands(m.commandList, m.searchQuery))
+		m.commandPages.Page = min(page, max(m.commandPages.TotalPages-1, 0))
+
+		fresh := newErrors(m.errors, msg.Errors)
+		m.errors = append(m.errors, fresh...)
 		m.logger.Info.Printf("Shell analysis completed. Found %d shell histories", len(msg.Histories))
-		return m, nil
+		for _, err := range fresh {
+			m.logger.Error.Printf("%v", err)
+		}
+
+		if m.takeSnapshot {
+			dir := m.config.ExpandPath(analyzer.SnapshotDir)
+			if err := analyzer.WriteSnapshot(dir, analyzer.NewSnapshot(msg, time.Now()), m.keepSnapshots); err != nil {
+				m.logger.Error.Printf("Failed to save snapshot: %v", err)
+			} else {
+				m.logger.Info.Printf("Saved snapshot to %s", dir)
+			}
+			// Once per run, not on every refresh
+			m.takeSnapshot = false
+		}
+
+		// Imported data never changes
+		if m.imported != nil {
+			return m, nil
+		}
+		return m, scheduleRefresh(m.config.RefreshInterval())
 	}
 
 	var cmd tea.Cmd
@@ -176,8 +354,34 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m Model) View() string {
+	header := clipWidth(renderHeader(), m.width)
+	if m.loading {
+		return header + "\n" + renderLoading(m.spinner.View(), m.progress.View(), time.Since(m.started))
+	}
+
+	status := renderStatusLine(m.errors, len(m.shellData.Histories))
+	if m.showDashboard() {
+		return fmt.Sprintf("%s\n%s%s%s", header, renderDashboard(m), status, m.renderFooter())
+	}
+
+	// The viewport holds the active tab's content once the terminal size
+	// is known, set by Update
+	content := m.viewport.View()
+	if m.viewportHeight() == 0 {
+		content = m.tabContent()
+	}
+
+	return fmt.Sprintf("%s\n%s\n%s%s%s",
+		header,
+		renderTabs(m.tabs, m.activeTab, m.width),
+		content,
+		status,
+		m.renderFooter())
+}
+
+func renderHeader() string {
 	// Minimalist header with updated name
-	header := lipgloss.NewStyle().
+	return lipgloss.NewStyle().
 		Bold(true).
 		Foreground(lipgloss.Color("86")).
 		Border(lipgloss.RoundedBorder()).
@@ -186,46 +390,97 @@ func (m Model) View() string {
 🚀 K8AU SHELL ANALYSER				 
 Shell Analytics & Configuration Tool
 `)
+}
 
-	if m.loading {
-		return header + "\n" + renderLoading()
+func (m Model) renderFooter() string {
+	hints := "Press 'q' to quit • Use 'tab' to switch tabs • 'd' dashboard • "
+	if m.tabs[m.activeTab] == "Overview" || m.showDashboard() {
+		hints += "1-9 filter by category, 0 for all • "
+	}
+	if m.tabs[m.activeTab] == "Tech Profile" || m.showDashboard() {
+		hints += "'r' relative proficiency • "
+	}
+	if !m.showDashboard() {
+		hints += "↑/↓ pgup/pgdown scroll • "
+	}
+	style := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("241"))
+	if m.width > 0 {
+		// Wrap on narrow terminals rather than run off the edge
+		style = style.Width(m.width)
 	}
+	return "\n\n" + style.Render(hints+"By Ksauraj")
+}
 
+// The active tab's view
+func (m Model) tabContent() string {
 	var content string
 	switch m.tabs[m.activeTab] {
 	case "Overview":
-		content = renderOverview(m.shellData)
+		content = renderOverview(m.shellData, m.categoryFilter)
+	case "Commands":
+		content = renderSearch(m.searchInput, m.searchResults, m.searchQuery, m.commandPages)
 	case "Tech Profile":
-		content = renderTechProfile(m.shellData.Insights.TechnicalProfile)
+		content = m.techProfileView
 	case "Work Patterns":
-		content = renderWorkPatterns(m.shellData.Insights.WorkPatterns)
+		content = renderWorkPatterns(m.shellData.Insights.WorkPatterns, m.shellData.TimePatterns)
+		if calendar := renderCalendar(m.shellData.Insights.WorkPatterns.DailyActivity, m.config.Theme, time.Now()); calendar != "" {
+			content += "\n" + calendar
+		}
+		if sinks := renderTimeSinks(m.shellData.Insights.WorkPatterns.TimeSinks); sinks != "" {
+			content += "\n" + sinks
+		}
 	case "Tool Usage":
 		content = renderToolUsage(m.shellData.Insights.ToolUsage)
+	case "Pipelines":
+		content = renderPipelines(m.shellData.Insights.ToolUsage.Pipelines)
+	case "Complexity":
+		content = renderComplexity(m.shellData.Insights.Complexity, m.shellData.Insights.Substitutions)
+	case "Stacks":
+		content = renderStacks(m.shellData.Insights.TechnicalProfile.StackUsage)
+	case "Projects":
+		content = renderProjects(m.shellData.Insights.Projects, m.shellData.Insights.PrivateProjects)
+	case "Security":
+		content = renderSecurity(m.shellData)
+	case "Hints":
+		content = renderHints(m.shellData.Insights.Hints)
+	case "Config Health":
+		content = renderConfigHealth(m.shellData)
+	case "Config Drift":
+		content = renderConfigDrift(m.shellData.ConfigDrift)
+	case "Tips":
+		content = renderTips(m.tips, m.selectedTip)
+	case "Recommendations":
+		content = renderRecommendations(m.shellData)
+	case "Queries":
+		content = renderQueries(m.shellData, m.config.SavedQueries, m.selectedQuery, time.Now())
+	case "Diagnostics":
+		data := m.shellData
+		data.Errors = m.errors
+		content = renderDiagnostics(data)
 	}
-
-	// Add footer
-	footer := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("241")).
-		Render("\n\nPress 'q' to quit • Use 'tab' to switch tabs • By Ksauraj")
-
-	return fmt.Sprintf("%s\n%s\n%s%s",
-		header,
-		renderTabs(m.tabs, m.activeTab),
-		content,
-		footer)
+	return content
 }
 
 // Render functions
-func renderLoading() string {
-	return lipgloss.NewStyle().
+func renderLoading(spin, bar string, elapsed time.Duration) string {
+	message := lipgloss.NewStyle().
 		Bold(true).
 		Foreground(lipgloss.Color("86")).
 		Render("Analyzing your shell history... 🔍")
-}
 
-func renderTabs(tabs []string, active int) string {
-	var tabsDisplay strings.Builder
+	timer := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("241")).
+		Render(fmt.Sprintf("%s elapsed", elapsed.Truncate(100*time.Millisecond)))
 
+	return fmt.Sprintf("%s %s %s\n\n%s", spin, message, timer, bar)
+}
+
+// Render the tab bar. When it's wider than width, only the tabs around the
+// active one are shown, with arrows marking the hidden ones.
+func renderTabs(tabs []string, active int, width int) string {
+	rendered := make([]string, len(tabs))
+	total := 0
 	for i, tab := range tabs {
 		style := lipgloss.NewStyle().
 			Padding(0, 2)
@@ -237,23 +492,104 @@ func renderTabs(tabs []string, active int) string {
 				Foreground(lipgloss.Color("15"))
 		}
 
-		tabsDisplay.WriteString(style.Render(tab))
+		rendered[i] = style.Render(tab)
+		total += lipgloss.Width(rendered[i])
 	}
 
-	return tabsDisplay.String()
+	if width <= 0 || total <= width {
+		return strings.Join(rendered, "")
+	}
+
+	// Room for the arrows on both sides
+	room := width - 2
+	start, end := active, active+1
+	used := lipgloss.Width(rendered[active])
+	for start > 0 && used+lipgloss.Width(rendered[start-1]) <= room {
+		start--
+		used += lipgloss.Width(rendered[start])
+	}
+	for end < len(rendered) && used+lipgloss.Width(rendered[end]) <= room {
+		used += lipgloss.Width(rendered[end])
+		end++
+	}
+
+	var tabsDisplay strings.Builder
+	if start > 0 {
+		tabsDisplay.WriteString("‹")
+	}
+	tabsDisplay.WriteString(strings.Join(rendered[start:end], ""))
+	if end < len(rendered) {
+		tabsDisplay.WriteString("›")
+	}
+	return clipWidth(tabsDisplay.String(), width)
 }
 
-func renderOverview(data ShellData) string {
+// Categories selectable with the number keys in the Overview
+var overviewCategories = []string{"development", "system", "file", "network", "containers", "cloud", "editing", "search", "version-control"}
+
+func renderOverview(data analyzer.ShellData, filter string) string {
 	style := lipgloss.NewStyle().
 		BorderStyle(lipgloss.RoundedBorder()).
 		Padding(1)
 
 	var content strings.Builder
 	content.WriteString(color.Green.Sprintf("📊 Shell Usage Overview\n\n"))
+	content.WriteString(renderSkipped(data.Skipped, analyzer.SkippedHistory))
+	content.WriteString(renderSkipped(data.Skipped, analyzer.SkippedConfig))
+
+	var shells []string
+	for shell := range data.Histories {
+		shells = append(shells, shell)
+	}
+	for _, shell := range primaryFirst(shells, data.PrimaryShell) {
+		history := data.Histories[shell]
+		badge := ""
+		if shell == data.PrimaryShell && len(data.Histories) > 1 {
+			badge = " " + color.Yellow.Sprint("⭐ primary")
+		}
+		content.WriteString(fmt.Sprintf("Shell: %s%s\n", color.Cyan.Sprint(shell), badge))
+		runs := 0
+		for _, entry := range history {
+			runs += entry.Runs()
+		}
+		content.WriteString(fmt.Sprintf("Commands: %d\n", runs))
+		if dropped := data.DroppedCommands[shell]; dropped > 0 {
+			content.WriteString(color.Gray.Sprintf("%d older commands only counted in totals (max_entries)", dropped) + "\n")
+		}
+		content.WriteString(renderHistoryGaps(data.HistoryGaps[shell]))
 
-	for shell, history := range data.Histories {
-		content.WriteString(fmt.Sprintf("Shell: %s\n", color.Cyan.Sprint(shell)))
-		content.WriteString(fmt.Sprintf("Commands: %d\n", len(history)))
+		// How repetitive the shell's usage is
+		if variety, ok := commandVariety(history); ok {
+			bars := int(variety * 20)
+			bars = min(max(bars, 0), 20)
+			barStr := strings.Repeat("█", bars) + strings.Repeat("░", 20-bars)
+			content.WriteString(fmt.Sprintf("Variety: %s %.2f (%s)\n", barStr, variety, describeVariety(variety)))
+		}
+
+		// Weighted category shares
+		if breakdown := categoryBreakdown(history); len(breakdown) > 0 {
+			var total float64
+			var names []string
+			for name, weight := range breakdown {
+				total += weight
+				names = append(names, name)
+			}
+			sort.Slice(names, func(i, j int) bool {
+				return breakdown[names[i]] > breakdown[names[j]]
+			})
+			var shares []string
+			for _, name := range names {
+				shares = append(shares, fmt.Sprintf("%s %.0f%%", name, breakdown[name]/total*100))
+			}
+			content.WriteString(fmt.Sprintf("Categories: %s\n", strings.Join(shares, " · ")))
+		}
+		if network := renderNetworkActivity(history); network != "" {
+			content.WriteString(network)
+		}
+
+		if filter != "" {
+			content.WriteString(renderCategoryCommands(history, filter))
+		}
 
 		// Add shell configuration information
 		if config, exists := data.ShellConfigs[shell]; exists {
@@ -261,6 +597,9 @@ func renderOverview(data ShellData) string {
 			content.WriteString(fmt.Sprintf("• Aliases: %d\n", len(config.Aliases)))
 			content.WriteString(fmt.Sprintf("• Plugins: %d\n", len(config.Plugins)))
 			content.WriteString(fmt.Sprintf("• Environment Variables: %d\n", len(config.Environment)))
+			if len(config.Unreadable) > 0 {
+				content.WriteString(color.Gray.Sprintf("%d config file(s) couldn't be read, see Diagnostics", len(config.Unreadable)) + "\n")
+			}
 
 			// List plugins if any
 			if len(config.Plugins) > 0 {
@@ -290,10 +629,49 @@ func renderOverview(data ShellData) string {
 		content.WriteString("\n")
 	}
 
+	content.WriteString(renderTopCommands(data.CommonCmds))
+
 	return style.Render(content.String())
 }
 
-func renderTechProfile(profile TechProfile) string {
+// Commands listed under Top Commands in the Overview
+const maxTopCommands = 15
+
+// The most run commands across all shells, with bars relative to the top one
+func renderTopCommands(counts map[string]int) string {
+	if len(counts) == 0 {
+		return ""
+	}
+
+	var cmds []string
+	for cmd := range counts {
+		cmds = append(cmds, cmd)
+	}
+	sort.Slice(cmds, func(i, j int) bool {
+		if counts[cmds[i]] != counts[cmds[j]] {
+			return counts[cmds[i]] > counts[cmds[j]]
+		}
+		return cmds[i] < cmds[j]
+	})
+	if len(cmds) > maxTopCommands {
+		cmds = cmds[:maxTopCommands]
+	}
+
+	var content strings.Builder
+	content.WriteString("🏆 Top Commands:\n")
+	top := counts[cmds[0]]
+	for _, cmd := range cmds {
+		bars := counts[cmd] * 20 / top
+		bars = min(max(bars, 0), 20)
+		barStr := strings.Repeat("█", bars) + strings.Repeat("░", 20-bars)
+		content.WriteString(fmt.Sprintf("%-15s %s %d\n", cmd, barStr, counts[cmd]))
+	}
+	return content.String()
+}
+
+// Proficiency bars show each language's share of all commands, or with
+// relative set, its usage compared to the top language
+func renderTechProfile(profile analyzer.TechProfile, relative bool) string {
 	style := lipgloss.NewStyle().
 		BorderStyle(lipgloss.RoundedBorder()).
 		Padding(1)
@@ -332,7 +710,11 @@ func renderTechProfile(profile TechProfile) string {
 	content.WriteString("\n")
 
 	// Proficiency Levels
-	content.WriteString("📊 Proficiency Levels:\n")
+	if relative {
+		content.WriteString("📊 Proficiency Levels (relative to your top language):\n")
+	} else {
+		content.WriteString("📊 Proficiency Levels:\n")
+	}
 	if len(profile.Proficiency) > 0 {
 		// Sort proficiencies for consistent display
 		var items []struct {
@@ -350,14 +732,22 @@ func renderTechProfile(profile TechProfile) string {
 			return items[i].Level > items[j].Level
 		})
 
+		top := items[0].Level
 		for _, item := range items {
-			bars := int(item.Level * 20)
-			if bars < 0 {
-				bars = 0
+			level := item.Level
+			if relative && top > 0 {
+				level /= top
 			}
+			bars := int(level * 20)
+			bars = min(max(bars, 0), 20)
 			barStr := strings.Repeat("█", bars) + strings.Repeat("░", 20-bars)
-			content.WriteString(fmt.Sprintf("%-15s %s %.1f%%\n",
-				item.Name, barStr, item.Level*100))
+			if relative && top > 0 {
+				content.WriteString(fmt.Sprintf("%-15s %s %.0f%% (%.1f%% of commands)\n",
+					item.Name, barStr, level*100, item.Level*100))
+			} else {
+				content.WriteString(fmt.Sprintf("%-15s %s %.1f%%\n",
+					item.Name, barStr, item.Level*100))
+			}
 		}
 	} else {
 		content.WriteString("No proficiency data available\n")
@@ -366,7 +756,8 @@ func renderTechProfile(profile TechProfile) string {
 	return style.Render(content.String())
 }
 
-func renderWorkPatterns(patterns WorkPatterns) string {
+// hours is the per-hour command count from ShellData.TimePatterns
+func renderWorkPatterns(patterns analyzer.WorkPatterns, hours map[string]int) string {
 	style := lipgloss.NewStyle().
 		BorderStyle(lipgloss.RoundedBorder()).
 		Padding(1)
@@ -381,25 +772,62 @@ func renderWorkPatterns(patterns WorkPatterns) string {
 	}
 	content.WriteString("\n")
 
+	// Commands per hour, when there are timestamps
+	content.WriteString(renderHourlyActivity(hours))
+
+	// Notable days, when there are timestamps
+	content.WriteString(renderHighlights(patterns.DailyActivity))
+
 	// Productivity Metrics
 	content.WriteString("📈 Productivity Metrics:\n")
 	for metric, value := range patterns.Productivity {
 		bars := int(value * 20)
+		bars = min(max(bars, 0), 20)
 		barStr := strings.Repeat("█", bars) + strings.Repeat("░", 20-bars)
 		content.WriteString(fmt.Sprintf("%-20s %s %.1f%%\n", metric, barStr, value*100))
 	}
 	content.WriteString("\n")
 
+	// Activity inside vs outside work hours, when there are timestamps
+	content.WriteString(renderWorkHours(patterns.WorkHours))
+
+	// Interactive vs Scripted
+	content.WriteString("⌨️  Interactive vs Scripted:\n")
+	if timed := patterns.Interactivity.Timed(); timed > 0 {
+		ratio := float64(patterns.Interactivity.Interactive) / float64(timed)
+		bars := int(ratio * 20)
+		bars = min(max(bars, 0), 20)
+		barStr := strings.Repeat("█", bars) + strings.Repeat("░", 20-bars)
+		content.WriteString(fmt.Sprintf("%-20s %s %.1f%%\n", "Typed", barStr, ratio*100))
+		content.WriteString(fmt.Sprintf("%d typed, %d pasted or scripted (bursts under %s apart)\n",
+			patterns.Interactivity.Interactive, patterns.Interactivity.Scripted, analyzer.BurstThreshold))
+	} else {
+		content.WriteString("No timing data available (enable EXTENDED_HISTORY in zsh or set HISTTIMEFORMAT in bash)\n")
+	}
+	content.WriteString("\n")
+
+	// How cd is used
+	content.WriteString(renderNavigation(patterns.Navigation))
+
+	// Local work vs work in containers and on remote hosts
+	content.WriteString(renderWorkplaces(patterns.Workplaces))
+
+	// Sessions across shells, with -timeline
+	content.WriteString(renderSessions(patterns.Sessions))
+
 	// Common Workflows
 	content.WriteString("🔄 Common Workflows:\n")
 	for _, workflow := range patterns.CommonWorkflows {
 		content.WriteString(fmt.Sprintf("• %s\n", workflow))
 	}
+	if len(patterns.CommonWorkflows) == 0 {
+		content.WriteString("No commands that often run back to back\n")
+	}
 
 	return style.Render(content.String())
 }
 
-func renderToolUsage(usage ToolUsage) string {
+func renderToolUsage(usage analyzer.ToolUsage) string {
 	style := lipgloss.NewStyle().
 		BorderStyle(lipgloss.RoundedBorder()).
 		Padding(1)
@@ -407,6 +835,31 @@ func renderToolUsage(usage ToolUsage) string {
 	var content strings.Builder
 	content.WriteString(color.Magenta.Sprintf("🔧 Tool Usage Statistics\n\n"))
 
+	// Tracked Tools Section
+	if len(usage.Tracked) > 0 {
+		content.WriteString("📌 Tracked Tools:\n")
+		maxCount := 0
+		for _, tool := range usage.Tracked {
+			if tool.Count > maxCount {
+				maxCount = tool.Count
+			}
+		}
+		for _, tool := range usage.Tracked {
+			bars := 0
+			if maxCount > 0 {
+				bars = tool.Count * 20 / maxCount
+			}
+			bars = min(max(bars, 0), 20)
+			barStr := strings.Repeat("█", bars) + strings.Repeat("░", 20-bars)
+			status := ""
+			if !tool.Installed {
+				status = color.Gray.Sprint(" not installed")
+			}
+			content.WriteString(fmt.Sprintf("%-15s: %s (%d uses)%s\n", tool.Name, barStr, tool.Count, status))
+		}
+		content.WriteString("\n")
+	}
+
 	// Calculate total usage
 	total := 0
 	for _, count := range usage.Editors {
@@ -419,11 +872,12 @@ func renderToolUsage(usage ToolUsage) string {
 		for editor, count := range usage.Editors {
 			percentage := float64(count) / float64(total) * 100
 			bars := int(percentage / 5)
-			if bars < 0 {
-				bars = 0
-			}
+			bars = min(max(bars, 0), 20)
 			barStr := strings.Repeat("█", bars) + strings.Repeat("░", 20-bars)
 			content.WriteString(fmt.Sprintf("%-15s: %s (%d uses, %.1f%%)\n", editor, barStr, count, percentage))
+			if fileTypes := usage.EditorFileTypes[editor]; len(fileTypes) > 0 {
+				content.WriteString(fmt.Sprintf("%-15s  edits %s\n", "", topFileTypes(fileTypes, 5)))
+			}
 		}
 	} else {
 		content.WriteString("No editor usage data available\n")
@@ -432,12 +886,14 @@ func renderToolUsage(usage ToolUsage) string {
 
 	// Languages Section
 	content.WriteString("💻 Programming Languages:\n")
+	total = 0
+	for _, count := range usage.Languages {
+		total += count
+	}
 	if total > 0 {
 		for lang, count := range usage.Languages {
 			bars := int(float64(count) / float64(total) * 20)
-			if bars < 0 {
-				bars = 0
-			}
+			bars = min(max(bars, 0), 20)
 			barStr := strings.Repeat("█", bars) + strings.Repeat("░", 20-bars)
 			content.WriteString(fmt.Sprintf("%-15s: %s (%d uses)\n", lang, barStr, count))
 		}
@@ -448,194 +904,163 @@ func renderToolUsage(usage ToolUsage) string {
 
 	// Build Tools Section
 	content.WriteString("🛠️  Build Tools:\n")
+	total = 0
+	for _, count := range usage.BuildTools {
+		total += count
+	}
 	if total > 0 {
 		for tool, count := range usage.BuildTools {
 			bars := int(float64(count) / float64(total) * 20)
-			if bars < 0 {
-				bars = 0
-			}
+			bars = min(max(bars, 0), 20)
 			barStr := strings.Repeat("█", bars) + strings.Repeat("░", 20-bars)
 			content.WriteString(fmt.Sprintf("%-15s: %s (%d uses)\n", tool, barStr, count))
 		}
 	} else {
 		content.WriteString("No build tool usage data available\n")
 	}
+	content.WriteString("\n")
 
-	return style.Render(content.String())
-}
-
-// Shell analysis function
-func analyzeShells() tea.Msg {
-	data := initShellData()
-
-	// Read shell histories
-	shellPaths := map[string]string{
-		"bash": "~/.bash_history",
-		"zsh":  "~/.zsh_history",
-		"fish": "~/.local/share/fish/fish_history",
-	}
-
-	for shell, path := range shellPaths {
-		expandedPath := expandPath(path)
-		if history, err := readHistory(expandedPath); err == nil {
-			data.Histories[shell] = history
-			analyzeCommands(history, &data)
-			data.ShellConfigs[shell] = analyzeShellConfigs(shell)
+	// Other Tools Section
+	content.WriteString("🧰 Other Tools:\n")
+	if len(usage.Other) > 0 {
+		type toolCount struct {
+			name  string
+			count int
 		}
-	}
-
-	return data
-}
+		var others []toolCount
+		maxCount := 0
+		for tool, count := range usage.Other {
+			others = append(others, toolCount{tool, count})
+			if count > maxCount {
+				maxCount = count
+			}
+		}
+		sort.Slice(others, func(i, j int) bool {
+			if others[i].count != others[j].count {
+				return others[i].count > others[j].count
+			}
+			return others[i].name < others[j].name
+		})
 
-func readHistory(path string) ([]CommandEntry, error) {
-	file, err := os.Open(path)
-	if err != nil {
-		return nil, err
+		// Show only the top 10
+		for i := 0; i < len(others) && i < 10; i++ {
+			bars := others[i].count * 20 / maxCount
+			bars = min(max(bars, 0), 20)
+			barStr := strings.Repeat("█", bars) + strings.Repeat("░", 20-bars)
+			content.WriteString(fmt.Sprintf("%-15s: %s (%d uses)\n", others[i].name, barStr, others[i].count))
+		}
+	} else {
+		content.WriteString("No other tools detected\n")
 	}
-	defer file.Close()
-
-	var entries []CommandEntry
-	scanner := bufio.NewScanner(file)
+	content.WriteString("\n")
 
-	for scanner.Scan() {
-		line := scanner.Text()
-		if cmd := cleanHistoryLine(line); cmd != "" {
-			entries = append(entries, CommandEntry{
-				Command:    cmd,
-				Timestamp:  time.Now(), // For simplicity
-				Categories: categorizeCommand(cmd),
-			})
-		}
+	// Tool Lifecycle Section
+	content.WriteString(renderToolLifecycle(usage.Lifecycle))
+	if learning := renderCurrentlyLearning(usage.Lifecycle); learning != "" {
+		content.WriteString("\n" + learning)
 	}
 
-	return entries, scanner.Err()
+	return style.Render(content.String())
 }
 
-func cleanHistoryLine(line string) string {
-	parts := strings.Fields(line)
-	if len(parts) > 0 {
-		return parts[len(parts)-1]
+// Shell analysis command. Finished shells are counted in progress, which
+// may be nil.
+func analyzeShells(cfg analyzer.Config, progress *analyzer.AnalysisProgress) tea.Cmd {
+	return func() tea.Msg {
+		return analyzeAll(cfg, progress)
 	}
-	return ""
 }
 
-func categorizeCommand(cmd string) []string {
-	categories := []string{}
-	patterns := map[string][]string{
-		"development": {"git", "docker", "npm", "go", "python"},
-		"system":      {"sudo", "systemctl", "ps", "top"},
-		"file":        {"ls", "cd", "cp", "mv", "rm"},
-	}
+// Analyze every shell with its commands kept, for the views drawn from
+// them, and the paths and settings in cfg
+func analyzeAll(cfg analyzer.Config, progress *analyzer.AnalysisProgress) analyzer.ShellData {
+	data, _ := analyzer.Analyze(analyzer.Options{Config: &cfg, KeepEntries: true, Progress: progress})
+	return data
+}
 
-	for category, patterns := range patterns {
-		for _, pattern := range patterns {
-			if strings.HasPrefix(cmd, pattern) {
-				categories = append(categories, category)
-				break
-			}
+// Sum category weights across a history
+func categoryBreakdown(entries []analyzer.CommandEntry) map[string]float64 {
+	breakdown := make(map[string]float64)
+	for _, entry := range entries {
+		for _, match := range entry.Categories {
+			breakdown[match.Name] += match.Weight * float64(entry.Runs())
 		}
 	}
-
-	return categories
+	return breakdown
 }
 
-func analyzeCommands(entries []CommandEntry, data *ShellData) {
-	// Initialize maps for analysis
-	langUsage := make(map[string]int)
-	toolUsage := make(map[string]int)
-	timeOfDay := make(map[int]int)
-	commandPatterns := make(map[string]int)
-
-	// Get installed languages
-	installedLangs := getInstalledLanguages()
-
-	// Analyze each command
+// Count network tools by name, like "ssh 40 · curl 12"
+func renderNetworkActivity(entries []analyzer.CommandEntry) string {
+	counts := make(map[string]int)
 	for _, entry := range entries {
-		cmd := entry.Command
-		hour := entry.Timestamp.Hour()
-		timeOfDay[hour]++
-
-		// Language usage analysis
-		for lang := range installedLangs {
-			if strings.Contains(cmd, lang) ||
-				strings.Contains(cmd, getPackageManager(lang)) {
-				langUsage[lang]++
-			}
-		}
-
-		// Development tool analysis
-		tools := []string{"git", "docker", "kubectl", "terraform", "ansible", "make"}
-		for _, tool := range tools {
-			if strings.HasPrefix(cmd, tool) && checkToolInstalled(tool) {
-				toolUsage[tool]++
+		for _, cmd := range analyzer.SplitCommand(entry.Command) {
+			base := analyzer.BaseCommand(cmd)
+			for _, tool := range analyzer.DefaultCategories()["network"] {
+				if base == tool {
+					counts[tool] += entry.Runs()
+				}
 			}
 		}
-
-		// Analyze command patterns
-		analyzeCommandPattern(cmd, commandPatterns)
 	}
-
-	// Update TechnicalProfile
-	techProfile := &data.Insights.TechnicalProfile
-
-	// Calculate primary role based on most used language/tool
-	if primaryLang, ok := getMostUsed(langUsage); ok {
-		techProfile.PrimaryRole = fmt.Sprintf("%s Developer", strings.Title(primaryLang))
+	if len(counts) == 0 {
+		return ""
 	}
 
-	// Calculate tech stack
-	techProfile.TechStack = make([]string, 0)
-	for lang := range installedLangs {
-		if langUsage[lang] > 0 {
-			techProfile.TechStack = append(techProfile.TechStack, lang)
-		}
+	var tools []string
+	for tool := range counts {
+		tools = append(tools, tool)
 	}
-
-	// Calculate proficiency
-	totalCommands := len(entries)
-	if totalCommands > 0 {
-		for lang, count := range langUsage {
-			techProfile.Proficiency[lang] = float64(count) / float64(totalCommands)
-		}
-		for tool, count := range toolUsage {
-			techProfile.Proficiency[tool] = float64(count) / float64(totalCommands)
+	sort.Slice(tools, func(i, j int) bool {
+		if counts[tools[i]] != counts[tools[j]] {
+			return counts[tools[i]] > counts[tools[j]]
 		}
+		return tools[i] < tools[j]
+	})
+	var parts []string
+	for _, tool := range tools {
+		parts = append(parts, fmt.Sprintf("%s %d", tool, counts[tool]))
 	}
-
-	// Update WorkPatterns
-	patterns := &data.Insights.WorkPatterns
-	patterns.PeakHours = getPeakHours(timeOfDay)
-
-	// Calculate productivity metrics based on command complexity and variety
-	patterns.Productivity = calculateProductivityMetrics(entries, commandPatterns)
+	return fmt.Sprintf("Network: %s\n", strings.Join(parts, " · "))
 }
 
-func getPackageManager(lang string) string {
-	managers := map[string]string{
-		"python": "pip",
-		"node":   "npm",
-		"go":     "go get",
-		"rust":   "cargo",
-		"ruby":   "gem",
-		"php":    "composer",
+// List the most used commands in one category
+func renderCategoryCommands(entries []analyzer.CommandEntry, category string) string {
+	counts := make(map[string]int)
+	for _, entry := range entries {
+		for _, match := range entry.Categories {
+			if match.Name == category {
+				counts[entry.Command] += entry.Runs()
+				break
+			}
+		}
 	}
-	return managers[lang]
-}
 
-func analyzeCommandPattern(cmd string, patterns map[string]int) {
-	// Define common command patterns
-	patternMap := map[string]*regexp.Regexp{
-		"git_workflow": regexp.MustCompile(`git (commit|push|pull|merge)`),
-		"build":        regexp.MustCompile(`(make|build|compile)`),
-		"deploy":       regexp.MustCompile(`(deploy|kubectl|docker)`),
-		"test":         regexp.MustCompile(`test|spec|pytest`),
+	var content strings.Builder
+	content.WriteString(fmt.Sprintf("\n🔎 %s commands:\n", color.Cyan.Sprint(category)))
+	if len(counts) == 0 {
+		content.WriteString(fmt.Sprintf("No %s commands found\n", category))
+		return content.String()
 	}
 
-	for pattern, regex := range patternMap {
-		if regex.MatchString(cmd) {
-			patterns[pattern]++
+	var commands []string
+	for cmd := range counts {
+		commands = append(commands, cmd)
+	}
+	sort.Slice(commands, func(i, j int) bool {
+		if counts[commands[i]] != counts[commands[j]] {
+			return counts[commands[i]] > counts[commands[j]]
 		}
+		return commands[i] < commands[j]
+	})
+
+	// Show only the top 10
+	if len(commands) > 10 {
+		commands = commands[:10]
+	}
+	for _, cmd := range commands {
+		content.WriteString(fmt.Sprintf("• %-40s %d\n", cmd, counts[cmd]))
 	}
+	return content.String()
 }
 
 func getMostUsed(usage map[string]int) (string, bool) {
@@ -650,407 +1075,294 @@ func getMostUsed(usage map[string]int) (string, bool) {
 	return maxKey, maxVal > 0
 }
 
-func getPeakHours(timeOfDay map[int]int) []int {
-	type hourCount struct {
-		hour  int
-		count int
-	}
-
-	var hours []hourCount
-	for h, c := range timeOfDay {
-		hours = append(hours, hourCount{h, c})
-	}
-
-	sort.Slice(hours, func(i, j int) bool {
-		return hours[i].count > hours[j].count
-	})
-
-	// Return top 3 peak hours
-	var peaks []int
-	for i := 0; i < len(hours) && i < 3; i++ {
-		peaks = append(peaks, hours[i].hour)
-	}
-	return peaks
-}
-
-func calculateProductivityMetrics(entries []CommandEntry, patterns map[string]int) map[string]float64 {
-	metrics := make(map[string]float64)
-	totalCommands := len(entries)
+func generateRecommendations(data *analyzer.ShellData) []string {
+	recommendations := []string{}
 
-	if totalCommands == 0 {
-		return metrics
-	}
+	// Analyze shell configuration
+	for shell, config := range data.ShellConfigs {
+		if len(config.Aliases) < 5 {
+			recommendations = append(recommendations,
+				fmt.Sprintf("Consider adding more aliases to your %s configuration to improve productivity", shell))
+		}
 
-	// Command variety score
-	uniqueCommands := make(map[string]bool)
-	for _, entry := range entries {
-		uniqueCommands[entry.Command] = true
+		if len(config.Plugins) < 3 {
+			recommendations = append(recommendations,
+				fmt.Sprintf("Explore popular %s plugins to enhance your shell experience", shell))
+		}
 	}
-	metrics["Command Variety"] = float64(len(uniqueCommands)) / float64(totalCommands)
-
-	// Workflow complexity score
-	workflowScore := float64(patterns["git_workflow"]+patterns["build"]+
-		patterns["deploy"]+patterns["test"]) / float64(totalCommands)
-	metrics["Workflow Complexity"] = workflowScore
-
-	return metrics
-}
 
-func checkToolInstalled(tool string) bool {
-	_, err := exec.LookPath(tool)
-	return err == nil
+	return recommendations
 }
 
-func getInstalledLanguages() map[string]string {
-	languages := map[string]string{
-		// Programming Languages
-		"python":  "python --version",
-		"python3": "python3 --version",
-		"node":    "node --version",
-		"go":      "go version",
-		"java":    "java -version",
-		"ruby":    "ruby --version",
-		"php":     "php --version",
-		"rust":    "rustc --version",
-		"perl":    "perl --version",
-		"scala":   "scala -version",
-		"kotlin":  "kotlin -version",
-		"swift":   "swift --version",
-		"r":       "R --version",
-		"julia":   "julia --version",
-		"haskell": "ghc --version",
-		"elixir":  "elixir --version",
-		"erlang":  "erl -version",
-		"clang":   "clang --version",
-		"gcc":     "gcc --version",
-		"dotnet":  "dotnet --version",
-		"lua":     "lua -v",
-		"ocaml":   "ocaml -version",
-		"dart":    "dart --version",
-		"zig":     "zig version",
-		"nim":     "nim --version",
-
-		// Build Tools & Package Managers
-		"maven":    "mvn --version",
-		"gradle":   "gradle --version",
-		"npm":      "npm --version",
-		"yarn":     "yarn --version",
-		"pnpm":     "pnpm --version",
-		"pip":      "pip --version",
-		"cargo":    "cargo --version",
-		"composer": "composer --version",
-		"bundler":  "bundle --version",
-
-		// DevOps & Cloud Tools
-		"docker":    "docker --version",
-		"kubectl":   "kubectl version --client",
-		"terraform": "terraform version",
-		"ansible":   "ansible --version",
-		"vagrant":   "vagrant --version",
-		"helm":      "helm version",
-		"aws":       "aws --version",
-		"gcloud":    "gcloud --version",
-		"azure":     "az --version",
-
-		// Version Control
-		"git":       "git --version",
-		"svn":       "svn --version",
-		"mercurial": "hg --version",
-
-		// Databases
-		"mysql":   "mysql --version",
-		"psql":    "psql --version",
-		"mongodb": "mongod --version",
-		"redis":   "redis-cli --version",
-
-		// Web Servers & Tools
-		"nginx":   "nginx -v",
-		"apache2": "apache2 -v",
-		"curl":    "curl --version",
-		"wget":    "wget --version",
-
-		// Text Editors & IDEs
-		"vim":   "vim --version",
-		"nvim":  "nvim --version",
-		"emacs": "emacs --version",
-		"code":  "code --version",
-
-		// Shell & Terminal Tools
-		"zsh":  "zsh --version",
-		"bash": "bash --version",
-		"fish": "fish --version",
-		"tmux": "tmux -V",
-	}
-
-	installed := make(map[string]string)
-	for lang, cmd := range languages {
-		if out, err := exec.Command("sh", "-c", cmd).Output(); err == nil {
-			installed[lang] = string(out)
-		}
-	}
-
-	// Sort and keep only top 10 most used
-	type usageEntry struct {
-		name  string
-		count int
-	}
-	var usageList []usageEntry
-	for name := range installed {
-		count := 0
-		// Count occurrences in command history (you'll need to pass this data somehow)
-		// For now, we'll just store all installed ones
-		usageList = append(usageList, usageEntry{name, count})
-	}
-
-	// Sort by usage count
-	sort.Slice(usageList, func(i, j int) bool {
-		return usageList[i].count > usageList[j].count
-	})
+func generateWorkflowTips(data *analyzer.ShellData) []string {
+	tips := []string{}
 
-	// Keep only top 10
-	result := make(map[string]string)
-	for i := 0; i < len(usageList) && i < 10; i++ {
-		name := usageList[i].name
-		result[name] = installed[name]
+	// Patterns that already have an alias in some shell, or that Tips
+	// suggests one for, need no tip
+	aliased := make(map[string]bool)
+	for shell, config := range data.ShellConfigs {
+		for _, command := range config.Aliases {
+			aliased[strings.TrimSpace(command)] = true
+		}
+		for _, suggestion := range suggestAliases(shell, data.Histories[shell], config) {
+			words, _ := analyzer.ShellWords(suggestion.Command)
+			aliased[strings.Join(words, " ")] = true
+		}
 	}
 
-	return result
-}
-
-func expandPath(path string) string {
-	if strings.HasPrefix(path, "~/") {
-		home, err := os.UserHomeDir()
-		if err != nil {
-			return path
+	// Analyze command patterns, most used first
+	commonPatterns := analyzeCommandPatterns(data)
+	var frequent []string
+	for pattern, count := range commonPatterns {
+		if count > 10 && !aliased[pattern] {
+			frequent = append(frequent, pattern)
 		}
-		return filepath.Join(home, path[2:])
 	}
-	return path
-}
-
-func analyzeShellConfigs(shell string) ShellConfig {
-	configPaths := map[string][]string{
-		"bash": {
-			"~/.bashrc",
-			"~/.bash_profile",
-			"~/.bash_aliases",
-		},
-		"zsh": {
-			"~/.zshrc",
-			"~/.zsh_plugins",
-			"~/.zprofile",
-		},
-		"fish": {
-			"~/.config/fish/config.fish",
-			"~/.config/fish/functions",
-			"~/.config/fish/conf.d",
-		},
-	}
-
-	config := ShellConfig{
-		ConfigFiles: make(map[string]ConfigInfo),
-		Aliases:     make(map[string]string),
-		Environment: make(map[string]string),
-		Plugins:     make([]PluginInfo, 0),
-	}
-
-	// Read and analyze config files
-	for _, paths := range configPaths[shell] {
-		expandedPath := expandPath(paths)
-		if info, err := os.Stat(expandedPath); err == nil {
-			content, _ := os.ReadFile(expandedPath)
-			config.ConfigFiles[paths] = ConfigInfo{
-				Path:     expandedPath,
-				Modified: info.ModTime(),
-				Content:  string(content),
-			}
-
-			// Parse the config file
-			parseShellConfig(string(content), &config)
+	sort.Slice(frequent, func(i, j int) bool {
+		if commonPatterns[frequent[i]] != commonPatterns[frequent[j]] {
+			return commonPatterns[frequent[i]] > commonPatterns[frequent[j]]
 		}
+		return frequent[i] < frequent[j]
+	})
+	for _, pattern := range frequent {
+		tips = append(tips, fmt.Sprintf(
+			"You frequently use '%s'. Consider creating an alias for this pattern", pattern))
 	}
 
-	// Detect plugins based on shell type
-	detectPlugins(shell, &config)
-
-	return config
+	return tips
 }
 
-func parseShellConfig(content string, config *ShellConfig) {
-	scanner := bufio.NewScanner(strings.NewReader(content))
-	for scanner.Scan() {
-		line := scanner.Text()
-
-		// Parse aliases
-		if strings.HasPrefix(line, "alias ") {
-			parts := strings.SplitN(strings.TrimPrefix(line, "alias "), "=", 2)
-			if len(parts) == 2 {
-				name := strings.TrimSpace(parts[0])
-				value := strings.Trim(strings.TrimSpace(parts[1]), "'\"")
-				config.Aliases[name] = value
-			}
-		}
+func analyzeCommandPatterns(data *analyzer.ShellData) map[string]int {
+	patterns := make(map[string]int)
 
-		// Parse environment variables
-		if strings.HasPrefix(line, "export ") {
-			parts := strings.SplitN(strings.TrimPrefix(line, "export "), "=", 2)
-			if len(parts) == 2 {
-				name := strings.TrimSpace(parts[0])
-				value := strings.Trim(strings.TrimSpace(parts[1]), "'\"")
-				config.Environment[name] = value
+	for _, history := range data.Histories {
+		for _, entry := range history {
+			// Look for common command sequences
+			parts := strings.Fields(entry.Command)
+			if len(parts) > 1 {
+				pattern := strings.Join(parts[:2], " ")
+				patterns[pattern] += entry.Runs()
 			}
 		}
 	}
+
+	return patterns
 }
 
-func detectPlugins(shell string, config *ShellConfig) {
-	switch shell {
-	case "zsh":
-		detectZshPlugins(config)
-	case "fish":
-		detectFishPlugins(config)
-	case "bash":
-		detectBashPlugins(config)
+func main() {
+	takeSnapshot := flag.Bool("snapshot", false, "save a snapshot of this analysis for trends")
+	prune := flag.Bool("prune-snapshots", false, "apply the snapshot retention policy and exit")
+	keepSnapshots := flag.Int("keep-snapshots", 30, "number of snapshots to keep")
+	configPath := flag.String("config", defaultConfigPath, "path to the config file")
+	format := flag.String("format", "", "export as json, csv, html, md, text, summary or dotfiles and exit instead of opening the TUI")
+	output := flag.String("output", "", "file to write the export to (default stdout)")
+	htmlPath := flag.String("html", "", "deprecated: use -format html -output FILE")
+	jsonPath := flag.String("json", "", "write the full analysis as JSON to `file` and exit, like -format json -output FILE")
+	summaryJSON := flag.Bool("summary-json", false, "export only the high-level insights as compact JSON, like -format summary")
+	dotfilesPath := flag.String("export-dotfiles", "", "write suggested aliases and settings as a snippet for your dotfiles to `file` and exit")
+	dashboard := flag.Bool("dashboard", false, "show the main sections side by side on wide terminals")
+	importPath := flag.String("import", "", "show or re-export a JSON export instead of analyzing this machine")
+	doctor := flag.Bool("doctor", false, "check shells, history files and config, then exit")
+	refresh := flag.Duration("refresh", 0, "re-run the analysis this often in the TUI, -serve and -watch, like 5m (overrides refresh_interval_minutes)")
+	serve := flag.String("serve", "", "serve the HTML, JSON and summary reports over HTTP on `addr`, like localhost:8080, re-analyzing at most every -refresh (5m by default)")
+	watch := flag.Bool("watch", false, "print the text report, and again whenever a history or config file changes, checking every -refresh (5m by default)")
+	timeline := flag.Bool("timeline", false, "merge all shells' histories by timestamp for session and workflow analysis")
+	historyFlag := flag.String("history", "", "history file paths that override the defaults, like bash=/path,zsh=/path")
+	omitContents := flag.Bool("omit-config-contents", false, "leave the raw contents of config files out of JSON exports")
+	noTUI := flag.Bool("no-tui", false, "print Overview, Tech Profile, Work Patterns and Tool Usage as plain text and exit, non-zero when no history is found")
+	section := flag.String("section", "", "print only this view, like tech-profile, as text (or in the -format html, md or text report)")
+	flag.Parse()
+
+	// A broken config falls back to defaults and is reported in the UI
+	config, configErr := analyzer.LoadConfig(*configPath)
+	config.Timeline = *timeline
+	config.Section = *section
+	config.OmitConfigContents = *omitContents
+	historyPaths, err := parseHistoryOverrides(*historyFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	config.HistoryPaths = historyPaths
+	if *refresh > 0 {
+		config.RefreshIntervalMinutes = refresh.Minutes()
 	}
-}
 
-func detectZshPlugins(config *ShellConfig) {
-	// Check for common plugin managers
-	pluginManagers := []string{
-		"~/.oh-my-zsh",
-		"~/.antigen",
-		"~/.zinit",
-		"~/.zplug",
-	}
-
-	for _, manager := range pluginManagers {
-		path := expandPath(manager)
-		if info, err := os.Stat(path); err == nil && info.IsDir() {
-			config.Plugins = append(config.Plugins, PluginInfo{
-				Name:        filepath.Base(manager),
-				Source:      path,
-				LastUpdated: info.ModTime(),
-			})
+	if *doctor {
+		if !printDoctor(os.Stdout, runDoctor(*configPath, config, configErr)) {
+			os.Exit(1)
 		}
+		return
 	}
-}
 
-func detectFishPlugins(config *ShellConfig) {
-	fishPluginPath := expandPath("~/.config/fish/conf.d")
-	if files, err := os.ReadDir(fishPluginPath); err == nil {
-		for _, file := range files {
-			if strings.HasSuffix(file.Name(), ".fish") {
-				info, _ := file.Info()
-				config.Plugins = append(config.Plugins, PluginInfo{
-					Name:        strings.TrimSuffix(file.Name(), ".fish"),
-					Source:      filepath.Join(fishPluginPath, file.Name()),
-					LastUpdated: info.ModTime(),
-				})
-			}
+	if *prune {
+		removed, err := analyzer.PruneSnapshots(config.ExpandPath(analyzer.SnapshotDir), *keepSnapshots)
+		if err != nil {
+			fmt.Printf("Error pruning snapshots: %v\n", err)
+			os.Exit(1)
 		}
+		fmt.Printf("Pruned %d snapshot(s), keeping the last %d\n", removed, *keepSnapshots)
+		return
 	}
-}
 
-func detectBashPlugins(config *ShellConfig) {
-	// Check for common bash plugin managers and extensions
-	bashPluginPaths := []string{
-		"~/.bash_it",
-		"~/.local/share/bash-completion",
+	// Load previously exported data instead of analyzing this machine
+	var imported *analyzer.ShellData
+	if *importPath != "" {
+		data, err := analyzer.ImportFile(*importPath)
+		if err != nil {
+			fmt.Printf("Error importing: %v\n", err)
+			os.Exit(1)
+		}
+		imported = &data
 	}
 
-	for _, path := range bashPluginPaths {
-		expandedPath := expandPath(path)
-		if info, err := os.Stat(expandedPath); err == nil && info.IsDir() {
-			config.Plugins = append(config.Plugins, PluginInfo{
-				Name:        filepath.Base(path),
-				Source:      expandedPath,
-				LastUpdated: info.ModTime(),
-			})
+	if *serve != "" {
+		if configErr != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", configErr)
 		}
+		analyze := func() analyzer.ShellData { return analyzeAll(config, nil) }
+		if imported != nil {
+			analyze = func() analyzer.ShellData { return *imported }
+		}
+		fmt.Fprintf(os.Stderr, "Serving reports on http://%s (re-analyzing at most every %v)\n", *serve, config.ServeInterval())
+		if err := serveReports(*serve, config, config.ServeInterval(), analyze); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
 	}
-}
 
-func analyzeCommandComplexity(data *ShellData) float64 {
-	var totalCommands, complexCommands float64
-
-	for _, history := range data.Histories {
-		for _, entry := range history {
-			totalCommands++
-
-			// Count pipes and redirections
-			if strings.Contains(entry.Command, "|") ||
-				strings.Contains(entry.Command, ">") ||
-				strings.Contains(entry.Command, "<") {
-				complexCommands++
+	if *watch {
+		if imported != nil {
+			fmt.Fprintln(os.Stderr, "Error: -watch re-reads this machine's histories and can't be used with -import")
+			os.Exit(1)
+		}
+		if *section != "" {
+			if err := checkSection(*section); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
 			}
+		}
+		if configErr != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", configErr)
+		}
+		if err := watchReports(analyzer.Options{Config: &config, KeepEntries: true}, config.ServeInterval(), os.Stdout, nil); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
 
-			// Count commands with multiple arguments
-			if len(strings.Fields(entry.Command)) > 2 {
-				complexCommands += 0.5
+	if *noTUI {
+		if configErr != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", configErr)
+		}
+		var data analyzer.ShellData
+		if imported != nil {
+			data = *imported
+		} else {
+			data = analyzeAll(config, nil)
+			// Shells that aren't installed are expected to have no history
+			for _, err := range data.Errors {
+				if !errors.Is(err, analyzer.ErrHistoryNotFound) {
+					fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+				}
 			}
 		}
+		if err := writePlainReport(data, os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if len(data.Histories) == 0 {
+			fmt.Fprintln(os.Stderr, "Error: no shell history found")
+			os.Exit(1)
+		}
+		return
 	}
 
-	if totalCommands == 0 {
-		return 0
+	// Export jobs from -format, plus the single-format flags
+	type exportJob struct {
+		format string
+		path   string
+	}
+	var jobs []exportJob
+	if *format != "" {
+		jobs = append(jobs, exportJob{*format, *output})
+	} else if *section != "" {
+		// A single section is printed as text unless another format is asked for
+		jobs = append(jobs, exportJob{"text", *output})
+	}
+	if *summaryJSON && *format != "summary" {
+		jobs = append(jobs, exportJob{"summary", *output})
+	}
+	if *dotfilesPath != "" {
+		jobs = append(jobs, exportJob{"dotfiles", *dotfilesPath})
+	}
+	if *jsonPath != "" {
+		jobs = append(jobs, exportJob{"json", *jsonPath})
+	}
+	if *htmlPath != "" {
+		fmt.Fprintln(os.Stderr, "Warning: -html is deprecated, use -format html -output FILE")
+		jobs = append(jobs, exportJob{"html", *htmlPath})
 	}
 
-	return complexCommands / totalCommands
-}
-
-func generateRecommendations(data *ShellData) []string {
-	recommendations := []string{}
-
-	// Analyze shell configuration
-	for shell, config := range data.ShellConfigs {
-		if len(config.Aliases) < 5 {
-			recommendations = append(recommendations,
-				fmt.Sprintf("Consider adding more aliases to your %s configuration to improve productivity", shell))
+	if len(jobs) > 0 {
+		for _, job := range jobs {
+			if err := checkExportFormat(job.format); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			if *section != "" && !slices.Contains(sectionFormats, job.format) {
+				fmt.Fprintf(os.Stderr, "Error: -section only applies to the %s formats, not %s\n", strings.Join(sectionFormats, ", "), job.format)
+				os.Exit(1)
+			}
 		}
-
-		if len(config.Plugins) < 3 {
-			recommendations = append(recommendations,
-				fmt.Sprintf("Explore popular %s plugins to enhance your shell experience", shell))
+		if *section != "" {
+			if err := checkSection(*section); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
 		}
-	}
-
-	return recommendations
-}
-
-func generateWorkflowTips(data *ShellData) []string {
-	tips := []string{}
-
-	// Analyze command patterns
-	commonPatterns := analyzeCommandPatterns(data)
-	for pattern, count := range commonPatterns {
-		if count > 10 {
-			tips = append(tips, fmt.Sprintf(
-				"You frequently use '%s'. Consider creating an alias for this pattern", pattern))
+		if configErr != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", configErr)
 		}
-	}
-
-	return tips
-}
 
-func analyzeCommandPatterns(data *ShellData) map[string]int {
-	patterns := make(map[string]int)
+		var data analyzer.ShellData
+		if imported != nil {
+			data = *imported
+		} else {
+			data = analyzeAll(config, nil)
+			for _, err := range data.Errors {
+				if errors.Is(err, analyzer.ErrNoTimestamps) {
+					fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+				}
+			}
+		}
 
-	for _, history := range data.Histories {
-		for _, entry := range history {
-			// Look for common command sequences
-			parts := strings.Fields(entry.Command)
-			if len(parts) > 1 {
-				pattern := strings.Join(parts[:2], " ")
-				patterns[pattern]++
+		for _, job := range jobs {
+			err := writeExportFile(job.path, func(w io.Writer) error {
+				return Export(data, job.format, config, w)
+			})
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error writing %s export: %v\n", job.format, err)
+				os.Exit(1)
 			}
 		}
+		return
 	}
 
-	return patterns
-}
+	model := initialModel(config)
+	if configErr != nil {
+		model.errors = append(model.errors, configErr)
+	}
+	model.keepSnapshots = *keepSnapshots
+	model.dashboard = *dashboard || config.Layout == layoutDashboard
+	model.imported = imported
 
-func main() {
-	p := tea.NewProgram(initialModel(),
+	// Snapshots track this machine, so imported data never creates one
+	model.takeSnapshot = *takeSnapshot && imported == nil
+
+	p := tea.NewProgram(model,
 		tea.WithAltScreen(),
 		tea.WithMouseCellMotion())
 
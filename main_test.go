@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"shell-analyzer/analyzer"
+)
+
+func TestToolUsageTabHasContent(t *testing.T) {
+	tab := renderToolUsage(analyzer.ToolUsage{
+		Languages:       map[string]int{"go": 2, "python3": 1},
+		Editors:         map[string]int{"vim": 1},
+		EditorFileTypes: map[string]map[string]int{"vim": {".go": 1}},
+		BuildTools:      map[string]int{"make": 1, "cargo": 1},
+	})
+	for _, empty := range []string{
+		"No language usage data available",
+		"No build tool usage data available",
+	} {
+		if strings.Contains(tab, empty) {
+			t.Errorf("Tool Usage tab shows %q", empty)
+		}
+	}
+	for _, want := range []string{"vim", "python3", "cargo", ".go 1"} {
+		if !strings.Contains(tab, want) {
+			t.Errorf("Tool Usage tab is missing %q", want)
+		}
+	}
+}
+
+func TestRenderTopCommands(t *testing.T) {
+	if got := renderTopCommands(nil); got != "" {
+		t.Errorf("no commands rendered %q, want nothing", got)
+	}
+
+	counts := map[string]int{"git": 40, "ls": 20, "make": 20, "vim": 5}
+	for i := range maxTopCommands {
+		counts[fmt.Sprintf("tool%02d", i)] = 1
+	}
+	lines := strings.Split(strings.TrimSuffix(renderTopCommands(counts), "\n"), "\n")
+	if len(lines) != maxTopCommands+1 {
+		t.Fatalf("rendered %d commands, want the top %d:\n%s", len(lines)-1, maxTopCommands, strings.Join(lines, "\n"))
+	}
+
+	// Most used first, ties by name, with a bar scaled to the top command
+	want := []string{
+		"git             " + strings.Repeat("█", 20) + " 40",
+		"ls              " + strings.Repeat("█", 10) + strings.Repeat("░", 10) + " 20",
+		"make            " + strings.Repeat("█", 10) + strings.Repeat("░", 10) + " 20",
+		"vim             " + strings.Repeat("█", 2) + strings.Repeat("░", 18) + " 5",
+		"tool00          " + strings.Repeat("░", 20) + " 1",
+	}
+	for i, line := range want {
+		if lines[i+1] != line {
+			t.Errorf("line %d = %q, want %q", i+1, lines[i+1], line)
+		}
+	}
+	if last := lines[len(lines)-1]; !strings.HasPrefix(last, "tool10 ") {
+		t.Errorf("last line = %q, want tool10", last)
+	}
+}
+
+// Write files into a temp home directory, keyed by their path under it
+func writeTestHome(t testing.TB, files map[string]string) string {
+	t.Helper()
+	home := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(home, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return home
+}
+
+func TestPlainReportScoresOverOne(t *testing.T) {
+	for _, history := range []string{
+		"git add . && git commit -m x\n",
+		"make test\nmake test\ndocker build .\n",
+	} {
+		home := writeTestHome(t, map[string]string{".bash_history": history})
+		data, err := analyzer.Analyze(analyzer.Options{HomeDir: home, Shells: []string{"bash"}, KeepEntries: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var report strings.Builder
+		if err := writePlainReport(data, &report); err != nil {
+			t.Errorf("%q: %v", history, err)
+		}
+	}
+
+	// Scores past 1, as older exports may hold, fill their bars
+	profile := analyzer.TechProfile{Proficiency: map[string]float64{"git": 2}}
+	if got := renderTechProfile(profile, false); !strings.Contains(got, strings.Repeat("█", 20)+" 200.0%") {
+		t.Errorf("Tech Profile doesn't show a full bar for git:\n%s", got)
+	}
+	patterns := analyzer.WorkPatterns{Productivity: map[string]float64{"Workflow Complexity": 1.5}}
+	if got := renderWorkPatterns(patterns, nil); !strings.Contains(got, strings.Repeat("█", 20)+" 150.0%") {
+		t.Errorf("Work Patterns doesn't show a full bar for Workflow Complexity:\n%s", got)
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"shell-analyzer/analyzer"
+)
+
+// Describe the dominant way targets are written
+func describeNavigation(stats analyzer.NavigationStats) string {
+	paths := stats.Paths()
+	if paths == 0 {
+		return ""
+	}
+	switch {
+	case stats.Absolute*2 > paths:
+		return "You mostly jump straight to absolute paths"
+	case stats.HomeRelative*2 > paths:
+		return "You mostly navigate from your home directory with ~/"
+	case (stats.Relative+stats.Up)*2 > paths:
+		return "You mostly walk the tree with relative paths"
+	}
+	return "You mix absolute, ~/ and relative paths"
+}
+
+func renderNavigation(stats analyzer.NavigationStats) string {
+	var content strings.Builder
+	content.WriteString("🧭 Navigation Style:\n")
+	if stats.Total == 0 {
+		content.WriteString("No cd commands found\n\n")
+		return content.String()
+	}
+
+	content.WriteString(fmt.Sprintf("cd used %d times: %d home, %d back (cd -), %d up (..)\n",
+		stats.Total, stats.Home, stats.Back, stats.Up))
+	if paths := stats.Paths(); paths > 0 {
+		for _, style := range []struct {
+			name  string
+			count int
+		}{
+			{"Absolute", stats.Absolute},
+			{"From ~/", stats.HomeRelative},
+			{"Relative", stats.Relative + stats.Up},
+		} {
+			ratio := float64(style.count) / float64(paths)
+			bars := int(ratio * 20)
+			barStr := strings.Repeat("█", bars) + strings.Repeat("░", 20-bars)
+			content.WriteString(fmt.Sprintf("%-20s %s %.1f%%\n", style.name, barStr, ratio*100))
+		}
+		content.WriteString(fmt.Sprintf("Average depth: %.1f levels\n", stats.AverageDepth()))
+		content.WriteString(fmt.Sprintf("Tab-completed (trailing /): %.1f%%\n",
+			float64(stats.TrailingSlash)/float64(paths)*100))
+		content.WriteString(describeNavigation(stats) + "\n")
+	}
+	content.WriteString("\n")
+	return content.String()
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gookit/color"
+
+	"shell-analyzer/analyzer"
+)
+
+func renderOrphanPasswords(orphans []analyzer.OrphanPassword) string {
+	var content strings.Builder
+	content.WriteString(fmt.Sprintf("• Possible passwords typed as commands: %d\n", len(orphans)))
+	for i, orphan := range orphans {
+		if i == analyzer.MaxSecretSamples {
+			break
+		}
+		where := orphan.Shell
+		if !orphan.Timestamp.IsZero() {
+			where += ", " + orphan.Timestamp.Format("2006-01-02 15:04")
+		}
+		line := fmt.Sprintf("%s (%d chars, %s)", strings.Repeat("•", 8), orphan.Length, where)
+		if orphan.After != "" {
+			line += " after " + orphan.After
+		}
+		content.WriteString(fmt.Sprintf("    %s\n", color.Red.Sprint(line)))
+	}
+	return content.String()
+}
@@ -0,0 +1,22 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Parse -history, a comma-separated list like "bash=/path,zsh=/path"
+func parseHistoryOverrides(value string) (map[string]string, error) {
+	overrides := make(map[string]string)
+	if value == "" {
+		return overrides, nil
+	}
+	for _, pair := range strings.Split(value, ",") {
+		shell, path, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok || shell == "" || path == "" {
+			return nil, fmt.Errorf("-history: %q isn't shell=path", pair)
+		}
+		overrides[shell] = path
+	}
+	return overrides, nil
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"maps"
+	"testing"
+)
+
+func TestParseHistoryOverrides(t *testing.T) {
+	got, err := parseHistoryOverrides("bash=/a/history, zsh=/b/.zsh_history")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"bash": "/a/history", "zsh": "/b/.zsh_history"}
+	if !maps.Equal(got, want) {
+		t.Errorf("parseHistoryOverrides = %v, want %v", got, want)
+	}
+
+	if got, err := parseHistoryOverrides(""); err != nil || len(got) != 0 {
+		t.Errorf("empty value = %v, %v; want no overrides", got, err)
+	}
+	for _, value := range []string{"bash", "=/path", "bash=", "bash=/a,zsh"} {
+		if _, err := parseHistoryOverrides(value); err == nil {
+			t.Errorf("parseHistoryOverrides(%q) succeeded, want an error", value)
+		}
+	}
+}
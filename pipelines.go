@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/gookit/color"
+
+	"shell-analyzer/analyzer"
+)
+
+// Tools listed per pipeline position
+const maxPipelineTools = 8
+
+// The most common names in counts, like "grep 40 · awk 12"
+func topCounts(counts map[string]int, limit int) string {
+	var tools []string
+	for tool := range counts {
+		tools = append(tools, tool)
+	}
+	sort.Slice(tools, func(i, j int) bool {
+		if counts[tools[i]] != counts[tools[j]] {
+			return counts[tools[i]] > counts[tools[j]]
+		}
+		return tools[i] < tools[j]
+	})
+	if len(tools) > limit {
+		tools = tools[:limit]
+	}
+
+	var parts []string
+	for _, tool := range tools {
+		parts = append(parts, fmt.Sprintf("%s %d", tool, counts[tool]))
+	}
+	return strings.Join(parts, " · ")
+}
+
+func renderPipelines(stats analyzer.PipelineStats) string {
+	style := lipgloss.NewStyle().
+		BorderStyle(lipgloss.RoundedBorder()).
+		Padding(1)
+
+	var content strings.Builder
+	content.WriteString(color.Yellow.Sprintf("🔗 Pipelines\n\n"))
+
+	if stats.Pipelines == 0 {
+		content.WriteString("No pipelines found in your history\n")
+		return style.Render(content.String())
+	}
+
+	content.WriteString(fmt.Sprintf("Pipelines: %d, %.1f stages on average\n\n",
+		stats.Pipelines, float64(stats.Stages)/float64(stats.Pipelines)))
+	for _, position := range []struct {
+		title  string
+		counts map[string]int
+	}{
+		{"📤 Piped from (sources):", stats.Sources},
+		{"🔀 Piped through (filters):", stats.Filters},
+		{"📥 Piped into (sinks):", stats.Sinks},
+	} {
+		content.WriteString(position.title + "\n")
+		if len(position.counts) == 0 {
+			content.WriteString("None\n\n")
+			continue
+		}
+		content.WriteString(topCounts(position.counts, maxPipelineTools) + "\n\n")
+	}
+
+	return style.Render(strings.TrimSuffix(content.String(), "\n"))
+}
@@ -0,0 +1,14 @@
+package main
+
+import "sort"
+
+// Shells sorted with the primary one first, then by name
+func primaryFirst(shells []string, primary string) []string {
+	sort.Slice(shells, func(i, j int) bool {
+		if (shells[i] == primary) != (shells[j] == primary) {
+			return shells[i] == primary
+		}
+		return shells[i] < shells[j]
+	})
+	return shells
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/gookit/color"
+
+	"shell-analyzer/analyzer"
+)
+
+func renderProjects(projects []analyzer.Project, hidden analyzer.PrivateProjects) string {
+	style := lipgloss.NewStyle().
+		BorderStyle(lipgloss.RoundedBorder()).
+		Padding(1)
+
+	var content strings.Builder
+	content.WriteString(color.Yellow.Sprintf("📁 Projects and Their Stacks\n\n"))
+	content.WriteString(color.Gray.Sprint("Heuristic: histories don't record where commands ran, so projects are guessed by following cd") + "\n\n")
+
+	privateNote := ""
+	if hidden.Projects > 0 {
+		privateNote = color.Gray.Sprintf("%d private project(s) with %d commands not shown (private_paths)", hidden.Projects, hidden.Commands)
+	}
+
+	if len(projects) == 0 {
+		content.WriteString("No projects found; cd into a project with an absolute or ~/ path to have it tracked\n")
+		if privateNote != "" {
+			content.WriteString(privateNote + "\n")
+		}
+		return style.Render(content.String())
+	}
+
+	for _, project := range projects {
+		language := project.Language()
+		if language == "" {
+			language = "no language detected"
+		}
+		content.WriteString(fmt.Sprintf("%s %s\n", color.Cyan.Sprint(project.Path),
+			color.Gray.Sprintf("(%d commands)", project.Commands)))
+		content.WriteString(fmt.Sprintf("  Language: %s\n", language))
+		if tools := topCounts(project.Tools, analyzer.MaxProjectTools); tools != "" {
+			content.WriteString(fmt.Sprintf("  Tools: %s\n", tools))
+		}
+	}
+
+	if privateNote != "" {
+		content.WriteString("\n" + privateNote + "\n")
+	}
+
+	return style.Render(strings.TrimSuffix(content.String(), "\n"))
+}
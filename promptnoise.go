@@ -0,0 +1,30 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+func renderPromptNoise(skipped map[string]int) string {
+	var content strings.Builder
+	content.WriteString("🧹 Prompt Noise Skipped:\n")
+
+	var shells []string
+	for shell, count := range skipped {
+		if count > 0 {
+			shells = append(shells, shell)
+		}
+	}
+	if len(shells) == 0 {
+		content.WriteString("No prompt artifacts found in your history\n")
+		return content.String()
+	}
+
+	sort.Strings(shells)
+	for _, shell := range shells {
+		content.WriteString(fmt.Sprintf("• %s: %d line(s) that looked like prompt output, not commands\n", shell, skipped[shell]))
+	}
+	content.WriteString("Set prompt_noise_patterns in the config to change what's skipped\n")
+	return content.String()
+}
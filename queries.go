@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/gookit/color"
+
+	"shell-analyzer/analyzer"
+)
+
+// Commands listed for the selected query
+const maxQueryCommands = 10
+
+// Summarize a query's filters, like "git · zsh · last 30 days"
+func describeQuery(query analyzer.SavedQuery) string {
+	var parts []string
+	if query.Command != "" {
+		parts = append(parts, query.Command)
+	}
+	if query.Shell != "" {
+		parts = append(parts, query.Shell)
+	}
+	if query.LastDays > 0 {
+		parts = append(parts, fmt.Sprintf("last %d days", query.LastDays))
+	}
+	if query.Since != "" || query.Until != "" {
+		parts = append(parts, fmt.Sprintf("%s to %s", orDefault(query.Since, "start"), orDefault(query.Until, "now")))
+	}
+	if len(parts) == 0 {
+		return "everything"
+	}
+	return strings.Join(parts, " · ")
+}
+
+func orDefault(s, fallback string) string {
+	if s == "" {
+		return fallback
+	}
+	return s
+}
+
+func renderQueries(data analyzer.ShellData, queries []analyzer.SavedQuery, selected int, now time.Time) string {
+	style := lipgloss.NewStyle().
+		BorderStyle(lipgloss.RoundedBorder()).
+		Padding(1)
+
+	var content strings.Builder
+	content.WriteString(color.Yellow.Sprintf("🔖 Saved Queries\n\n"))
+
+	if len(queries) == 0 {
+		content.WriteString("No saved queries yet. Add some under saved_queries in your config, e.g.\n")
+		content.WriteString(color.Gray.Sprint(`{"name": "Recent git", "command": "git", "last_days": 30}`) + "\n")
+		return style.Render(content.String())
+	}
+
+	for i, query := range queries {
+		marker := "  "
+		name := query.Name
+		if i == selected {
+			marker = color.Cyan.Sprint("▸ ")
+			name = color.Bold.Sprint(name)
+		}
+		content.WriteString(fmt.Sprintf("%s%s %s\n", marker, name, color.Gray.Sprintf("(%s)", describeQuery(query))))
+	}
+
+	query := queries[selected]
+	result := analyzer.RunQuery(data, query, now)
+	content.WriteString(fmt.Sprintf("\n%s: %d matching commands\n", color.Bold.Sprint(query.Name), result.Matches))
+	if !result.First.IsZero() {
+		content.WriteString(fmt.Sprintf("From %s to %s\n", result.First.Format(analyzer.DayLayout), result.Last.Format(analyzer.DayLayout)))
+	}
+	if result.Undated > 0 {
+		content.WriteString(color.Gray.Sprintf("%d matching commands without timestamps were left out", result.Undated) + "\n")
+	}
+
+	var commands []string
+	for cmd := range result.Commands {
+		commands = append(commands, cmd)
+	}
+	sort.Slice(commands, func(i, j int) bool {
+		if result.Commands[commands[i]] != result.Commands[commands[j]] {
+			return result.Commands[commands[i]] > result.Commands[commands[j]]
+		}
+		return commands[i] < commands[j]
+	})
+	if len(commands) > maxQueryCommands {
+		commands = commands[:maxQueryCommands]
+	}
+	if len(commands) > 0 {
+		content.WriteString("\nTop commands:\n")
+		for _, cmd := range commands {
+			content.WriteString(fmt.Sprintf("%4d  %s\n", result.Commands[cmd], cmd))
+		}
+	}
+	content.WriteString("\n↑/↓ select a query")
+
+	return style.Render(content.String())
+}
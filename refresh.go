@@ -0,0 +1,74 @@
+package main
+
+import (
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"shell-analyzer/analyzer"
+)
+
+// Sent when it's time to re-run the analysis in the TUI
+type refreshMsg struct{}
+
+// analysisCache keeps the last analysis and re-runs it only once it's
+// older than interval, so serving many requests parses histories and
+// probes tools at most once per interval
+type analysisCache struct {
+	mu       sync.Mutex
+	analyze  func() analyzer.ShellData
+	interval time.Duration
+	now      func() time.Time
+
+	data  analyzer.ShellData
+	taken time.Time
+}
+
+func newAnalysisCache(interval time.Duration, analyze func() analyzer.ShellData) *analysisCache {
+	return &analysisCache{analyze: analyze, interval: interval, now: time.Now}
+}
+
+// The last analysis and when it ran, re-running it first when it's stale.
+// Requests arriving during a re-run wait for it rather than starting
+// their own.
+func (c *analysisCache) get() (analyzer.ShellData, time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.taken.IsZero() || c.now().Sub(c.taken) >= c.interval {
+		c.data = c.analyze()
+		c.taken = c.now()
+	}
+	return c.data, c.taken
+}
+
+// Re-run the analysis after the configured interval. The next one is
+// scheduled only once this one is done, so slow analyses never overlap,
+// and the last result stays on screen in between.
+func scheduleRefresh(interval time.Duration) tea.Cmd {
+	if interval <= 0 {
+		return nil
+	}
+	return tea.Tick(interval, func(time.Time) tea.Msg {
+		return refreshMsg{}
+	})
+}
+
+// Errors not already in shown, so problems that persist across refreshes
+// are reported once
+func newErrors(shown, errs []error) []error {
+	seen := make(map[string]bool)
+	for _, err := range shown {
+		seen[err.Error()] = true
+	}
+
+	var fresh []error
+	for _, err := range errs {
+		if !seen[err.Error()] {
+			seen[err.Error()] = true
+			fresh = append(fresh, err)
+		}
+	}
+	return fresh
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"shell-analyzer/analyzer"
+)
+
+func TestAnalysisCacheReusesResultWithinInterval(t *testing.T) {
+	now := time.Date(2024, 4, 12, 10, 0, 0, 0, time.UTC)
+	runs := 0
+	cache := newAnalysisCache(5*time.Minute, func() analyzer.ShellData {
+		runs++
+		data := analyzer.NewShellData()
+		data.PrimaryShell = "zsh"
+		return data
+	})
+	cache.now = func() time.Time { return now }
+
+	data, taken := cache.get()
+	if runs != 1 || data.PrimaryShell != "zsh" || !taken.Equal(now) {
+		t.Fatalf("first get: %d runs, taken %v", runs, taken)
+	}
+
+	now = now.Add(4 * time.Minute)
+	if _, taken = cache.get(); runs != 1 || !taken.Equal(now.Add(-4*time.Minute)) {
+		t.Errorf("within the interval: %d runs, taken %v; want the cached result", runs, taken)
+	}
+
+	now = now.Add(time.Minute)
+	if _, taken = cache.get(); runs != 2 || !taken.Equal(now) {
+		t.Errorf("after the interval: %d runs, taken %v; want a fresh analysis", runs, taken)
+	}
+}
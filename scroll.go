@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Keys that scroll the active tab. Views that use up and down to select
+// something, like Tips, handle them first.
+var scrollKeys = map[string]bool{
+	"up": true, "down": true, "pgup": true, "pgdown": true,
+}
+
+// Cut lines wider than width rather than letting them wrap, which would
+// break the views' borders
+func clipWidth(content string, width int) string {
+	if width <= 0 {
+		return content
+	}
+	return lipgloss.NewStyle().MaxWidth(width).Render(content)
+}
+
+// Lines left for the active tab once the header, tabs, status line and
+// footer are drawn, or 0 before the terminal size is known
+func (m Model) viewportHeight() int {
+	if m.height <= 0 {
+		return 0
+	}
+	chrome := lipgloss.Height(fmt.Sprintf("%s\n%s\n%s%s",
+		clipWidth(renderHeader(), m.width),
+		renderTabs(m.tabs, m.activeTab, m.width),
+		renderStatusLine(m.errors, len(m.shellData.Histories)),
+		m.renderFooter()))
+	return max(m.height-chrome, 1)
+}
+
+// Give the viewport the active tab's content and size, so scrolling is
+// measured against what's on screen
+func (m *Model) syncViewport() {
+	m.viewport.Width = m.width
+	m.viewport.Height = m.viewportHeight()
+	m.viewport.SetContent(clipWidth(m.tabContent(), m.width))
+}
+
+// Whether msg scrolls the active tab. Views that use up and down to
+// select something take them first, and Commands pages with pgup and
+// pgdown instead.
+func (m Model) isScroll(msg tea.Msg) bool {
+	switch msg := msg.(type) {
+	case tea.MouseMsg:
+		return true
+	case tea.KeyMsg:
+		key := msg.String()
+		if m.searchInput.Focused() || !scrollKeys[key] {
+			return false
+		}
+		tab := m.tabs[m.activeTab]
+		if tab == "Tips" && len(m.tips) > 0 && (key == "up" || key == "down") {
+			return false
+		}
+		return tab != "Commands" || key == "up" || key == "down"
+	}
+	return false
+}
+
+// Scroll the active tab, moving the viewport without rendering the tab
+// again
+func (m Model) scroll(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if m.viewportHeight() == 0 || m.showDashboard() {
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.viewport, cmd = m.viewport.Update(msg)
+	return m, cmd
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"shell-analyzer/analyzer"
+)
+
+// A loaded model showing the Tech Profile tab, whose content is a field
+// the test can change behind the model's back
+func testScrollModel(t *testing.T) Model {
+	t.Helper()
+	discard := log.New(io.Discard, "", 0)
+	m := Model{
+		viewport:     viewport.New(80, 20),
+		tabs:         []string{"Overview", "Tech Profile", "Diagnostics"},
+		shellData:    analyzer.NewShellData(),
+		config:       analyzer.DefaultConfig(),
+		logger:       Logger{Info: discard, Error: discard},
+		searchInput:  newSearchInput(),
+		commandPages: newCommandPaginator(),
+	}
+	m.activeTab = slices.Index(m.tabs, "Tech Profile")
+
+	var lines []string
+	for i := range 100 {
+		lines = append(lines, fmt.Sprintf("line %d", i))
+	}
+	m.techProfileView = strings.Join(lines, "\n")
+
+	model, _ := m.Update(tea.WindowSizeMsg{Width: 80, Height: 30})
+	return model.(Model)
+}
+
+func TestMouseScrollKeepsContent(t *testing.T) {
+	m := testScrollModel(t)
+	if !strings.Contains(m.View(), "line 0") {
+		t.Fatalf("view before scrolling doesn't show the tab:\n%s", m.View())
+	}
+
+	// Scrolling must only move the viewport, not render the tab again
+	m.techProfileView = "rendered again"
+	model, _ := m.Update(tea.MouseMsg{Button: tea.MouseButtonWheelDown, Action: tea.MouseActionPress})
+	m = model.(Model)
+	if m.viewport.YOffset == 0 {
+		t.Error("mouse wheel didn't scroll")
+	}
+	if view := m.View(); strings.Contains(view, "rendered again") || !strings.Contains(view, "line ") {
+		t.Errorf("mouse wheel rendered the tab again:\n%s", view)
+	}
+
+	model, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m = model.(Model)
+	if strings.Contains(m.View(), "rendered again") {
+		t.Error("down key rendered the tab again")
+	}
+}
+
+func TestTabSwitchSetsContent(t *testing.T) {
+	m := testScrollModel(t)
+	model, _ := m.Update(tea.MouseMsg{Button: tea.MouseButtonWheelDown, Action: tea.MouseActionPress})
+	m = model.(Model)
+
+	// Coming back to the tab shows its current content from the top
+	m.techProfileView = "rendered again"
+	for range len(m.tabs) {
+		model, _ = m.Update(tea.KeyMsg{Type: tea.KeyTab})
+		m = model.(Model)
+	}
+	if m.tabs[m.activeTab] != "Tech Profile" {
+		t.Fatalf("on tab %q, want Tech Profile", m.tabs[m.activeTab])
+	}
+	if m.viewport.YOffset != 0 || !strings.Contains(m.View(), "rendered again") {
+		t.Errorf("tab switch didn't set the content, offset %d:\n%s", m.viewport.YOffset, m.View())
+	}
+}
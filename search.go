@@ -0,0 +1,191 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/paginator"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/gookit/color"
+
+	"shell-analyzer/analyzer"
+)
+
+const (
+	// Pause in typing before the list is filtered again, so huge
+	// histories aren't re-filtered on every keystroke
+	searchDebounce = 150 * time.Millisecond
+
+	// Commands shown per page, so only a slice of huge histories is
+	// rendered at a time
+	searchPageSize = 30
+)
+
+// commandCount is a distinct command line and how often it was run
+type commandCount struct {
+	Command string
+	Count   int
+}
+
+// Sent after a keystroke in the search box. Only the one for the latest
+// keystroke applies the filter.
+type searchDebounceMsg struct {
+	seq int
+}
+
+func newSearchInput() textinput.Model {
+	input := textinput.New()
+	input.Placeholder = "type to filter commands"
+	input.Prompt = "🔍 "
+	input.CharLimit = 200
+	// A blinking cursor would need its own stream of messages
+	input.Cursor.SetMode(cursor.CursorStatic)
+	return input
+}
+
+func newCommandPaginator() paginator.Model {
+	pages := paginator.New()
+	pages.Type = paginator.Arabic
+	pages.PerPage = searchPageSize
+	return pages
+}
+
+// Show a new list of matches, starting over from the first page
+func (m *Model) setSearchResults(results []commandCount) {
+	m.searchResults = results
+	m.commandPages.Page = 0
+	m.commandPages.SetTotalPages(len(results))
+}
+
+// Every distinct command across all shells, most run first
+func buildCommandList(data analyzer.ShellData) []commandCount {
+	counts := make(map[string]int)
+	for _, history := range data.Histories {
+		for _, entry := range history {
+			counts[entry.Command] += entry.Runs()
+		}
+	}
+
+	list := make([]commandCount, 0, len(counts))
+	for cmd, count := range counts {
+		list = append(list, commandCount{cmd, count})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Count != list[j].Count {
+			return list[i].Count > list[j].Count
+		}
+		return list[i].Command < list[j].Command
+	})
+	return list
+}
+
+// Commands containing every word of the query, ignoring case
+func filterCommands(list []commandCount, query string) []commandCount {
+	terms := strings.Fields(strings.ToLower(query))
+	if len(terms) == 0 {
+		return list
+	}
+
+	var matches []commandCount
+	for _, item := range list {
+		cmd := strings.ToLower(item.Command)
+		matched := true
+		for _, term := range terms {
+			if !strings.Contains(cmd, term) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			matches = append(matches, item)
+		}
+	}
+	return matches
+}
+
+// Color every occurrence of the query's words in cmd, ignoring case
+func highlightMatches(cmd, query string) string {
+	terms := strings.Fields(strings.ToLower(query))
+	lower := strings.ToLower(cmd)
+	// Lowercasing a few runes changes their length, which would misplace
+	// the highlights
+	if len(terms) == 0 || len(lower) != len(cmd) {
+		return cmd
+	}
+
+	matched := make([]bool, len(cmd))
+	for _, term := range terms {
+		for from := 0; ; {
+			i := strings.Index(lower[from:], term)
+			if i < 0 {
+				break
+			}
+			for j := from + i; j < from+i+len(term); j++ {
+				matched[j] = true
+			}
+			from += i + len(term)
+		}
+	}
+
+	var highlighted strings.Builder
+	for start := 0; start < len(cmd); {
+		end := start
+		for end < len(cmd) && matched[end] == matched[start] {
+			end++
+		}
+		if matched[start] {
+			highlighted.WriteString(color.Yellow.Sprint(cmd[start:end]))
+		} else {
+			highlighted.WriteString(cmd[start:end])
+		}
+		start = end
+	}
+	return highlighted.String()
+}
+
+// Wait for a pause in typing before filtering
+func debounceSearch(seq int) tea.Cmd {
+	return tea.Tick(searchDebounce, func(time.Time) tea.Msg {
+		return searchDebounceMsg{seq: seq}
+	})
+}
+
+func renderSearch(input textinput.Model, results []commandCount, query string, pages paginator.Model) string {
+	style := lipgloss.NewStyle().
+		BorderStyle(lipgloss.RoundedBorder()).
+		Padding(1)
+
+	var content strings.Builder
+	content.WriteString(color.Yellow.Sprintf("📜 Commands\n\n"))
+	content.WriteString(input.View() + "\n\n")
+
+	if len(results) == 0 {
+		if query == "" {
+			content.WriteString("No commands found\n")
+		} else {
+			content.WriteString(fmt.Sprintf("No commands match %q\n", query))
+		}
+		return style.Render(content.String())
+	}
+
+	content.WriteString(color.Gray.Sprintf("%d distinct commands", len(results)) + "\n")
+	start, end := pages.GetSliceBounds(len(results))
+	for _, item := range results[start:end] {
+		content.WriteString(fmt.Sprintf("%5d  %s\n", item.Count, highlightMatches(item.Command, query)))
+	}
+	if pages.TotalPages > 1 {
+		content.WriteString(color.Gray.Sprintf("page %s", pages.View()) + "\n")
+	}
+
+	if input.Focused() {
+		content.WriteString("\nenter to filter now • esc to stop typing")
+	} else {
+		content.WriteString("\n/ to filter • esc to clear • ←/→ or pgup/pgdown to change page")
+	}
+	return style.Render(content.String())
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/gookit/color"
+
+	"shell-analyzer/analyzer"
+)
+
+// Combine history-based and config-based hygiene into a single view
+func mergeSecretHygiene(data analyzer.ShellData) analyzer.SecretHygiene {
+	merged := analyzer.NewSecretHygiene()
+	history := data.Insights.Security.Secrets
+
+	merged.EnvFileSources = history.EnvFileSources
+	merged.DirenvUses = history.DirenvUses
+	merged.LeakedSecrets = history.LeakedSecrets
+	merged.LeakedSamples = history.LeakedSamples
+	for tool, count := range history.CredentialTools {
+		merged.CredentialTools[tool] += count
+	}
+
+	for _, config := range data.ShellConfigs {
+		secrets := config.Secrets
+		merged.EnvFileSources += secrets.EnvFileSources
+		merged.DirenvHooked = merged.DirenvHooked || secrets.DirenvHooked
+		merged.PlaintextSecrets = append(merged.PlaintextSecrets, secrets.PlaintextSecrets...)
+	}
+
+	sort.Strings(merged.PlaintextSecrets)
+	return merged
+}
+
+func generateSecretTips(hygiene analyzer.SecretHygiene) []string {
+	tips := []string{}
+	usesDirenv := hygiene.DirenvUses > 0 || hygiene.DirenvHooked
+
+	if hygiene.EnvFileSources > 0 && !usesDirenv {
+		tips = append(tips, fmt.Sprintf(
+			"You source .env files directly (%d times); consider direnv to load them per-directory and unload them when you leave",
+			hygiene.EnvFileSources))
+	}
+	if hygiene.EnvFileSources > 0 && usesDirenv {
+		tips = append(tips, fmt.Sprintf(
+			"You use direnv but still source .env files by hand %d times; let an .envrc do it instead",
+			hygiene.EnvFileSources))
+	}
+	if hygiene.DirenvUses > 0 && !hygiene.DirenvHooked {
+		tips = append(tips,
+			"You run direnv but no `direnv hook` was found in your shell config; without it .envrc files are never loaded automatically")
+	}
+	if hygiene.LeakedSecrets > 0 {
+		tips = append(tips, fmt.Sprintf(
+			"%d command(s) in your history look like they contain a secret; rotate those secrets, remove the lines from your history, and read secrets with `read -s` or a credential manager instead of pasting them",
+			hygiene.LeakedSecrets))
+	}
+	if len(hygiene.PlaintextSecrets) > 0 {
+		tips = append(tips, fmt.Sprintf(
+			"Secrets are exported in plain text from your shell config (%s); fetch them from pass or aws-vault instead",
+			strings.Join(hygiene.PlaintextSecrets, ", ")))
+	}
+	if len(hygiene.CredentialTools) == 0 && (hygiene.EnvFileSources > 0 || len(hygiene.PlaintextSecrets) > 0) {
+		tips = append(tips,
+			"No credential manager usage found; tools like pass, gopass or aws-vault keep secrets out of files and history")
+	}
+
+	return tips
+}
+
+func renderSecurity(data analyzer.ShellData) string {
+	style := lipgloss.NewStyle().
+		BorderStyle(lipgloss.RoundedBorder()).
+		Padding(1)
+
+	hygiene := mergeSecretHygiene(data)
+
+	var content strings.Builder
+	content.WriteString(color.Red.Sprintf("🔒 Security\n\n"))
+
+	// Secret Management
+	content.WriteString("🔑 Secret Management:\n")
+	content.WriteString(fmt.Sprintf("• .env files sourced: %d\n", hygiene.EnvFileSources))
+	direnvStatus := "not used"
+	if hygiene.DirenvHooked {
+		direnvStatus = "hooked into shell"
+	} else if hygiene.DirenvUses > 0 {
+		direnvStatus = fmt.Sprintf("used %d times, not hooked", hygiene.DirenvUses)
+	}
+	content.WriteString(fmt.Sprintf("• direnv: %s\n", direnvStatus))
+
+	if len(hygiene.CredentialTools) > 0 {
+		var tools []string
+		for tool := range hygiene.CredentialTools {
+			tools = append(tools, tool)
+		}
+		sort.Strings(tools)
+		content.WriteString("• Credential managers:\n")
+		for _, tool := range tools {
+			content.WriteString(fmt.Sprintf("    %s (%d uses)\n",
+				color.Cyan.Sprint(tool), hygiene.CredentialTools[tool]))
+		}
+	} else {
+		content.WriteString("• Credential managers: none detected\n")
+	}
+
+	content.WriteString(fmt.Sprintf("• Probable secrets in history: %d\n", hygiene.LeakedSecrets))
+	for _, sample := range hygiene.LeakedSamples {
+		content.WriteString(fmt.Sprintf("    %s\n", color.Red.Sprint(sample)))
+	}
+	orphans := data.Insights.Security.OrphanPasswords
+	content.WriteString(renderOrphanPasswords(orphans))
+	content.WriteString("\n")
+
+	// Aliases that expand to something dangerous
+	content.WriteString("⚠️  Dangerous Aliases:\n")
+	if len(data.Insights.Security.DangerousAliases) == 0 {
+		content.WriteString("No aliases expand to dangerous commands\n")
+	}
+	for _, alias := range data.Insights.Security.DangerousAliases {
+		content.WriteString(fmt.Sprintf("• %s → %s\n", color.Yellow.Sprint(alias.Name), alias.Expansion))
+		content.WriteString(color.Gray.Sprintf("    %s (%s)", alias.Reason, alias.Shell) + "\n")
+	}
+	content.WriteString("\n")
+
+	// Privilege escalation
+	content.WriteString("🛡️  Privileged Commands:\n")
+	content.WriteString(fmt.Sprintf("%d commands run through sudo or doas\n\n", data.Insights.Security.PrivilegedCommands))
+
+	// Tips
+	content.WriteString("💡 Tips:\n")
+	tips := generateSecretTips(hygiene)
+	if len(orphans) > 0 {
+		tips = append(tips, fmt.Sprintf(
+			"%d history entr(ies) look like passwords typed when a prompt wasn't waiting; they're left out of the analysis, but remove them from your history file and change those passwords",
+			len(orphans)))
+	}
+	if len(tips) > 0 {
+		for _, tip := range tips {
+			content.WriteString(fmt.Sprintf("• %s\n", color.Yellow.Sprint(tip)))
+		}
+	} else {
+		content.WriteString("No secret-management issues found\n")
+	}
+
+	return style.Render(content.String())
+}
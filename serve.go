@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"time"
+
+	"shell-analyzer/analyzer"
+)
+
+// Longest a client may take to send its request headers
+const serveHeaderTimeout = 10 * time.Second
+
+// Reports served in serve mode, by path
+var servedReports = []struct {
+	Path        string
+	Format      string
+	ContentType string
+}{
+	{"/", "html", "text/html; charset=utf-8"},
+	{"/report.json", "json", "application/json"},
+	{"/summary.json", "summary", "application/json"},
+}
+
+// Serve the reports from the cached analysis. Each response says when the
+// analysis ran in its Last-Modified header.
+func serveHandler(cache *analysisCache, cfg analyzer.Config) http.Handler {
+	mux := http.NewServeMux()
+	for _, report := range servedReports {
+		pattern := "GET " + report.Path
+		if report.Path == "/" {
+			pattern += "{$}"
+		}
+		mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+			data, taken := cache.get()
+
+			// Exported in full before anything is sent, so a failed export
+			// is a clean error rather than half a report
+			var body bytes.Buffer
+			if err := Export(data, report.Format, cfg, &body); err != nil {
+				http.Error(w, fmt.Sprintf("exporting %s: %v", report.Format, err), http.StatusInternalServerError)
+				return
+			}
+			w.Header().Set("Content-Type", report.ContentType)
+			w.Header().Set("Last-Modified", taken.UTC().Format(http.TimeFormat))
+			w.Write(body.Bytes())
+		})
+	}
+	return mux
+}
+
+// Serve the HTML, JSON and summary reports over HTTP on addr until the
+// server fails. The analysis re-runs at most once per interval.
+func serveReports(addr string, cfg analyzer.Config, interval time.Duration, analyze func() analyzer.ShellData) error {
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           serveHandler(newAnalysisCache(interval, analyze), cfg),
+		ReadHeaderTimeout: serveHeaderTimeout,
+	}
+	return server.ListenAndServe()
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"shell-analyzer/analyzer"
+)
+
+func TestServeHandler(t *testing.T) {
+	runs := 0
+	cache := newAnalysisCache(time.Hour, func() analyzer.ShellData {
+		runs++
+		data := analyzer.NewShellData()
+		data.Histories["bash"] = []analyzer.CommandEntry{{Command: "git status"}}
+		return data
+	})
+	server := httptest.NewServer(serveHandler(cache, analyzer.DefaultConfig()))
+	defer server.Close()
+
+	get := func(path string) (*http.Response, string) {
+		t.Helper()
+		resp, err := http.Get(server.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp, string(body)
+	}
+
+	resp, body := get("/")
+	if resp.StatusCode != http.StatusOK || !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/html") {
+		t.Errorf("/: %s %s", resp.Status, resp.Header.Get("Content-Type"))
+	}
+	if !strings.Contains(body, "shell-analyser-schema-version") || resp.Header.Get("Last-Modified") == "" {
+		t.Errorf("/ isn't the HTML report with its analysis time")
+	}
+
+	resp, body = get("/report.json")
+	var export struct {
+		SchemaVersion int                `json:"schema_version"`
+		Data          analyzer.ShellData `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(body), &export); err != nil {
+		t.Fatalf("/report.json: %v", err)
+	}
+	if export.SchemaVersion != analyzer.SchemaVersion || export.Data.Histories["bash"][0].Command != "git status" {
+		t.Errorf("/report.json = %+v", export)
+	}
+
+	if resp, _ = get("/summary.json"); resp.StatusCode != http.StatusOK {
+		t.Errorf("/summary.json: %s", resp.Status)
+	}
+	if resp, _ = get("/missing"); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("/missing: %s, want 404", resp.Status)
+	}
+
+	if runs != 1 {
+		t.Errorf("analysis ran %d times for requests within one interval, want once", runs)
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/gookit/color"
+)
+
+func renderStacks(usage map[string]int) string {
+	style := lipgloss.NewStyle().
+		BorderStyle(lipgloss.RoundedBorder()).
+		Padding(1)
+
+	var content strings.Builder
+	content.WriteString(color.Blue.Sprintf("🧱 Stacks\n\n"))
+
+	total := 0
+	for _, count := range usage {
+		total += count
+	}
+	if total == 0 {
+		content.WriteString("No stack usage found\n")
+		return style.Render(content.String())
+	}
+
+	var stacks []string
+	for stack := range usage {
+		stacks = append(stacks, stack)
+	}
+	sort.Slice(stacks, func(i, j int) bool {
+		if usage[stacks[i]] != usage[stacks[j]] {
+			return usage[stacks[i]] > usage[stacks[j]]
+		}
+		return stacks[i] < stacks[j]
+	})
+
+	for _, stack := range stacks {
+		share := float64(usage[stack]) / float64(total)
+		bars := int(share * 20)
+		barStr := strings.Repeat("█", bars) + strings.Repeat("░", 20-bars)
+		content.WriteString(fmt.Sprintf("%-15s %s %5.1f%% (%d)\n", stack, barStr, share*100, usage[stack]))
+	}
+
+	return style.Render(content.String())
+}
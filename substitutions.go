@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/gookit/color"
+
+	"shell-analyzer/analyzer"
+)
+
+const maxSubstitutionTools = 8
+
+func renderComplexity(score float64, stats analyzer.SubstitutionStats) string {
+	style := lipgloss.NewStyle().
+		BorderStyle(lipgloss.RoundedBorder()).
+		Padding(1)
+
+	var content strings.Builder
+	content.WriteString(color.Yellow.Sprintf("🧩 Complexity\n\n"))
+
+	if stats.Scanned == 0 {
+		content.WriteString("No commands found in your history\n")
+		return style.Render(content.String())
+	}
+
+	// The score gives half a point more for long commands, so it can pass 1
+	bars := int(min(score, 1) * 20)
+	barStr := strings.Repeat("█", bars) + strings.Repeat("░", 20-bars)
+	content.WriteString(fmt.Sprintf("%-20s %s %.1f%%\n", "Complexity score", barStr, score*100))
+	content.WriteString(color.Gray.Sprint("Commands with pipes, redirects, substitutions or subshells, plus half for each with several arguments") + "\n\n")
+
+	content.WriteString("💲 Command Substitution and Subshells:\n")
+	if stats.Total == 0 {
+		content.WriteString("None found in your history\n")
+		return style.Render(content.String())
+	}
+	content.WriteString(fmt.Sprintf("%d of %d commands (%.1f%%) use one\n",
+		stats.Using, stats.Scanned, float64(stats.Using)/float64(stats.Scanned)*100))
+	content.WriteString(fmt.Sprintf("$(...) %d · `...` %d · ( ... ) %d · nested %d\n",
+		stats.Dollar, stats.Backticks, stats.Subshells, stats.Nested))
+	if tools := topCounts(stats.Tools, maxSubstitutionTools); tools != "" {
+		content.WriteString(fmt.Sprintf("Most run inside: %s\n", tools))
+	}
+	if stats.Backticks > stats.Dollar {
+		content.WriteString(color.Gray.Sprint("Backticks need escaping to nest; $(...) nests and reads more easily") + "\n")
+	}
+
+	return style.Render(strings.TrimSuffix(content.String(), "\n"))
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+	"time"
+
+	"shell-analyzer/analyzer"
+)
+
+// Version of the summary structure, separate from the full export's
+// since the summary is meant to change rarely. Same rules as
+// SchemaVersion: bump it when a field is renamed, removed or changes
+// meaning.
+const summarySchemaVersion = 1
+
+const summaryTopTools = 5
+
+// Summary is the small, stable subset of the analysis written by
+// -summary-json for dashboards and badges. It's built field by field
+// rather than filtered from ShellData so internal changes can't leak in.
+type Summary struct {
+	SchemaVersion int           `json:"schema_version"`
+	Generated     time.Time     `json:"generated"`
+	PrimaryRole   string        `json:"primary_role"`
+	PrimaryShell  string        `json:"primary_shell"`
+	TopTools      []ToolRun     `json:"top_tools"`
+	PeakHours     []int         `json:"peak_hours"`
+	HealthScore   int           `json:"health_score"`
+	Counts        SummaryCounts `json:"counts"`
+}
+
+type ToolRun struct {
+	Name string `json:"name"`
+	Runs int    `json:"runs"`
+}
+
+type SummaryCounts struct {
+	Shells   int `json:"shells"`
+	Commands int `json:"commands"`
+	Aliases  int `json:"aliases"`
+	Plugins  int `json:"plugins"`
+}
+
+// A 0-100 score for shell setup hygiene: points come off for alias
+// conflicts, missing history options, secrets in config files and
+// secrets pasted into commands
+func healthScore(data analyzer.ShellData) int {
+	score := 100
+	penalty := func(count, each, most int) {
+		score -= min(count*each, most)
+	}
+
+	var conflicts, historyTips, plaintext int
+	for shell, config := range data.ShellConfigs {
+		conflicts += len(findAliasConflicts(config))
+		historyTips += len(generateHistoryOptionTips(shell, config.HistoryOptions))
+		plaintext += len(config.Secrets.PlaintextSecrets)
+	}
+	penalty(conflicts, 5, 20)
+	penalty(historyTips, 5, 20)
+	penalty(plaintext, 10, 30)
+	penalty(data.Insights.Security.Secrets.LeakedSecrets, 10, 30)
+
+	return max(score, 0)
+}
+
+// The most run commands across all shells, ignoring shell builtins
+func topTools(data analyzer.ShellData, limit int) []ToolRun {
+	counts := make(map[string]int)
+	for _, history := range data.Histories {
+		for _, entry := range history {
+			for _, cmd := range analyzer.SplitCommand(entry.Command) {
+				name := analyzer.BaseCommand(cmd)
+				if name == "" || analyzer.ShellBuiltins[name] {
+					continue
+				}
+				counts[name] += entry.Runs()
+			}
+		}
+	}
+
+	tools := []ToolRun{}
+	for name, runs := range counts {
+		tools = append(tools, ToolRun{name, runs})
+	}
+	sort.Slice(tools, func(i, j int) bool {
+		if tools[i].Runs != tools[j].Runs {
+			return tools[i].Runs > tools[j].Runs
+		}
+		return tools[i].Name < tools[j].Name
+	})
+	if len(tools) > limit {
+		tools = tools[:limit]
+	}
+	return tools
+}
+
+func newSummary(data analyzer.ShellData, now time.Time) Summary {
+	summary := Summary{
+		SchemaVersion: summarySchemaVersion,
+		Generated:     now,
+		PrimaryRole:   data.Insights.TechnicalProfile.PrimaryRole,
+		PrimaryShell:  data.PrimaryShell,
+		TopTools:      topTools(data, summaryTopTools),
+		PeakHours:     data.Insights.WorkPatterns.PeakHours,
+		HealthScore:   healthScore(data),
+	}
+	// Keep empty lists as [] so consumers don't need to handle null
+	if summary.PeakHours == nil {
+		summary.PeakHours = []int{}
+	}
+
+	shells := make(map[string]bool)
+	for shell, history := range data.Histories {
+		shells[shell] = true
+		for _, entry := range history {
+			summary.Counts.Commands += entry.Runs()
+		}
+	}
+	for shell, config := range data.ShellConfigs {
+		shells[shell] = true
+		summary.Counts.Aliases += len(config.Aliases)
+		summary.Counts.Plugins += len(config.Plugins)
+	}
+	summary.Counts.Shells = len(shells)
+
+	return summary
+}
+
+// Write the compact summary as JSON
+func exportSummary(data analyzer.ShellData, w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(newSummary(data, time.Now()))
+}
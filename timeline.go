@@ -0,0 +1,23 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"shell-analyzer/analyzer"
+)
+
+func renderSessions(stats analyzer.SessionStats) string {
+	if stats.Sessions == 0 {
+		return ""
+	}
+
+	var content strings.Builder
+	content.WriteString("🧵 Sessions (all shells):\n")
+	content.WriteString(fmt.Sprintf("%d session(s), %.0f commands and %s each on average\n",
+		stats.Sessions, float64(stats.Commands)/float64(stats.Sessions), stats.AverageLength().Round(time.Minute)))
+	content.WriteString(fmt.Sprintf("%d used more than one shell, switching %d times\n\n",
+		stats.CrossShell, stats.Switches))
+	return content.String()
+}
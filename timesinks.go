@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/gookit/color"
+
+	"shell-analyzer/analyzer"
+)
+
+// Render the time sinks as their own box, or nothing when the history
+// has no duration data to rank
+func renderTimeSinks(sinks []analyzer.TimeSink) string {
+	if len(sinks) == 0 {
+		return ""
+	}
+
+	style := lipgloss.NewStyle().
+		BorderStyle(lipgloss.RoundedBorder()).
+		Padding(1)
+
+	var content strings.Builder
+	content.WriteString("⏳ Time Sinks:\n")
+	for i, sink := range sinks {
+		if i > 0 {
+			content.WriteString("\n")
+		}
+		content.WriteString(fmt.Sprintf("%-20s %3d runs, avg %s, total %s",
+			sink.Command, sink.Count,
+			sink.Average.Round(time.Second), sink.Total.Round(time.Second)))
+		if sink.Tip != "" {
+			content.WriteString("\n  💡 " + color.Yellow.Sprintf(
+				"your `%s` averages %s — %s", sink.Command, sink.Average.Round(time.Second), sink.Tip))
+		}
+	}
+
+	return style.Render(content.String())
+}
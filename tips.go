@@ -0,0 +1,303 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/gookit/color"
+
+	"shell-analyzer/analyzer"
+)
+
+const (
+	minAliasSuggestionUses = 10
+	maxAliasSuggestions    = 10
+)
+
+// Tip is a suggestion shown in the Tips view. Tips about a shell's config
+// carry the file to edit so it can be opened straight from the TUI.
+type Tip struct {
+	Text       string
+	ConfigPath string
+}
+
+type editorFinishedMsg struct {
+	path string
+	err  error
+}
+
+// The config file a shell reads at startup: the first known one that
+// exists, or the usual one so the editor creates it
+func primaryConfigFile(shell string, config analyzer.ShellConfig) string {
+	paths := analyzer.DefaultConfigPaths()[shell]
+	for _, path := range paths {
+		if info, ok := config.ConfigFiles[path]; ok {
+			if stat, err := os.Stat(info.Path); err == nil && !stat.IsDir() {
+				return info.Path
+			}
+		}
+	}
+	if len(paths) == 0 {
+		return ""
+	}
+	return config.ExpandPath(paths[0])
+}
+
+// AliasSuggestion is a frequent command worth giving a short alias
+type AliasSuggestion struct {
+	Name    string
+	Command string
+	Runs    int
+}
+
+// Words that are safe to use unquoted in any shell. ~ is included so a
+// leading ~/ still expands.
+var plainWordRegex = regexp.MustCompile(`^[A-Za-z0-9_./:=@%+,~-]+$`)
+
+// A word of a command as shell code, quoted when it needs to be
+func quoteWord(shell, word string) string {
+	if plainWordRegex.MatchString(word) {
+		return word
+	}
+	return shellQuote(shell, word)
+}
+
+// Lowercase letters, digits and underscores from a word, the only
+// characters suggested alias names use
+func aliasNameChars(word string) string {
+	var chars strings.Builder
+	for _, c := range strings.ToLower(word) {
+		if c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '_' {
+			chars.WriteRune(c)
+		}
+	}
+	return chars.String()
+}
+
+// Suggest a short alias name from the initials of a command's words, e.g.
+// "gst" for "git status" when "gs" is taken: more of the last word is used
+// until a name is free. Returns "" when every candidate is taken.
+func suggestAliasName(words []string, taken func(string) bool) string {
+	var initials strings.Builder
+	for _, word := range words[:len(words)-1] {
+		if chars := aliasNameChars(word); chars != "" {
+			initials.WriteByte(chars[0])
+		}
+	}
+
+	last := aliasNameChars(words[len(words)-1])
+	for n := 1; n <= len(last); n++ {
+		if name := initials.String() + last[:n]; !taken(name) {
+			return name
+		}
+	}
+	return ""
+}
+
+// Frequent two-word commands in a shell's history that have no alias yet,
+// named so they don't clash with existing aliases, builtins, commands on
+// PATH or each other. Commands are quoted for shell.
+func suggestAliases(shell string, history []analyzer.CommandEntry, config analyzer.ShellConfig) []AliasSuggestion {
+	// Compared by words, since the config may quote them differently
+	aliased := make(map[string]bool)
+	for _, expansion := range config.Aliases {
+		words, _ := analyzer.ShellWords(expansion)
+		aliased[strings.Join(words, "\x00")] = true
+	}
+	patterns := make(map[string]int)
+	patternWords := make(map[string][]string)
+	for _, entry := range history {
+		words, complete := analyzer.ShellWords(entry.Command)
+		if !complete || len(words) < 2 || words[1] == "" || strings.HasPrefix(words[1], "-") {
+			continue
+		}
+		words = words[:2]
+		// Quoting an expansion would change what it means
+		if strings.ContainsAny(strings.Join(words, " "), "$`") || aliased[strings.Join(words, "\x00")] {
+			continue
+		}
+		pattern := quoteWord(shell, words[0]) + " " + quoteWord(shell, words[1])
+		patterns[pattern] += entry.Runs()
+		patternWords[pattern] = words
+	}
+	var frequent []string
+	for pattern, count := range patterns {
+		if count > minAliasSuggestionUses {
+			frequent = append(frequent, pattern)
+		}
+	}
+	sort.Slice(frequent, func(i, j int) bool {
+		if patterns[frequent[i]] != patterns[frequent[j]] {
+			return patterns[frequent[i]] > patterns[frequent[j]]
+		}
+		return frequent[i] < frequent[j]
+	})
+
+	suggested := make(map[string]bool)
+	taken := func(name string) bool {
+		_, exists := config.Aliases[name]
+		return exists || suggested[name] || analyzer.ShellBuiltins[name] || analyzer.CheckToolInstalled(name)
+	}
+	var suggestions []AliasSuggestion
+	for _, pattern := range frequent {
+		if len(suggestions) == maxAliasSuggestions {
+			break
+		}
+		name := suggestAliasName(patternWords[pattern], taken)
+		if name == "" {
+			continue
+		}
+		suggested[name] = true
+		suggestions = append(suggestions, AliasSuggestion{name, pattern, patterns[pattern]})
+	}
+	return suggestions
+}
+
+// Specific changes to make, each tied to the config file it goes in when
+// there is one. Broader suggestions are in the Recommendations tab.
+func generateTips(data analyzer.ShellData, rules []analyzer.CompanionRule) []Tip {
+	var shells []string
+	for shell := range data.ShellConfigs {
+		shells = append(shells, shell)
+	}
+
+	// Tips for the primary shell matter most, so they come first
+	var tips []Tip
+	for _, shell := range primaryFirst(shells, data.PrimaryShell) {
+		config := data.ShellConfigs[shell]
+		path := primaryConfigFile(shell, config)
+
+		// Frequent commands that don't have an alias yet
+		for _, suggestion := range suggestAliases(shell, data.Histories[shell], config) {
+			tips = append(tips, Tip{
+				Text: fmt.Sprintf("You ran `%s` %d times in %s; add an alias like `%s`",
+					suggestion.Command, suggestion.Runs, shell, aliasDefinition(shell, suggestion)),
+				ConfigPath: path,
+			})
+		}
+
+		for _, text := range generateHistoryOptionTips(shell, config.HistoryOptions) {
+			tips = append(tips, Tip{Text: text, ConfigPath: path})
+		}
+	}
+
+	// Companion tools that aren't tied to one shell's config
+	for _, text := range generateCompanionTips(data, rules) {
+		tips = append(tips, Tip{Text: text})
+	}
+
+	return tips
+}
+
+// Open a file in the user's editor, suspending the TUI until it exits
+func openInEditor(path string) tea.Cmd {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+		if runtime.GOOS == "windows" {
+			editor = "notepad"
+		}
+	}
+
+	// Editors are often configured with arguments, like "code --wait".
+	// No timeout here: the user decides when they're done editing.
+	args := append(strings.Fields(editor), path)
+	return tea.ExecProcess(exec.Command(args[0], args[1:]...), func(err error) tea.Msg {
+		return editorFinishedMsg{path: path, err: err}
+	})
+}
+
+func renderTips(tips []Tip, selected int) string {
+	style := lipgloss.NewStyle().
+		BorderStyle(lipgloss.RoundedBorder()).
+		Padding(1)
+
+	var content strings.Builder
+	content.WriteString(color.Yellow.Sprintf("💡 Tips\n\n"))
+
+	if len(tips) == 0 {
+		content.WriteString("No tips, your setup looks good\n")
+		return style.Render(content.String())
+	}
+
+	for i, tip := range tips {
+		marker := "  "
+		text := tip.Text
+		if i == selected {
+			marker = color.Cyan.Sprint("▸ ")
+			text = color.Bold.Sprint(text)
+		}
+		content.WriteString(marker + text + "\n")
+		if i == selected && tip.ConfigPath != "" {
+			content.WriteString(color.Gray.Sprintf("  press 'e' to edit %s\n", tip.ConfigPath))
+		}
+	}
+	content.WriteString("\n↑/↓ select a tip")
+
+	return style.Render(content.String())
+}
+
+// Config and workflow suggestions across all shells, with how complex the
+// commands typed are. Commands the Tips tab already suggests an alias for
+// aren't repeated here.
+func renderRecommendations(data analyzer.ShellData) string {
+	style := lipgloss.NewStyle().
+		BorderStyle(lipgloss.RoundedBorder()).
+		Padding(1)
+
+	var content strings.Builder
+	content.WriteString(color.Yellow.Sprintf("🧭 Recommendations\n\n"))
+	content.WriteString(fmt.Sprintf("Command complexity score: %.1f%%\n\n", data.Insights.Complexity*100))
+
+	// Each suggestion is listed once, even when more than one generator
+	// comes up with it
+	seen := make(map[string]bool)
+	section := func(title string, items []string) {
+		var fresh []string
+		for _, item := range items {
+			if !seen[item] {
+				seen[item] = true
+				fresh = append(fresh, item)
+			}
+		}
+		if len(fresh) == 0 {
+			return
+		}
+		content.WriteString(title + "\n")
+		for _, item := range fresh {
+			content.WriteString("• " + item + "\n")
+		}
+		content.WriteString("\n")
+	}
+
+	recommendations := generateRecommendations(&data)
+	sort.Strings(recommendations)
+	section("⚙️  Config:", recommendations)
+	section("🔁 Workflow:", generateWorkflowTips(&data))
+
+	if len(seen) == 0 {
+		content.WriteString("No recommendations, your setup looks good\n")
+	}
+
+	return style.Render(strings.TrimRight(content.String(), "\n"))
+}
+
+// Describe why the editor couldn't be used
+func editorError(path string, err error) error {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return fmt.Errorf("editor exited with an error while editing %s: %w", path, err)
+	}
+	return fmt.Errorf("could not open %s in an editor (set $EDITOR): %w", path, err)
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	"shell-analyzer/analyzer"
+)
+
+func TestSuggestAliasName(t *testing.T) {
+	takenNames := func(names ...string) func(string) bool {
+		return func(name string) bool { return slices.Contains(names, name) }
+	}
+	tests := []struct {
+		words []string
+		taken []string
+		want  string
+	}{
+		{[]string{"git", "status"}, nil, "gs"},
+		{[]string{"git", "status"}, []string{"gs"}, "gst"},
+		{[]string{"cd", "My Documents"}, nil, "cm"},
+		{[]string{"./build.sh", "run"}, nil, "br"},
+		{[]string{"kubectl", "get-pods"}, []string{"kg", "kge", "kget"}, "kgetp"},
+		{[]string{"git", "st"}, []string{"gs", "gst"}, ""},
+		{[]string{"cd", "$$$"}, nil, ""},
+	}
+	for _, test := range tests {
+		if got := suggestAliasName(test.words, takenNames(test.taken...)); got != test.want {
+			t.Errorf("suggestAliasName(%q) with %v taken = %q, want %q", test.words, test.taken, got, test.want)
+		}
+	}
+}
+
+func TestSuggestAliases(t *testing.T) {
+	// A command on PATH named like a suggestion, as ghostscript's gs is
+	bin := t.TempDir()
+	if err := os.WriteFile(filepath.Join(bin, "gs"), []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin)
+
+	var history []analyzer.CommandEntry
+	for _, cmd := range []string{
+		"git status",
+		`cd "My Documents"`,
+		`cd "$HOME/src"`,
+		"docker compose up",
+		"ls -la",
+	} {
+		for range minAliasSuggestionUses + 1 {
+			history = append(history, analyzer.CommandEntry{Command: cmd})
+		}
+	}
+	config := analyzer.ShellConfig{Aliases: map[string]string{"dcu": `docker "compose"`}}
+
+	suggestions := suggestAliases("bash", history, config)
+	got := make(map[string]string)
+	for _, suggestion := range suggestions {
+		got[suggestion.Command] = suggestion.Name
+	}
+	want := map[string]string{"git status": "gst", "cd 'My Documents'": "cm"}
+	if len(got) != len(want) {
+		t.Errorf("suggestions = %v, want %v", got, want)
+	}
+	for cmd, name := range want {
+		if got[cmd] != name {
+			t.Errorf("alias for %q = %q, want %q", cmd, got[cmd], name)
+		}
+	}
+
+	validName := regexp.MustCompile(`^[a-z0-9_]+$`)
+	for _, suggestion := range suggestions {
+		if !validName.MatchString(suggestion.Name) {
+			t.Errorf("alias name %q has characters outside [a-z0-9_]", suggestion.Name)
+		}
+	}
+}
+
+func TestTipsAndRecommendationsDiffer(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+
+	data := analyzer.NewShellData()
+	for _, cmd := range []string{"git status", "ls -la"} {
+		for range minAliasSuggestionUses + 1 {
+			data.Histories["zsh"] = append(data.Histories["zsh"], analyzer.CommandEntry{Command: cmd})
+		}
+	}
+	data.ShellConfigs["zsh"] = analyzer.ShellConfig{Aliases: map[string]string{}}
+
+	tips := generateTips(data, nil)
+	recommendations := renderRecommendations(data)
+	var tipTexts []string
+	for _, tip := range tips {
+		tipTexts = append(tipTexts, tip.Text)
+		if strings.Contains(recommendations, tip.Text) {
+			t.Errorf("tip %q is also a recommendation", tip.Text)
+		}
+	}
+	allTips := strings.Join(tipTexts, "\n")
+
+	if !strings.Contains(allTips, "`git status`") {
+		t.Errorf("tips = %q, want an alias for git status", tipTexts)
+	}
+	if strings.Contains(recommendations, "'git status'") {
+		t.Errorf("recommendations repeat the git status alias tip:\n%s", recommendations)
+	}
+	if !strings.Contains(recommendations, "'ls -la'") {
+		t.Errorf("recommendations are missing ls -la, which Tips doesn't suggest:\n%s", recommendations)
+	}
+	for _, text := range []string{"Consider adding more aliases", "Explore popular zsh plugins"} {
+		if !strings.Contains(recommendations, text) || strings.Contains(allTips, text) {
+			t.Errorf("%q should only be a recommendation", text)
+		}
+	}
+}
+
+func TestPrimaryConfigFileInHome(t *testing.T) {
+	home := writeTestHome(t, map[string]string{
+		".zshrc":       "alias gs='git status'\n",
+		".zsh_history": "gs\n",
+	})
+	data, err := analyzer.Analyze(analyzer.Options{HomeDir: home, Shells: []string{"zsh"}, NoProbe: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := primaryConfigFile("zsh", data.ShellConfigs["zsh"]), filepath.Join(home, ".zshrc"); got != want {
+		t.Errorf("primaryConfigFile = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"shell-analyzer/analyzer"
+)
+
+func TestWrappedCommandsInToolViews(t *testing.T) {
+	data := analyzer.NewShellData()
+	for _, cmd := range []string{"sudo docker ps", "sudo docker ps -a", "docker images", "sudo ssh host", "sudo -i"} {
+		data.Histories["bash"] = append(data.Histories["bash"], analyzer.CommandEntry{Command: cmd})
+	}
+
+	if network := renderNetworkActivity(data.Histories["bash"]); !strings.Contains(network, "ssh 1") {
+		t.Errorf("network activity = %q, want sudo ssh counted", network)
+	}
+	for _, tool := range topTools(data, 10) {
+		if tool.Name == "docker" && tool.Runs != 3 {
+			t.Errorf("top tools count docker %d times, want 3", tool.Runs)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"shell-analyzer/analyzer"
+)
+
+// The paths, sizes and modification times of the history files and shell
+// configs an analysis reads, which change when any of those files does
+func watchedFilesState(opts analyzer.Options) string {
+	var state strings.Builder
+	for _, path := range analyzer.InputFiles(opts) {
+		info, err := os.Stat(path)
+		if err != nil {
+			fmt.Fprintf(&state, "%s missing\n", path)
+			continue
+		}
+		fmt.Fprintf(&state, "%s %d %d\n", path, info.Size(), info.ModTime().UnixNano())
+	}
+	return state.String()
+}
+
+// Print the text report, then print it again whenever a history or config
+// file has changed, checking once per interval until stop is closed. A
+// burst of changes within one interval is analyzed once. opts.Config
+// holds the section to print, if any.
+func watchReports(opts analyzer.Options, interval time.Duration, w io.Writer, stop <-chan struct{}) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	last := ""
+	for {
+		if state := watchedFilesState(opts); state != last {
+			last = state
+			data, err := analyzer.Analyze(opts)
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(w, "── %s ──\n\n", time.Now().Format(time.RFC1123))
+			if opts.Config != nil && opts.Config.Section != "" {
+				err = exportText(data, opts.Config.Section, w)
+			} else {
+				err = writePlainReport(data, w)
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		select {
+		case <-stop:
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"shell-analyzer/analyzer"
+)
+
+// A writer safe to read while watchReports writes to it
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf strings.Builder
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// Wait until the output holds n reports
+func waitForReports(t *testing.T, out *syncBuffer, n int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for strings.Count(out.String(), "── ") < n {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for report %d:\n%s", n, out.String())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestWatchReportsReanalyzesOnChange(t *testing.T) {
+	home := t.TempDir()
+	history := filepath.Join(home, ".bash_history")
+	if err := os.WriteFile(history, []byte("git status\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	opts := analyzer.Options{HomeDir: home, NoProbe: true, KeepEntries: true}
+
+	var out syncBuffer
+	stop := make(chan struct{})
+	done := make(chan error)
+	go func() { done <- watchReports(opts, 20*time.Millisecond, &out, stop) }()
+
+	waitForReports(t, &out, 1)
+	// Unchanged files aren't analyzed again
+	time.Sleep(100 * time.Millisecond)
+	if n := strings.Count(out.String(), "── "); n != 1 {
+		t.Errorf("%d reports without any change, want 1", n)
+	}
+
+	if err := os.WriteFile(history, []byte("git status\nmake test\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(history, later, later); err != nil {
+		t.Fatal(err)
+	}
+	waitForReports(t, &out, 2)
+
+	close(stop)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+}
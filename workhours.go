@@ -0,0 +1,24 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"shell-analyzer/analyzer"
+)
+
+// Omitted when no command has a timestamp
+func renderWorkHours(stats analyzer.WorkHoursStats) string {
+	if stats.Total() == 0 {
+		return ""
+	}
+
+	var content strings.Builder
+	content.WriteString(fmt.Sprintf("🕘 Work Hours (%s):\n", stats.Definition))
+	ratio := float64(stats.InHours) / float64(stats.Total())
+	bars := int(ratio * 20)
+	barStr := strings.Repeat("█", bars) + strings.Repeat("░", 20-bars)
+	content.WriteString(fmt.Sprintf("%-20s %s %.1f%%\n", "In hours", barStr, ratio*100))
+	content.WriteString(fmt.Sprintf("%d commands in work hours, %d outside them\n\n", stats.InHours, stats.OffHours))
+	return content.String()
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gookit/color"
+
+	"shell-analyzer/analyzer"
+)
+
+const maxWorkplaceTargets = 5
+
+func renderWorkplaces(stats analyzer.WorkplaceStats) string {
+	total := stats.Total()
+	if total == 0 {
+		return ""
+	}
+
+	var content strings.Builder
+	content.WriteString("🌍 Where You Work:\n")
+	for _, place := range []struct {
+		name  string
+		count int
+	}{
+		{"Local", stats.Local},
+		{"Containers", stats.Container},
+		{"Remote hosts", stats.Remote},
+	} {
+		ratio := float64(place.count) / float64(total)
+		bars := int(ratio * 20)
+		barStr := strings.Repeat("█", bars) + strings.Repeat("░", 20-bars)
+		content.WriteString(fmt.Sprintf("%-20s %s %.1f%%\n", place.name, barStr, ratio*100))
+	}
+	if containers := topCounts(stats.Containers, maxWorkplaceTargets); containers != "" {
+		content.WriteString(fmt.Sprintf("Containers: %s\n", containers))
+	}
+	if hosts := topCounts(stats.Hosts, maxWorkplaceTargets); hosts != "" {
+		content.WriteString(fmt.Sprintf("Hosts: %s\n", hosts))
+	}
+	content.WriteString(color.Gray.Sprint("Counts the commands that enter a container or host; what you type once inside isn't in this history") + "\n\n")
+	return content.String()
+}